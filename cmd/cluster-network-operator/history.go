@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/cluster-network-operator/pkg/controller/operconfig"
+)
+
+// newReconcileHistoryCommand returns a Command that prints the reconcile
+// history stored in the reconcile-history ConfigMap.
+func newReconcileHistoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reconcile-history",
+		Short: "Print the history of the reconciles of the operator configuration",
+	}
+
+	var configMapPath string
+	var output string
+
+	flags := cmd.Flags()
+	flags.StringVar(&configMapPath, "configmap", "-", "path to the openshift-network-operator/reconcile-history ConfigMap (YAML or JSON), or - for stdin")
+	flags.StringVarP(&output, "output", "o", "table", "output format: table, wide or json")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var b []byte
+		var err error
+		if configMapPath == "-" {
+			b, err = io.ReadAll(os.Stdin)
+		} else {
+			b, err = os.ReadFile(configMapPath)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", configMapPath, err)
+		}
+		cm := &corev1.ConfigMap{}
+		if err := yaml.Unmarshal(b, cm); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", configMapPath, err)
+		}
+		history, err := operconfig.ReconcileHistoryFromConfigMap(cm)
+		if err != nil {
+			return err
+		}
+
+		switch output {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(history)
+		case "table", "wide":
+			return printReconcileHistory(os.Stdout, history, output == "wide")
+		default:
+			return fmt.Errorf("unknown output format %q", output)
+		}
+	}
+	return cmd
+}
+
+// printReconcileHistory writes history as a table, most recent reconcile
+// last. In wide mode, the objects that were changed are listed in an extra
+// column, one per line.
+func printReconcileHistory(w io.Writer, history []operconfig.ReconcileRecord, wide bool) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	header := "TIME\tDURATION\tGENERATION\tSPEC\tRENDER\tAPPLIED\tCHANGED\tFAILED\tREPEATS\tERROR"
+	if wide {
+		header += "\tCHANGED OBJECTS"
+	}
+	fmt.Fprintln(tw, header)
+	for _, record := range history {
+		notes := ""
+		if record.DryRun {
+			notes = " (dry run)"
+		} else if record.RolledBack {
+			notes = " (rolled back)"
+		}
+		repeats := ""
+		if record.Repeats > 0 {
+			repeats = fmt.Sprintf("%d until %s", record.Repeats, record.LastTimestamp.UTC().Format(time.RFC3339))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s%s\t%s\t%d\t%d\t%d\t%s\t%s",
+			record.Timestamp.UTC().Format(time.RFC3339), record.Duration.Duration, record.Generation,
+			record.SpecHash, notes, record.RenderHash, record.Applied, record.Changed, record.Failed,
+			repeats, record.Error)
+		if !wide {
+			fmt.Fprintln(tw)
+			continue
+		}
+		objects := record.ChangedObjects
+		if record.Changed > len(objects) {
+			objects = append(objects, fmt.Sprintf("... and %d more", record.Changed-len(objects)))
+		}
+		if len(objects) == 0 {
+			fmt.Fprintln(tw, "\t")
+		}
+		for i, obj := range objects {
+			if i > 0 {
+				fmt.Fprint(tw, "\t\t\t\t\t\t\t\t\t")
+			}
+			fmt.Fprintf(tw, "\t%s\n", obj)
+		}
+	}
+	return tw.Flush()
+}
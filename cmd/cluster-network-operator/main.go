@@ -46,8 +46,10 @@ func newNetworkOperatorCommand() *cobra.Command {
 	}
 	var extraClusters *map[string]string
 	var inClusterClientName *string
+	var metricsBindAddress *string
+	var webhookPort *int
 	cmdcfg := controllercmd.NewControllerCommandConfig("network-operator", version.Get(), func(ctx context.Context, controllerConfig *controllercmd.ControllerContext) error {
-		return operator.RunOperator(ctx, controllerConfig, *inClusterClientName, *extraClusters)
+		return operator.RunOperator(ctx, controllerConfig, *inClusterClientName, *extraClusters, *metricsBindAddress, *webhookPort)
 	}, clock.RealClock{})
 
 	cmd2 := cmdcfg.NewCommand()
@@ -55,9 +57,17 @@ func newNetworkOperatorCommand() *cobra.Command {
 	cmd2.Short = "Start the cluster network operator"
 	extraClusters = cmd2.Flags().StringToString("extra-clusters", nil, "extra clusters, pairs of cluster name and kubeconfig path")
 	inClusterClientName = cmd2.Flags().String("in-cluster-client-name", names.DefaultClusterName, "client name for in-cluster config(service account or kubeconfig)")
+	metricsBindAddress = cmd2.Flags().String("metrics-bind-address", "0", "address the controller metrics endpoint binds to, or 0 to disable it")
+	webhookPort = cmd2.Flags().Int("webhook-port", 0, "port the admission webhooks are served on, or 0 to disable them")
 	cmd.AddCommand(cmd2)
 
 	cmd.AddCommand(newMTUProberCommand())
 
+	cmd.AddCommand(newRenderCommand())
+
+	cmd.AddCommand(newPreviewChangeCommand())
+
+	cmd.AddCommand(newReconcileHistoryCommand())
+
 	return cmd
 }
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
+	"github.com/openshift/cluster-network-operator/pkg/controller/operconfig"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+	"github.com/openshift/cluster-network-operator/pkg/network"
+	"github.com/openshift/cluster-network-operator/pkg/platform"
+	"github.com/openshift/cluster-network-operator/pkg/util"
+	"github.com/openshift/library-go/pkg/config/client"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+)
+
+// newPreviewChangeCommand returns a Command that tells whether the operator
+// would apply a proposed configuration on top of the applied one, and how
+// disruptive the change would be. The verdict is written to stdout as JSON.
+func newPreviewChangeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preview-change",
+		Short: "Check whether a proposed operator configuration is a safe change from the applied configuration",
+	}
+
+	var kubeconfig string
+	var operConfigPath string
+	var appliedConfigPath string
+	var bootstrapResultPath string
+	var hostMTU int
+
+	flags := cmd.Flags()
+	flags.StringVar(&kubeconfig, "kubeconfig", "", "path to the kubeconfig of the cluster to read the applied configuration from (defaults to $KUBECONFIG, then the in-cluster config)")
+	flags.StringVar(&operConfigPath, "operator-config", "", "path to the proposed Network.operator.openshift.io object (YAML)")
+	flags.StringVar(&appliedConfigPath, "applied-config", "", "path to the applied NetworkSpec, as stored in the applied-cluster ConfigMap (JSON); overrides the one read from the cluster")
+	flags.StringVar(&bootstrapResultPath, "bootstrap-result", "", "path to a synthetic bootstrap result (YAML), for the platform checks; overrides the infrastructure status read from the cluster")
+	flags.IntVar(&hostMTU, "host-mtu", 1500, "the node MTU used when the configuration does not specify one; defaults to the MTU probed in the cluster, if any")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if operConfigPath == "" {
+			return fmt.Errorf("--operator-config is required")
+		}
+
+		operConfig := &operv1.Network{}
+		if err := readYAMLFile(operConfigPath, operConfig); err != nil {
+			return err
+		}
+
+		// Only connect to the cluster for the inputs that were not given as files.
+		var cli cnoclient.Client
+		if appliedConfigPath == "" || bootstrapResultPath == "" {
+			var err error
+			if cli, err = newPreviewClient(kubeconfig); err != nil {
+				return fmt.Errorf("failed to connect to the cluster, the --applied-config and --bootstrap-result files are required to preview offline: %w", err)
+			}
+		}
+
+		var applied *operv1.NetworkSpec
+		if appliedConfigPath != "" {
+			applied = &operv1.NetworkSpec{}
+			if err := readYAMLFile(appliedConfigPath, applied); err != nil {
+				return err
+			}
+		}
+		var infraStatus *bootstrap.InfraStatus
+		if bootstrapResultPath != "" {
+			bootstrapResult := &bootstrap.BootstrapResult{}
+			if err := readYAMLFile(bootstrapResultPath, bootstrapResult); err != nil {
+				return err
+			}
+			infraStatus = &bootstrapResult.Infra
+		}
+		if !flags.Changed("host-mtu") {
+			hostMTU = 0
+		}
+
+		preview, err := previewChange(context.Background(), cli, operConfig, applied, infraStatus, hostMTU)
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(preview)
+	}
+	return cmd
+}
+
+// newPreviewClient returns a client for the cluster of the kubeconfig, or
+// for the cluster the command runs in if there is none.
+func newPreviewClient(kubeconfig string) (cnoclient.Client, error) {
+	if kubeconfig == "" {
+		kubeconfig = os.Getenv("KUBECONFIG")
+	}
+	cfg, err := client.GetKubeConfigOrInClusterConfig(kubeconfig, nil)
+	if err != nil {
+		return nil, err
+	}
+	protoCfg := rest.CopyConfig(cfg)
+	protoCfg.AcceptContentTypes = "application/vnd.kubernetes.protobuf,application/json"
+	protoCfg.ContentType = "application/vnd.kubernetes.protobuf"
+	cc, err := cnoclient.NewClusterClient(cfg, protoCfg)
+	if err != nil {
+		return nil, err
+	}
+	return cnoclient.NewSingleClusterClient(cc), nil
+}
+
+// previewChange classifies the change from the applied configuration to
+// operConfig. The applied configuration, the infrastructure status and the
+// host MTU that are nil or 0 are read from the cluster through cli, like the
+// operator does.
+func previewChange(ctx context.Context, cli cnoclient.Client, operConfig *operv1.Network, applied *operv1.NetworkSpec, infraStatus *bootstrap.InfraStatus, hostMTU int) (*network.ChangePreview, error) {
+	name := operConfig.Name
+	if name == "" {
+		name = names.OPERATOR_CONFIG
+	}
+
+	if applied == nil {
+		var err error
+		if applied, err = operconfig.GetAppliedConfiguration(ctx, cli.Default().CRClient(), name); err != nil {
+			return nil, fmt.Errorf("failed to read the applied configuration: %w", err)
+		}
+	}
+	if infraStatus == nil {
+		var err error
+		if infraStatus, err = platform.InfraStatus(cli); err != nil {
+			return nil, fmt.Errorf("failed to read the infrastructure status: %w", err)
+		}
+	}
+	if hostMTU == 0 {
+		hostMTU = 1500
+		if cli != nil {
+			mtu, err := util.ReadMTUConfigMap(ctx, cli)
+			if err == nil {
+				hostMTU = mtu
+			} else if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to read the probed MTU: %w", err)
+			}
+		}
+	}
+
+	return network.PreviewChange(applied, &operConfig.Spec, infraStatus, hostMTU), nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operv1 "github.com/openshift/api/operator/v1"
+	cnofake "github.com/openshift/cluster-network-operator/pkg/client/fake"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+	"github.com/openshift/cluster-network-operator/pkg/network"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func previewTestSpec(serviceNetwork string) operv1.NetworkSpec {
+	return operv1.NetworkSpec{
+		ClusterNetwork: []operv1.ClusterNetworkEntry{{CIDR: "10.128.0.0/14", HostPrefix: 23}},
+		ServiceNetwork: []string{serviceNetwork},
+		DefaultNetwork: operv1.DefaultNetworkDefinition{Type: operv1.NetworkTypeOVNKubernetes},
+	}
+}
+
+func TestPreviewChange(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	applied, err := json.Marshal(previewTestSpec("172.30.0.0/16"))
+	g.Expect(err).NotTo(HaveOccurred())
+	clusterObjects := []crclient.Object{
+		&configv1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Status: configv1.InfrastructureStatus{
+				PlatformStatus: &configv1.PlatformStatus{Type: configv1.BareMetalPlatformType},
+			},
+		},
+		&configv1.Proxy{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: names.APPLIED_NAMESPACE, Name: names.APPLIED_PREFIX + names.OPERATOR_CONFIG},
+			Data:       map[string]string{"applied": string(applied)},
+		},
+	}
+	proposed := &operv1.Network{
+		ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG},
+		Spec:       previewTestSpec("172.31.0.0/16"),
+	}
+
+	// the applied configuration is read from the cluster
+	cli := cnofake.NewFakeClient(clusterObjects...)
+	preview, err := previewChange(context.Background(), cli, proposed, nil, nil, 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(preview).To(Equal(&network.ChangePreview{Verdict: network.ChangeForbidden, Reasons: []string{
+		"unsupported change to ServiceNetwork",
+	}}))
+
+	// the applied configuration file overrides the cluster one
+	override := previewTestSpec("172.31.0.0/16")
+	preview, err = previewChange(context.Background(), cli, proposed, &override, nil, 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(preview).To(Equal(&network.ChangePreview{Verdict: network.ChangeSafe}))
+
+	// nothing was applied in the cluster yet
+	cli = cnofake.NewFakeClient(clusterObjects[:2]...)
+	preview, err = previewChange(context.Background(), cli, proposed, nil, nil, 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(preview).To(Equal(&network.ChangePreview{Verdict: network.ChangeSafe, Reasons: []string{
+		"no configuration has been applied yet",
+	}}))
+
+	// the infrastructure status is required when not given as a file
+	cli = cnofake.NewFakeClient(clusterObjects[2])
+	_, err = previewChange(context.Background(), cli, proposed, nil, nil, 0)
+	g.Expect(err).To(MatchError(ContainSubstring("failed to read the infrastructure status")))
+}
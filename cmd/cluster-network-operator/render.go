@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	configv1 "github.com/openshift/api/config/v1"
+	apifeatures "github.com/openshift/api/features"
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+	"github.com/openshift/cluster-network-operator/pkg/client/fake"
+	"github.com/openshift/cluster-network-operator/pkg/network"
+	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
+
+	corev1 "k8s.io/api/core/v1"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// newRenderCommand returns a Command that renders every manifest the operator
+// would apply for a given configuration, without talking to an apiserver.
+// This is used to diff operand manifests between operator versions offline.
+func newRenderCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Render the operand manifests for a given configuration and write them to disk",
+	}
+
+	var operConfigPath string
+	var clusterConfigPath string
+	var bootstrapResultPath string
+	var manifestDir string
+	var outputDir string
+	var featureSet string
+	var hostMTU int
+	var operandOverridesPath string
+
+	flags := cmd.Flags()
+	flags.StringVar(&operConfigPath, "operator-config", "", "path to a Network.operator.openshift.io object (YAML)")
+	flags.StringVar(&clusterConfigPath, "cluster-config", "", "path to a Network.config.openshift.io object (YAML)")
+	flags.StringVar(&bootstrapResultPath, "bootstrap-result", "", "path to a synthetic bootstrap result (YAML)")
+	flags.StringVar(&manifestDir, "manifest-dir", "./bindata", "the directory containing the manifest templates")
+	flags.StringVar(&outputDir, "output-dir", "", "the directory in which to write the rendered manifests")
+	flags.StringVar(&featureSet, "feature-set", string(configv1.Default), "the cluster FeatureSet used to resolve feature gates")
+	flags.IntVar(&hostMTU, "host-mtu", 1500, "the node MTU used when the configuration does not specify one")
+	flags.StringVar(&operandOverridesPath, "operand-overrides", "", "path to an openshift-network-operator/operand-overrides ConfigMap (YAML), optional")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if operConfigPath == "" || clusterConfigPath == "" || bootstrapResultPath == "" || outputDir == "" {
+			return fmt.Errorf("--operator-config, --cluster-config, --bootstrap-result and --output-dir are required")
+		}
+
+		operConfig := &operv1.Network{}
+		if err := readYAMLFile(operConfigPath, operConfig); err != nil {
+			return err
+		}
+		clusterConfig := &configv1.Network{}
+		if err := readYAMLFile(clusterConfigPath, clusterConfig); err != nil {
+			return err
+		}
+		bootstrapResult := &bootstrap.BootstrapResult{}
+		if err := readYAMLFile(bootstrapResultPath, bootstrapResult); err != nil {
+			return err
+		}
+
+		featureGates, err := featureGatesForFeatureSet(configv1.FeatureSet(featureSet))
+		if err != nil {
+			return err
+		}
+
+		// Mirror what the operconfig controller does before rendering.
+		network.DeprecatedCanonicalize(&operConfig.Spec)
+		if err := network.Validate(&operConfig.Spec); err != nil {
+			return err
+		}
+		network.FillDefaults(&operConfig.Spec, nil, hostMTU)
+
+		// Renderers look up a few existing objects (e.g. to know if a
+		// rollout is in progress); an empty fake client stands in for the
+		// apiserver, so everything is rendered as for a fresh install.
+		client := fake.NewFakeClient()
+
+		if operandOverridesPath != "" {
+			cm := &corev1.ConfigMap{}
+			if err := readYAMLFile(operandOverridesPath, cm); err != nil {
+				return err
+			}
+			bootstrapResult.OperandOverrides.Data = cm.Data
+		}
+
+		objs, _, err := network.Render(&operConfig.Spec, &clusterConfig.Spec, manifestDir, client, featureGates, bootstrapResult)
+		if err != nil {
+			return fmt.Errorf("failed to render: %w", err)
+		}
+
+		if err := writeRenderedObjects(outputDir, objs); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %d objects to %s\n", len(objs), outputDir)
+		return nil
+	}
+	return cmd
+}
+
+// readYAMLFile decodes the YAML (or JSON) file at path in to out.
+func readYAMLFile(path string, out interface{}) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// featureGatesForFeatureSet returns the self-managed feature gates that are
+// enabled and disabled for the given FeatureSet.
+func featureGatesForFeatureSet(featureSet configv1.FeatureSet) (featuregates.FeatureGate, error) {
+	gates, err := apifeatures.FeatureSets(apifeatures.SelfManaged, featureSet)
+	if err != nil {
+		return nil, err
+	}
+	enabled := make([]configv1.FeatureGateName, 0, len(gates.Enabled))
+	for _, g := range gates.Enabled {
+		enabled = append(enabled, g.FeatureGateAttributes.Name)
+	}
+	disabled := make([]configv1.FeatureGateName, 0, len(gates.Disabled))
+	for _, g := range gates.Disabled {
+		disabled = append(disabled, g.FeatureGateAttributes.Name)
+	}
+	return featuregates.NewFeatureGate(enabled, disabled), nil
+}
+
+// writeRenderedObjects writes one YAML file per object in to dir. Files are
+// named after the object rather than its position in the render output, so
+// that the output of two versions can be compared with a plain recursive diff.
+// An object that is rendered more than once is written as a multi-document
+// file, in render order.
+func writeRenderedObjects(dir string, objs []*uns.Unstructured) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	files := map[string][]byte{}
+	order := []string{}
+	for _, obj := range objs {
+		b, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return fmt.Errorf("failed to encode (%s) %s/%s: %w", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+		}
+		name := renderedObjectFileName(obj)
+		if existing, ok := files[name]; ok {
+			files[name] = append(append(existing, []byte("---\n")...), b...)
+			continue
+		}
+		files[name] = b
+		order = append(order, name)
+	}
+	for _, name := range order {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, files[name], 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// renderedObjectFileName returns a stable file name for a rendered object,
+// of the form <group>_<kind>_<namespace>_<name>.yaml
+func renderedObjectFileName(obj *uns.Unstructured) string {
+	gvk := obj.GroupVersionKind()
+	group := gvk.Group
+	if group == "" {
+		group = "core"
+	}
+	parts := []string{group, strings.ToLower(gvk.Kind)}
+	if obj.GetNamespace() != "" {
+		parts = append(parts, obj.GetNamespace())
+	}
+	parts = append(parts, obj.GetName())
+	return strings.ReplaceAll(strings.Join(parts, "_"), "/", "-") + ".yaml"
+}
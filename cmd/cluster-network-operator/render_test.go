@@ -0,0 +1,133 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const renderTestOperatorConfig = `
+apiVersion: operator.openshift.io/v1
+kind: Network
+metadata:
+  name: cluster
+spec:
+  clusterNetwork:
+  - cidr: 10.128.0.0/14
+    hostPrefix: 23
+  serviceNetwork:
+  - 172.30.0.0/16
+  defaultNetwork:
+    type: OVNKubernetes
+`
+
+const renderTestClusterConfig = `
+apiVersion: config.openshift.io/v1
+kind: Network
+metadata:
+  name: cluster
+spec:
+  clusterNetwork:
+  - cidr: 10.128.0.0/14
+    hostPrefix: 23
+  serviceNetwork:
+  - 172.30.0.0/16
+  networkType: OVNKubernetes
+`
+
+const renderTestBootstrapResult = `
+Infra:
+  PlatformType: GCP
+  PlatformRegion: moon-2
+  ControlPlaneTopology: HighlyAvailable
+  InfrastructureTopology: HighlyAvailable
+  APIServers:
+    default:
+      Host: api.example.com
+      Port: "6443"
+OVN:
+  ControlPlaneReplicaCount: 3
+  OVNKubernetesConfig:
+    DpuHostModeLabel: network.operator.openshift.io/dpu-host
+    DpuModeLabel: network.operator.openshift.io/dpu
+    SmartNicModeLabel: network.operator.openshift.io/smart-nic
+    HyperShiftConfig:
+      Enabled: false
+`
+
+// writeRenderTestFiles writes the inputs of the render command to a temporary
+// directory, and returns the arguments to pass them.
+func writeRenderTestFiles(t *testing.T, operConfig string) (string, []string) {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"operator-config":  operConfig,
+		"cluster-config":   renderTestClusterConfig,
+		"bootstrap-result": renderTestBootstrapResult,
+	}
+	args := []string{"--manifest-dir", "../../bindata", "--output-dir", filepath.Join(dir, "out")}
+	for flag, content := range files {
+		path := filepath.Join(dir, flag+".yaml")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		args = append(args, "--"+flag, path)
+	}
+	return filepath.Join(dir, "out"), args
+}
+
+func TestRenderCommand(t *testing.T) {
+	g := NewGomegaWithT(t)
+	t.Setenv("RELEASE_VERSION", "4.99.0")
+
+	outputDir, args := writeRenderTestFiles(t, renderTestOperatorConfig)
+	cmd := newRenderCommand()
+	cmd.SetArgs(args)
+	g.Expect(cmd.Execute()).To(Succeed())
+
+	for _, name := range []string{
+		"apps_daemonset_openshift-ovn-kubernetes_ovnkube-node.yaml",
+		"apps_deployment_openshift-ovn-kubernetes_ovnkube-control-plane.yaml",
+		"apps_daemonset_openshift-multus_multus.yaml",
+		"core_namespace_openshift-ovn-kubernetes.yaml",
+	} {
+		g.Expect(filepath.Join(outputDir, name)).To(BeAnExistingFile())
+	}
+	node, err := os.ReadFile(filepath.Join(outputDir, "apps_daemonset_openshift-ovn-kubernetes_ovnkube-node.yaml"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(node)).To(ContainSubstring("kind: DaemonSet"))
+}
+
+func TestRenderCommandInvalidConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// the service network is not a CIDR
+	invalid := `
+apiVersion: operator.openshift.io/v1
+kind: Network
+metadata:
+  name: cluster
+spec:
+  clusterNetwork:
+  - cidr: 10.128.0.0/14
+    hostPrefix: 23
+  serviceNetwork:
+  - not-a-cidr
+  defaultNetwork:
+    type: OVNKubernetes
+`
+	outputDir, args := writeRenderTestFiles(t, invalid)
+	cmd := newRenderCommand()
+	cmd.SetArgs(args)
+	cmd.SilenceUsage = true
+	g.Expect(cmd.Execute()).To(MatchError(ContainSubstring("not-a-cidr")))
+	g.Expect(outputDir).NotTo(BeADirectory())
+
+	// the required inputs are checked
+	cmd = newRenderCommand()
+	cmd.SetArgs([]string{"--output-dir", t.TempDir()})
+	cmd.SilenceUsage = true
+	g.Expect(cmd.Execute()).To(MatchError(ContainSubstring("are required")))
+}
@@ -24,14 +24,17 @@ import (
 //   - tls.key - the private key
 //   - tls.crt - the certificate, signed by the CA
 //
-// The CA certificate will have a validity of 10 years, rotated after 9.
-// The target certificate will have a validity of 6 months, rotated after 3
+// By default, the CA certificate will have a validity of 10 years, rotated after 9.
+// The target certificate will have a validity of 6 months, rotated after 3.
+// Both have RSA keys. The validity and the key algorithm of each certificate
+// can be configured; a change of either rotates the certificate.
 //
 // The CA certificate will have a CommonName of "<namespace>_<name>-ca@<timestamp>", where
 // <timestamp> is the last rotation time.
 //
 // +k8s:openapi-gen=true
 // +kubebuilder:resource:path=operatorpkis,scope=Namespaced
+// +kubebuilder:subresource:status
 // +kubebuilder:metadata:annotations=include.release.openshift.io/self-managed-high-availability=true
 // +kubebuilder:metadata:annotations=include.release.openshift.io/ibm-cloud-managed=true
 type OperatorPKI struct {
@@ -48,9 +51,48 @@ type OperatorPKI struct {
 // +k8s:openapi-gen=true
 // +kubebuilder:validation:Required
 type OperatorPKISpec struct {
-	// targetCert configures the certificate signed by the CA. It will have
-	// both ClientAuth and ServerAuth enabled
+	// targetCert configures the certificate signed by the CA. By default, it
+	// has both ClientAuth and ServerAuth enabled
 	TargetCert CertSpec `json:"targetCert"`
+
+	// ca configures the CA certificate.
+	//
+	// +optional
+	CA CASpec `json:"ca,omitempty"`
+}
+
+// KeyAlgorithm is the algorithm of the key of a certificate.
+// +kubebuilder:validation:Enum=RSA;ECDSA
+type KeyAlgorithm string
+
+const (
+	// KeyAlgorithmRSA is a 2048 bit RSA key
+	KeyAlgorithmRSA KeyAlgorithm = "RSA"
+	// KeyAlgorithmECDSA is an ECDSA key on the P-256 curve
+	KeyAlgorithmECDSA KeyAlgorithm = "ECDSA"
+)
+
+// ExtendedKeyUsage is an extended key usage of a certificate.
+// +kubebuilder:validation:Enum=ServerAuth;ClientAuth
+type ExtendedKeyUsage string
+
+const (
+	ExtendedKeyUsageServerAuth ExtendedKeyUsage = "ServerAuth"
+	ExtendedKeyUsageClientAuth ExtendedKeyUsage = "ClientAuth"
+)
+
+// CASpec defines the configuration of the CA certificate.
+type CASpec struct {
+	// validity is the duration the CA certificate is valid for. It is
+	// rotated after 80% of it. Defaults to 10 years.
+	//
+	// +optional
+	Validity *metav1.Duration `json:"validity,omitempty"`
+
+	// keyAlgorithm is the algorithm of the key of the CA. Defaults to RSA.
+	//
+	// +optional
+	KeyAlgorithm KeyAlgorithm `json:"keyAlgorithm,omitempty"`
 }
 
 // CertSpec defines common certificate configuration.
@@ -59,10 +101,92 @@ type CertSpec struct {
 	//
 	// +kubebuilder:validation:MinLength=1
 	CommonName string `json:"commonName"`
+
+	// validity is the duration the certificate is valid for. It is rotated
+	// after half of it. It must be shorter than the validity of the CA.
+	// Defaults to 6 months.
+	//
+	// +optional
+	Validity *metav1.Duration `json:"validity,omitempty"`
+
+	// keyAlgorithm is the algorithm of the key of the certificate. Defaults
+	// to RSA.
+	//
+	// +optional
+	KeyAlgorithm KeyAlgorithm `json:"keyAlgorithm,omitempty"`
+
+	// dnsNames are DNS subject alternative names of the certificate, in
+	// addition to the commonName.
+	//
+	// +optional
+	// +listType=set
+	DNSNames []string `json:"dnsNames,omitempty"`
+
+	// ipAddresses are IP subject alternative names of the certificate.
+	//
+	// +optional
+	// +listType=set
+	IPAddresses []string `json:"ipAddresses,omitempty"`
+
+	// extendedKeyUsages are the extended key usages of the certificate.
+	// Defaults to both ServerAuth and ClientAuth.
+	//
+	// +optional
+	// +listType=set
+	ExtendedKeyUsages []ExtendedKeyUsage `json:"extendedKeyUsages,omitempty"`
 }
 
-// OperatorPKIStatus is not implemented.
+// OperatorPKIStatus is the observed status of the PKI.
 type OperatorPKIStatus struct {
+	// conditions are the conditions of the PKI. Degraded is True when the
+	// spec is invalid or the certificates could not be reconciled.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ca is the status of the current CA certificate.
+	//
+	// +optional
+	CA *CertificateStatus `json:"ca,omitempty"`
+
+	// caBundleConfigMapName is the name of the ConfigMap, in the namespace of
+	// the PKI, with the CA bundle that consumers of the certificate trust.
+	//
+	// +optional
+	CABundleConfigMapName string `json:"caBundleConfigMapName,omitempty"`
+
+	// targetCert is the status of the current certificate signed by the CA.
+	//
+	// +optional
+	TargetCert *CertificateStatus `json:"targetCert,omitempty"`
+}
+
+// CertificateStatus is the status of a certificate.
+type CertificateStatus struct {
+	// secretName is the name of the Secret, in the namespace of the PKI, that
+	// holds the certificate and its key.
+	SecretName string `json:"secretName"`
+
+	// notBefore is the time the certificate is valid from.
+	NotBefore metav1.Time `json:"notBefore"`
+
+	// notAfter is the time the certificate expires.
+	NotAfter metav1.Time `json:"notAfter"`
+
+	// fingerprint is the SHA-256 fingerprint of the certificate, as
+	// colon-separated uppercase hex bytes.
+	Fingerprint string `json:"fingerprint"`
+
+	// lastRotationTime is the time the operator observed the current
+	// certificate replace the previous one, or the notBefore of the
+	// certificate if it has not been rotated since it was first reported.
+	LastRotationTime metav1.Time `json:"lastRotationTime"`
+
+	// nextRotationTime is the time after which the operator rotates the
+	// certificate.
+	NextRotationTime metav1.Time `json:"nextRotationTime"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
 	"github.com/openshift/cluster-network-operator/pkg/names"
@@ -35,20 +36,77 @@ func GetClusterName(obj Object) string {
 // For more information, see https://kubernetes.io/docs/reference/using-api/server-side-apply/
 // The subcontroller, if set, is used to assign field ownership.
 func ApplyObject(ctx context.Context, client cnoclient.Client, obj Object, subcontroller string, subresources ...string) error {
+	_, _, err := applyObject(ctx, client, obj, subcontroller, false, false, subresources...)
+	return err
+}
+
+// ApplyObjectChanged is ApplyObject, but also tells whether the apply created
+// or changed the object. The apiserver does not update the resourceVersion of
+// an object when an apply does not change it, so the object changed if its
+// resourceVersion is not the one it had before the apply. An object changed by
+// someone else between the two is also reported as changed.
+func ApplyObjectChanged(ctx context.Context, client cnoclient.Client, obj Object, subcontroller string) (bool, error) {
+	current, result, err := applyObject(ctx, client, obj, subcontroller, false, true)
+	if err != nil || result == nil {
+		return false, err
+	}
+	return objectChanged(current, result), nil
+}
+
+// objectChanged returns true if result, the object returned by an apply, is
+// not current, the object before the apply (nil if it did not exist).
+func objectChanged(current, result *unstructured.Unstructured) bool {
+	return current == nil || current.GetResourceVersion() != result.GetResourceVersion()
+}
+
+// fieldManagerFor returns the field manager used to apply the objects of a subcontroller.
+func fieldManagerFor(subcontroller string) string {
+	if subcontroller == "" {
+		return "cluster-network-operator"
+	}
+	return fmt.Sprintf("cluster-network-operator/%s", subcontroller)
+}
+
+// DryRunObject submits the same server-side apply patch as ApplyObject, but
+// with DryRun set, so that nothing is persisted. It returns the changes that
+// applying the object would make to the cluster, or nil if there would be none.
+func DryRunObject(ctx context.Context, client cnoclient.Client, obj Object, subcontroller string) (*ObjectChange, error) {
+	current, result, err := applyObject(ctx, client, obj, subcontroller, true, false)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		// the object would be skipped
+		return nil, nil
+	}
+	change := diffObjects(current, result)
+	if change != nil {
+		change.ClusterName = GetClusterName(obj)
+	}
+	return change, nil
+}
+
+// applyObject implements ApplyObject, ApplyObjectChanged and DryRunObject.
+// When dryRun or getCurrent is set, it also returns the current object (nil if
+// it does not exist). The returned objects are nil if the object was skipped.
+func applyObject(ctx context.Context, client cnoclient.Client, obj Object, subcontroller string, dryRun, getCurrent bool, subresources ...string) (*unstructured.Unstructured, *unstructured.Unstructured, error) {
 	name := obj.GetName()
 	namespace := obj.GetNamespace()
 	clusterClient := client.ClientFor(GetClusterName(obj))
 	if clusterClient == nil {
-		return fmt.Errorf("object %s/%s specifies unknown cluster %s", namespace, name, GetClusterName(obj))
+		return nil, nil, fmt.Errorf("object %s/%s specifies unknown cluster %s", namespace, name, GetClusterName(obj))
 	}
 
 	oks, _, _ := clusterClient.Scheme().ObjectKinds(obj)
 	if len(oks) == 0 {
-		return errors.Errorf("Object %s/%s has no Kind registered in the Scheme", namespace, name)
+		return nil, nil, errors.Errorf("Object %s/%s has no Kind registered in the Scheme", namespace, name)
 	}
 	gvk := oks[0]
 	if name == "" {
-		return errors.Errorf("Object %s has no name", gvk)
+		return nil, nil, errors.Errorf("Object %s has no name", gvk)
+	}
+	if !dryRun && !metricsDisabled(ctx) {
+		defer observeApplyDuration(gvk, time.Now())
 	}
 
 	// Dragons: If we're passed a non-Unstructured object (e.g. v1.ConfigMap), it won't have
@@ -57,7 +115,11 @@ func ApplyObject(ctx context.Context, client cnoclient.Client, obj Object, subco
 	obj.GetObjectKind().SetGroupVersionKind(gvk)
 	// used for logging and errors
 	objDesc := fmt.Sprintf("(%s) %s/%s", gvk.String(), namespace, name)
-	log.Printf("reconciling %s", objDesc)
+	if dryRun {
+		log.Printf("reconciling %s (dry run)", objDesc)
+	} else {
+		log.Printf("reconciling %s", objDesc)
+	}
 
 	// It isn't allowed to send ManagedFields in a Patch.
 	obj.SetManagedFields(nil)
@@ -66,31 +128,37 @@ func ApplyObject(ctx context.Context, client cnoclient.Client, obj Object, subco
 		var err error
 		obj, err = getCopySource(ctx, obj, client)
 		if err != nil {
-			return fmt.Errorf("failed to retrieve copy-from object: %w", err)
+			return nil, nil, fmt.Errorf("failed to retrieve copy-from object: %w", err)
 		}
 	}
 
 	// determine resource
 	rm, err := clusterClient.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve resource from Object %s: %v", objDesc, err)
+		return nil, nil, fmt.Errorf("failed to retrieve resource from Object %s: %v", objDesc, err)
 	}
 
 	// If create-wait is specified, ignore creating the object
 	if _, ok := obj.GetAnnotations()[names.CreateWaitAnnotation]; ok {
 		log.Printf("Object %s has create-wait annotation, skipping apply.", objDesc)
-		return nil
+		return nil, nil, nil
 	}
 
-	// If create-only is specified, check to see if exists
-	if _, ok := obj.GetAnnotations()[names.CreateOnlyAnnotation]; ok {
-		_, err := clusterClient.Dynamic().Resource(rm.Resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
-		if err == nil {
+	// If create-only is specified, check to see if exists.
+	// A dry run, or a caller that wants to know whether the apply changed the
+	// object, needs the current object to compute the changes.
+	var current *unstructured.Unstructured
+	_, createOnly := obj.GetAnnotations()[names.CreateOnlyAnnotation]
+	if createOnly || dryRun || getCurrent {
+		current, err = clusterClient.Dynamic().Resource(rm.Resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil && createOnly {
 			log.Printf("Object %s has create-only annotation and already exists, skipping apply.", objDesc)
-			return nil
+			return nil, nil, nil
 		}
-		if !apierrors.IsNotFound(err) {
-			return err
+		if apierrors.IsNotFound(err) {
+			current = nil
+		} else if err != nil {
+			return nil, nil, err
 		}
 	}
 
@@ -101,14 +169,11 @@ func ApplyObject(ctx context.Context, client cnoclient.Client, obj Object, subco
 		// apply is not doing what we want
 		obj, err = merge(ctx, clusterClient)
 		if err != nil {
-			return fmt.Errorf("failed to merge object %s: %w", objDesc, err)
+			return nil, nil, fmt.Errorf("failed to merge object %s: %w", objDesc, err)
 		}
 	}
 
-	fieldManager := "cluster-network-operator"
-	if subcontroller != "" {
-		fieldManager = fmt.Sprintf("%s/%s", fieldManager, subcontroller)
-	}
+	fieldManager := fieldManagerFor(subcontroller)
 
 	// Use server-side apply to merge the desired object with the object on disk
 	patchOptions := metav1.PatchOptions{
@@ -116,20 +181,27 @@ func ApplyObject(ctx context.Context, client cnoclient.Client, obj Object, subco
 		Force:        utilpointer.To(true),
 		FieldManager: fieldManager,
 	}
+	if dryRun {
+		patchOptions.DryRun = []string{metav1.DryRunAll}
+	}
 	// Send the full object to be applied on the server side.
 	data, err := runtime.Encode(unstructured.UnstructuredJSONScheme, obj)
 	if err != nil {
 		log.Printf("could not encode %s for apply", objDesc)
-		return fmt.Errorf("could not encode for patching: %w", err)
+		return nil, nil, fmt.Errorf("could not encode for patching: %w", err)
 	}
 
-	_, err = clusterClient.Dynamic().Resource(rm.Resource).Namespace(namespace).Patch(ctx, name, types.ApplyPatchType, data, patchOptions, subresources...)
+	result, err := clusterClient.Dynamic().Resource(rm.Resource).Namespace(namespace).Patch(ctx, name, types.ApplyPatchType, data, patchOptions, subresources...)
 	if err != nil {
-		return fmt.Errorf("failed to apply / update %s: %w", objDesc, err)
+		return nil, nil, fmt.Errorf("failed to apply / update %s: %w", objDesc, err)
 	}
 
-	log.Printf("Apply / Create of %s was successful", objDesc)
-	return nil
+	if dryRun {
+		log.Printf("Dry run apply of %s was successful", objDesc)
+	} else {
+		log.Printf("Apply / Create of %s was successful", objDesc)
+	}
+	return current, result, nil
 }
 
 // getCopySource retrieves an object using copy-from annotation from obj.
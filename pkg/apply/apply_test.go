@@ -0,0 +1,26 @@
+package apply
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_objectChanged(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	current := &unstructured.Unstructured{}
+	current.SetResourceVersion("100")
+	result := current.DeepCopy()
+
+	// a no-op apply keeps the resourceVersion
+	g.Expect(objectChanged(current, result)).To(BeFalse())
+
+	result.SetResourceVersion("101")
+	g.Expect(objectChanged(current, result)).To(BeTrue())
+
+	// the object was created
+	g.Expect(objectChanged(nil, result)).To(BeTrue())
+}
@@ -0,0 +1,144 @@
+package apply
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ObjectChange describes the changes that applying an object would make.
+type ObjectChange struct {
+	Group       string `json:"group,omitempty"`
+	Kind        string `json:"kind"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name"`
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// Created is true if the object does not exist yet.
+	Created bool `json:"created,omitempty"`
+
+	// Fields are the paths of the fields whose value would change,
+	// e.g. spec.template.spec.containers[0].image
+	Fields []string `json:"fields,omitempty"`
+
+	// Error is set if the change could not be computed.
+	Error string `json:"error,omitempty"`
+}
+
+// ignoredMetadataFields are maintained by the apiserver, and changes to them
+// are not interesting
+var ignoredMetadataFields = []string{
+	"managedFields",
+	"resourceVersion",
+	"generation",
+	"creationTimestamp",
+	"uid",
+}
+
+// diffObjects compares the current object with the result of a dry-run apply
+// and returns the resulting change, or nil if nothing would change.
+func diffObjects(current, desired *unstructured.Unstructured) *ObjectChange {
+	change := &ObjectChange{
+		Group:     desired.GroupVersionKind().Group,
+		Kind:      desired.GetKind(),
+		Namespace: desired.GetNamespace(),
+		Name:      desired.GetName(),
+	}
+	if current == nil {
+		change.Created = true
+		return change
+	}
+
+	currentObj := stripIgnoredFields(current.Object)
+	desiredObj := stripIgnoredFields(desired.Object)
+	change.Fields = diffFields("", currentObj, desiredObj)
+	if len(change.Fields) == 0 {
+		return nil
+	}
+	return change
+}
+
+// stripIgnoredFields returns a shallow copy of obj without the fields
+// populated by the apiserver.
+func stripIgnoredFields(obj map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		out[k] = v
+	}
+	delete(out, "status")
+	if meta, ok := obj["metadata"].(map[string]interface{}); ok {
+		metaCopy := make(map[string]interface{}, len(meta))
+		for k, v := range meta {
+			metaCopy[k] = v
+		}
+		for _, f := range ignoredMetadataFields {
+			delete(metaCopy, f)
+		}
+		out["metadata"] = metaCopy
+	}
+	return out
+}
+
+// diffFields recursively compares two decoded JSON values and returns the
+// sorted paths of the leaves that differ.
+func diffFields(path string, a, b interface{}) []string {
+	if reflect.DeepEqual(a, b) {
+		return nil
+	}
+
+	switch aTyped := a.(type) {
+	case map[string]interface{}:
+		bTyped, ok := b.(map[string]interface{})
+		if !ok {
+			return []string{path}
+		}
+		keys := map[string]struct{}{}
+		for k := range aTyped {
+			keys[k] = struct{}{}
+		}
+		for k := range bTyped {
+			keys[k] = struct{}{}
+		}
+		out := []string{}
+		for k := range keys {
+			out = append(out, diffFields(joinFieldPath(path, k), aTyped[k], bTyped[k])...)
+		}
+		sort.Strings(out)
+		return out
+	case []interface{}:
+		bTyped, ok := b.([]interface{})
+		if !ok {
+			return []string{path}
+		}
+		out := []string{}
+		for i := 0; i < len(aTyped) || i < len(bTyped); i++ {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(aTyped):
+				out = append(out, elemPath)
+			case i >= len(bTyped):
+				out = append(out, elemPath)
+			default:
+				out = append(out, diffFields(elemPath, aTyped[i], bTyped[i])...)
+			}
+		}
+		return out
+	default:
+		return []string{path}
+	}
+}
+
+// joinFieldPath appends a map key to a field path. Keys that are not simple
+// identifiers (e.g. label and annotation keys) are written in brackets.
+func joinFieldPath(path, key string) string {
+	if strings.ContainsAny(key, "./[]") {
+		return fmt.Sprintf("%s[%s]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
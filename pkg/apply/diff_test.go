@@ -0,0 +1,97 @@
+package apply
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_diffObjects(t *testing.T) {
+	current := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "DaemonSet",
+			"metadata": map[string]interface{}{
+				"name":            "ovnkube-node",
+				"namespace":       "openshift-ovn-kubernetes",
+				"resourceVersion": "1",
+				"annotations": map[string]interface{}{
+					"networkoperator.openshift.io/ip-family-mode": "single-stack",
+				},
+			},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "ovnkube-controller", "image": "ovn:1"},
+						},
+					},
+				},
+			},
+			"status": map[string]interface{}{
+				"numberReady": int64(3),
+			},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		current  *unstructured.Unstructured
+		mutate   func(*unstructured.Unstructured)
+		expected *ObjectChange
+	}{
+		{
+			name:    "object is created",
+			current: nil,
+			mutate:  func(*unstructured.Unstructured) {},
+			expected: &ObjectChange{
+				Group:     "apps",
+				Kind:      "DaemonSet",
+				Namespace: "openshift-ovn-kubernetes",
+				Name:      "ovnkube-node",
+				Created:   true,
+			},
+		},
+		{
+			name:    "only server-populated fields change",
+			current: current(),
+			mutate: func(u *unstructured.Unstructured) {
+				u.SetResourceVersion("2")
+				_ = unstructured.SetNestedField(u.Object, int64(2), "status", "numberReady")
+			},
+			expected: nil,
+		},
+		{
+			name:    "image and annotation change",
+			current: current(),
+			mutate: func(u *unstructured.Unstructured) {
+				u.SetAnnotations(map[string]string{"networkoperator.openshift.io/ip-family-mode": "dual-stack"})
+				_ = unstructured.SetNestedSlice(u.Object, []interface{}{
+					map[string]interface{}{"name": "ovnkube-controller", "image": "ovn:2"},
+					map[string]interface{}{"name": "kube-rbac-proxy", "image": "proxy:1"},
+				}, "spec", "template", "spec", "containers")
+			},
+			expected: &ObjectChange{
+				Group:     "apps",
+				Kind:      "DaemonSet",
+				Namespace: "openshift-ovn-kubernetes",
+				Name:      "ovnkube-node",
+				Fields: []string{
+					"metadata.annotations[networkoperator.openshift.io/ip-family-mode]",
+					"spec.template.spec.containers[0].image",
+					"spec.template.spec.containers[1]",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			desired := current()
+			tt.mutate(desired)
+			g.Expect(diffObjects(tt.current, desired)).To(Equal(tt.expected))
+		})
+	}
+}
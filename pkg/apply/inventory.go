@@ -0,0 +1,211 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+
+	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KeepCRDs is a list of CRD names that won't be removed from the system even if
+// the conditions that triggered their install are no longer met. The general
+// purpose of this is to prevent data loss from configured instances of such
+// CRDs. User should explicitly delete such instances.
+// TODO: perhaps degrade CNO state if CRDs are attempted to be removed
+var KeepCRDs = sets.New(
+	// Aside from the data loss, MetalLB operator relies on FRR and reconciles
+	// the CNO flag to deploy it. This reconciliation would be meaningless it we
+	// were to destroy frrconfigurations.
+	"frrconfigurations.frrk8s.metallb.io",
+	"routeadvertisements.k8s.ovn.org",
+)
+
+// InventoryEntry identifies an object that was applied by the operator.
+type InventoryEntry struct {
+	ClusterName string `json:"clusterName,omitempty"`
+	Group       string `json:"group,omitempty"`
+	Version     string `json:"version"`
+	Kind        string `json:"kind"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name"`
+}
+
+// NewInventoryEntry returns the InventoryEntry for an object.
+func NewInventoryEntry(obj Object) InventoryEntry {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	return InventoryEntry{
+		ClusterName: GetClusterName(obj),
+		Group:       gvk.Group,
+		Version:     gvk.Version,
+		Kind:        gvk.Kind,
+		Namespace:   obj.GetNamespace(),
+		Name:        obj.GetName(),
+	}
+}
+
+// key identifies the object an entry points to. The version is not part of
+// it, since an object rendered with a different version is still the same object.
+func (e InventoryEntry) key() string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s", e.ClusterName, e.Group, e.Kind, e.Namespace, e.Name)
+}
+
+func (e InventoryEntry) String() string {
+	return fmt.Sprintf("(%s/%s, Kind=%s) %s/%s", e.Group, e.Version, e.Kind, e.Namespace, e.Name)
+}
+
+// GetInventory retrieves the inventory of objects applied by the previous
+// reconcile. Returns nil with no error if there is no inventory yet.
+func GetInventory(ctx context.Context, client crclient.Client) ([]InventoryEntry, error) {
+	cm := &corev1.ConfigMap{}
+	err := client.Get(ctx, types.NamespacedName{Namespace: names.APPLIED_NAMESPACE, Name: names.INVENTORY_CONFIGMAP}, cm)
+	if err != nil && apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	entries := []InventoryEntry{}
+	if err := json.Unmarshal([]byte(cm.Data["inventory"]), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// InventoryConfigMap renders the ConfigMap in which we store the inventory.
+func InventoryConfigMap(entries []InventoryEntry) (*corev1.ConfigMap, error) {
+	buf, err := json.Marshal(entries)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: names.APPLIED_NAMESPACE,
+			Name:      names.INVENTORY_CONFIGMAP,
+		},
+		Data: map[string]string{
+			"inventory": string(buf),
+		},
+	}, nil
+}
+
+// StaleInventoryEntries returns the entries of previous that are not in current,
+// in the order in which they should be deleted.
+func StaleInventoryEntries(previous, current []InventoryEntry) []InventoryEntry {
+	currentKeys := sets.New[string]()
+	for _, e := range current {
+		currentKeys.Insert(e.key())
+	}
+
+	stale := []InventoryEntry{}
+	for _, e := range previous {
+		if !currentKeys.Has(e.key()) {
+			stale = append(stale, e)
+		}
+	}
+	sort.SliceStable(stale, func(i, j int) bool {
+		return pruneRank(stale[i]) < pruneRank(stale[j])
+	})
+	return stale
+}
+
+// pruneRank orders deletions so that nothing is left pointing to an object
+// that was already deleted: webhooks go before the workloads serving them,
+// workloads before their configuration and RBAC, and CRDs and Namespaces,
+// which take everything in them along, go last.
+func pruneRank(e InventoryEntry) int {
+	switch {
+	case e.Group == "admissionregistration.k8s.io", e.Group == "apiregistration.k8s.io":
+		return 0
+	case e.Group == "apps", e.Group == "batch":
+		return 1
+	case e.Group == "apiextensions.k8s.io" && e.Kind == "CustomResourceDefinition":
+		return 3
+	case e.Group == "" && e.Kind == "Namespace":
+		return 4
+	default:
+		return 2
+	}
+}
+
+// PruneObjects deletes the objects of the given inventory entries, in order.
+// Objects with the names.PruneProtectAnnotation annotation, as well as the
+// CRDs in KeepCRDs, are left in place. The entries that could not be deleted
+// are returned, so that deletion can be retried later.
+func PruneObjects(ctx context.Context, client cnoclient.Client, stale []InventoryEntry) []InventoryEntry {
+	failed := []InventoryEntry{}
+	for _, e := range stale {
+		// Namespaces take everything in them along, so only delete them
+		// once everything else was cleanly removed.
+		if e.Group == "" && e.Kind == "Namespace" && len(failed) > 0 {
+			log.Printf("Not pruning %s yet, other objects failed to be pruned", e)
+			failed = append(failed, e)
+			continue
+		}
+		if err := pruneObject(ctx, client, e); err != nil {
+			log.Printf("Failed to prune %s: %v", e, err)
+			failed = append(failed, e)
+		}
+	}
+	return failed
+}
+
+func pruneObject(ctx context.Context, client cnoclient.Client, e InventoryEntry) error {
+	clusterClient := client.ClientFor(e.ClusterName)
+	if clusterClient == nil {
+		return fmt.Errorf("unknown cluster %s", e.ClusterName)
+	}
+
+	if e.Group == "" && e.Kind == "Namespace" && e.Name == names.APPLIED_NAMESPACE {
+		return nil
+	}
+	if e.Group == "apiextensions.k8s.io" && e.Kind == "CustomResourceDefinition" && KeepCRDs.Has(e.Name) {
+		log.Printf("Won't prune CRD %q to prevent data loss, skip", e.Name)
+		return nil
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(metav1.GroupVersion{Group: e.Group, Version: e.Version}.String())
+	obj.SetKind(e.Kind)
+	err := clusterClient.CRClient().Get(ctx, types.NamespacedName{Namespace: e.Namespace, Name: e.Name}, obj)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// The inventory is not enough: an object that was replaced by someone
+	// else after we applied it is not ours to delete.
+	if obj.GetLabels()[names.AppliedByLabel] != names.AppliedByLabelValue {
+		log.Printf("Object %s is no longer rendered but does not have the %s=%s label, not deleting", e, names.AppliedByLabel, names.AppliedByLabelValue)
+		return nil
+	}
+
+	if _, ok := obj.GetAnnotations()[names.PruneProtectAnnotation]; ok {
+		log.Printf("Object %s is no longer rendered but has the %s annotation, not deleting", e, names.PruneProtectAnnotation)
+		return nil
+	}
+
+	log.Printf("Object %s is no longer rendered, deleting", e)
+	err = clusterClient.CRClient().Delete(ctx, obj, crclient.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
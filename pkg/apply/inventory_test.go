@@ -0,0 +1,73 @@
+package apply
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift/cluster-network-operator/pkg/client/fake"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestStaleInventoryEntries(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ns := InventoryEntry{Version: "v1", Kind: "Namespace", Name: "openshift-foo"}
+	crd := InventoryEntry{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition", Name: "foos.example.com"}
+	cm := InventoryEntry{Version: "v1", Kind: "ConfigMap", Namespace: "openshift-foo", Name: "foo"}
+	ds := InventoryEntry{Group: "apps", Version: "v1", Kind: "DaemonSet", Namespace: "openshift-foo", Name: "foo"}
+	webhook := InventoryEntry{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration", Name: "foo"}
+	kept := InventoryEntry{Group: "apps", Version: "v1beta1", Kind: "Deployment", Namespace: "openshift-bar", Name: "bar"}
+
+	// Nothing is stale if nothing changed
+	g.Expect(StaleInventoryEntries([]InventoryEntry{ns, cm}, []InventoryEntry{ns, cm})).To(BeEmpty())
+
+	// A version change is not a different object
+	keptV1 := kept
+	keptV1.Version = "v1"
+	g.Expect(StaleInventoryEntries([]InventoryEntry{kept}, []InventoryEntry{keptV1})).To(BeEmpty())
+
+	// The same object in a different cluster is a different object
+	keptHosted := kept
+	keptHosted.ClusterName = "management"
+	g.Expect(StaleInventoryEntries([]InventoryEntry{keptHosted}, []InventoryEntry{kept})).To(Equal([]InventoryEntry{keptHosted}))
+
+	// Stale objects are returned in deletion order
+	stale := StaleInventoryEntries([]InventoryEntry{ns, crd, cm, kept, ds, webhook}, []InventoryEntry{kept})
+	g.Expect(stale).To(Equal([]InventoryEntry{webhook, ds, cm, crd, ns}))
+}
+
+func TestPruneObjects(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	appliedLabels := map[string]string{names.AppliedByLabel: names.AppliedByLabelValue}
+	applied := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-foo", Name: "applied", Labels: appliedLabels}}
+	replaced := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-foo", Name: "replaced"}}
+	protected := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-foo", Name: "protected", Labels: appliedLabels,
+		Annotations: map[string]string{names.PruneProtectAnnotation: ""}}}
+	client := fake.NewFakeClient(applied, replaced, protected)
+
+	entry := func(name string) InventoryEntry {
+		return InventoryEntry{Version: "v1", Kind: "ConfigMap", Namespace: "openshift-foo", Name: name}
+	}
+	failed := PruneObjects(context.TODO(), client, []InventoryEntry{entry("applied"), entry("replaced"), entry("protected"), entry("gone")})
+	g.Expect(failed).To(BeEmpty())
+
+	exists := func(name string) bool {
+		err := client.Default().CRClient().Get(context.TODO(), types.NamespacedName{Namespace: "openshift-foo", Name: name}, &corev1.ConfigMap{})
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		g.Expect(err).NotTo(HaveOccurred())
+		return true
+	}
+	// Only the objects labeled as applied by the operator are deleted
+	g.Expect(exists("applied")).To(BeFalse())
+	g.Expect(exists("replaced")).To(BeTrue())
+	g.Expect(exists("protected")).To(BeTrue())
+}
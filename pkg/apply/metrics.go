@@ -0,0 +1,38 @@
+package apply
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var metricApplyDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "openshift_network_operator",
+	Name:      "apply_duration_seconds",
+	Help:      "Time taken to apply a rendered object, labeled with the group, version and kind of the object.",
+	Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+}, []string{"group", "version", "kind"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(metricApplyDuration)
+}
+
+func observeApplyDuration(gvk schema.GroupVersionKind, start time.Time) {
+	metricApplyDuration.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind).Observe(time.Since(start).Seconds())
+}
+
+type metricsDisabledKey struct{}
+
+// WithoutMetrics returns a context under which the applied objects are not
+// observed, for the clusters that must not report the operator's own metrics.
+func WithoutMetrics(ctx context.Context) context.Context {
+	return context.WithValue(ctx, metricsDisabledKey{}, true)
+}
+
+func metricsDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(metricsDisabledKey{}).(bool)
+	return disabled
+}
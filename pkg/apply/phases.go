@@ -0,0 +1,175 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Conditions of the wait-for annotation that are not status conditions of
+// the object.
+const (
+	// WaitForAvailable waits for a DaemonSet, Deployment or StatefulSet to be
+	// completely rolled out. For other kinds, it waits for the Available
+	// status condition.
+	WaitForAvailable = "Available"
+	// WaitForEndpoints waits for a Service to have a ready endpoint.
+	WaitForEndpoints = "Endpoints"
+)
+
+// GetApplyPhase returns the phase of the names.ApplyPhaseAnnotation annotation
+// of an object, or 0 if it is not set.
+func GetApplyPhase(obj Object) (int, error) {
+	value, ok := obj.GetAnnotations()[names.ApplyPhaseAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	phase, err := strconv.Atoi(value)
+	if err != nil || phase < 0 {
+		return 0, fmt.Errorf("invalid %s annotation %q on (%s) %s/%s: must be a non-negative integer",
+			names.ApplyPhaseAnnotation, value, obj.GetObjectKind().GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+	}
+	return phase, nil
+}
+
+// SplitApplyPhases groups objects by apply phase, in increasing phase order.
+// The order of objects within a phase is preserved.
+func SplitApplyPhases(objs []*unstructured.Unstructured) ([][]*unstructured.Unstructured, error) {
+	byPhase := map[int][]*unstructured.Unstructured{}
+	for _, obj := range objs {
+		phase, err := GetApplyPhase(obj)
+		if err != nil {
+			return nil, err
+		}
+		byPhase[phase] = append(byPhase[phase], obj)
+	}
+	phases := make([]int, 0, len(byPhase))
+	for phase := range byPhase {
+		phases = append(phases, phase)
+	}
+	sort.Ints(phases)
+
+	result := make([][]*unstructured.Unstructured, 0, len(phases))
+	for _, phase := range phases {
+		result = append(result, byPhase[phase])
+	}
+	return result, nil
+}
+
+// IsReady checks whether an applied object with the names.WaitForAnnotation
+// annotation is ready. Objects without the annotation are always ready. When
+// the object isn't ready, the returned message says what it is waiting for.
+func IsReady(ctx context.Context, client cnoclient.Client, obj Object) (bool, string, error) {
+	condition, ok := obj.GetAnnotations()[names.WaitForAnnotation]
+	if !ok || condition == "" {
+		return true, "", nil
+	}
+	// A create-wait object is never applied, so there is nothing to wait for
+	if _, ok := obj.GetAnnotations()[names.CreateWaitAnnotation]; ok {
+		return true, "", nil
+	}
+
+	clusterClient := client.ClientFor(GetClusterName(obj))
+	if clusterClient == nil {
+		return false, "", fmt.Errorf("object %s/%s specifies unknown cluster %s", obj.GetNamespace(), obj.GetName(), GetClusterName(obj))
+	}
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	objDesc := fmt.Sprintf("(%s) %s/%s", gvk.String(), obj.GetNamespace(), obj.GetName())
+	rm, err := clusterClient.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to retrieve resource from Object %s: %v", objDesc, err)
+	}
+	current, err := clusterClient.Dynamic().Resource(rm.Resource).Namespace(obj.GetNamespace()).Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, fmt.Sprintf("%s does not exist yet", objDesc), nil
+	} else if err != nil {
+		return false, "", err
+	}
+
+	switch {
+	case condition == WaitForAvailable && gvk.Group == "apps" &&
+		(gvk.Kind == "DaemonSet" || gvk.Kind == "Deployment" || gvk.Kind == "StatefulSet"):
+		if !isRolledOut(current) {
+			return false, fmt.Sprintf("%s is not available yet", objDesc), nil
+		}
+	case condition == WaitForEndpoints && gvk.Group == "" && gvk.Kind == "Service":
+		endpoints, err := clusterClient.Kubernetes().CoreV1().Endpoints(obj.GetNamespace()).Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return false, "", err
+		}
+		ready := false
+		if err == nil {
+			for _, subset := range endpoints.Subsets {
+				if len(subset.Addresses) > 0 {
+					ready = true
+				}
+			}
+		}
+		if !ready {
+			return false, fmt.Sprintf("%s has no ready endpoints yet", objDesc), nil
+		}
+	default:
+		if !hasTrueCondition(current, condition) {
+			return false, fmt.Sprintf("%s is not %s yet", objDesc, condition), nil
+		}
+	}
+	return true, "", nil
+}
+
+// isRolledOut returns true if a DaemonSet, Deployment or StatefulSet has
+// observed its latest generation and all of its pods are updated and available.
+func isRolledOut(obj *unstructured.Unstructured) bool {
+	observedGeneration, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if observedGeneration < obj.GetGeneration() {
+		return false
+	}
+
+	switch obj.GetKind() {
+	case "DaemonSet":
+		desired, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+		updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedNumberScheduled")
+		unavailable, _, _ := unstructured.NestedInt64(obj.Object, "status", "numberUnavailable")
+		return updated >= desired && unavailable == 0
+	case "Deployment":
+		replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		if !found {
+			replicas = 1
+		}
+		updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+		available, _, _ := unstructured.NestedInt64(obj.Object, "status", "availableReplicas")
+		return updated >= replicas && available >= replicas
+	case "StatefulSet":
+		replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		if !found {
+			replicas = 1
+		}
+		updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		return updated >= replicas && ready >= replicas
+	}
+	return false
+}
+
+// hasTrueCondition returns true if the status of an object has a condition of
+// the given type with status True.
+func hasTrueCondition(obj *unstructured.Unstructured, conditionType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == conditionType && condition["status"] == string(metav1.ConditionTrue) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package apply
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/cluster-network-operator/pkg/names"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func phaseObject(name, phase string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName(name)
+	if phase != "" {
+		obj.SetAnnotations(map[string]string{names.ApplyPhaseAnnotation: phase})
+	}
+	return obj
+}
+
+func TestSplitApplyPhases(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := phaseObject("a", "")
+	b := phaseObject("b", "2")
+	c := phaseObject("c", "0")
+	d := phaseObject("d", "1")
+	e := phaseObject("e", "2")
+
+	phases, err := SplitApplyPhases([]*unstructured.Unstructured{a, b, c, d, e})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(phases).To(Equal([][]*unstructured.Unstructured{{a, c}, {d}, {b, e}}))
+
+	// Without annotations, everything is applied in a single phase
+	phases, err = SplitApplyPhases([]*unstructured.Unstructured{a, c})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(phases).To(HaveLen(1))
+
+	_, err = SplitApplyPhases([]*unstructured.Unstructured{a, phaseObject("f", "-1")})
+	g.Expect(err).To(MatchError(ContainSubstring("must be a non-negative integer")))
+	_, err = SplitApplyPhases([]*unstructured.Unstructured{a, phaseObject("f", "first")})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestIsRolledOut(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ds := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "DaemonSet",
+		"metadata":   map[string]interface{}{"name": "ds", "generation": int64(2)},
+		"status": map[string]interface{}{
+			"observedGeneration":     int64(2),
+			"desiredNumberScheduled": int64(3),
+			"updatedNumberScheduled": int64(3),
+			"numberUnavailable":      int64(1),
+		},
+	}}
+	g.Expect(isRolledOut(ds)).To(BeFalse())
+	g.Expect(unstructured.SetNestedField(ds.Object, int64(0), "status", "numberUnavailable")).To(Succeed())
+	g.Expect(isRolledOut(ds)).To(BeTrue())
+	ds.SetGeneration(3)
+	g.Expect(isRolledOut(ds)).To(BeFalse())
+
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "deployment"},
+		"spec":       map[string]interface{}{"replicas": int64(2)},
+		"status": map[string]interface{}{
+			"updatedReplicas":   int64(2),
+			"availableReplicas": int64(1),
+		},
+	}}
+	g.Expect(isRolledOut(deployment)).To(BeFalse())
+	g.Expect(unstructured.SetNestedField(deployment.Object, int64(2), "status", "availableReplicas")).To(Succeed())
+	g.Expect(isRolledOut(deployment)).To(BeTrue())
+}
+
+func TestHasTrueCondition(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "NamesAccepted", "status": "True"},
+				map[string]interface{}{"type": "Established", "status": "False"},
+			},
+		},
+	}}
+	g.Expect(hasTrueCondition(crd, "NamesAccepted")).To(BeTrue())
+	g.Expect(hasTrueCondition(crd, "Established")).To(BeFalse())
+	g.Expect(hasTrueCondition(crd, "Available")).To(BeFalse())
+}
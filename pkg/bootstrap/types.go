@@ -3,22 +3,31 @@ package bootstrap
 import (
 	configv1 "github.com/openshift/api/config/v1"
 	mcfgv1 "github.com/openshift/api/machineconfiguration/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/openshift/cluster-network-operator/pkg/hypershift"
 )
 
 type OVNHyperShiftBootstrapResult struct {
-	Enabled              bool
-	ClusterID            string
-	Namespace            string
-	HCPNodeSelector      map[string]string
-	HCPLabels            map[string]string
-	HCPTolerations       []string
-	ControlPlaneReplicas int
-	ReleaseImage         string
-	ControlPlaneImage    string
-	CAConfigMap          string
-	CAConfigMapKey       string
+	Enabled         bool
+	ClusterID       string
+	Namespace       string
+	HCPNodeSelector map[string]string
+	HCPLabels       map[string]string
+	HCPTolerations  []string
+	// HCPAnnotations, HCPTopologySpreadConstraints, HCPPodAffinity,
+	// HCPPodAntiAffinity and HCPResourceRequests are the scheduling options of
+	// the HostedControlPlane for ovnkube-control-plane
+	HCPAnnotations               map[string]string
+	HCPTopologySpreadConstraints []string
+	HCPPodAffinity               hypershift.AffinityTerms
+	HCPPodAntiAffinity           hypershift.AffinityTerms
+	HCPResourceRequests          map[string]map[string]string
+	ControlPlaneReplicas         int
+	ReleaseImage                 string
+	ControlPlaneImage            string
+	CAConfigMap                  string
+	CAConfigMapKey               string
 }
 
 type OVNConfigBoostrapResult struct {
@@ -32,6 +41,10 @@ type OVNConfigBoostrapResult struct {
 	SmartNicModeLabel     string
 	SmartNicModeNodes     []string
 	MgmtPortResourceName  string
+	// NodeCanaryLabel is the label of the nodes that ovnkube-node changes
+	// are rolled out to first. Empty if canary rollouts are disabled.
+	NodeCanaryLabel string
+	NodeCanaryNodes []string
 }
 
 // OVNUpdateStatus contains the status of existing daemonset
@@ -44,6 +57,15 @@ type OVNUpdateStatus struct {
 	IPFamilyMode        string
 	ClusterNetworkCIDRs string
 	Progressing         bool
+	// ServiceNetworkCIDRs are the service network CIDRs the object was
+	// last rendered with
+	ServiceNetworkCIDRs string
+	// TemplateHash is the hash of the ovnkube-node pod template the
+	// daemonset was last updated with. Only set for ovnkube-node.
+	TemplateHash string
+	// CanaryLabel is the canary node label the ovnkube-node daemonset
+	// was last rendered for. Only set for ovnkube-node.
+	CanaryLabel string
 }
 
 // OVNIPsecStatus contains status of current IPsec configuration
@@ -66,7 +88,12 @@ type OVNBootstrapResult struct {
 	// IPsecUpdateStatus is the status of ovn-ipsec config
 	IPsecUpdateStatus *OVNIPsecStatus
 	// PrePullerUpdateStatus is the status of ovnkube-upgrades-prepuller daemonset
-	PrePullerUpdateStatus     *OVNUpdateStatus
+	PrePullerUpdateStatus *OVNUpdateStatus
+	// NodeCanaryUpdateStatus is the status of ovnkube-node-canary daemonset
+	NodeCanaryUpdateStatus *OVNUpdateStatus
+	// NodeCanaryFailedChecks are the PodNetworkConnectivityChecks from canary
+	// nodes whose target is currently unreachable
+	NodeCanaryFailedChecks    []string
 	OVNKubernetesConfig       *OVNConfigBoostrapResult
 	FlowsConfig               *FlowsConfig
 	DefaultV4MasqueradeSubnet string
@@ -79,11 +106,68 @@ type IPTablesAlerterBootstrapResult struct {
 	Enabled bool
 }
 
+// ExternalNetworkBootstrapResult describes a default network deployed by a
+// third party rather than by the operator.
+type ExternalNetworkBootstrapResult struct {
+	// Enabled is true if the external network mode is configured
+	Enabled bool
+	// DaemonSets are the DaemonSets of the third-party CNI, which are
+	// included in the operator status
+	DaemonSets []types.NamespacedName
+	// CNIConfigFile is the name of the configuration file the third-party
+	// CNI writes once it is ready
+	CNIConfigFile string
+	// CNIConfigDir is the host directory the third-party CNI writes
+	// CNIConfigFile to, or "" for the directory multus reads it from
+	CNIConfigDir string
+	// MTU is the MTU of the cluster network, or 0 if unknown
+	MTU int
+}
+
+// ServiceNetworkMigrationBootstrapResult describes the phase of an in-progress
+// migration of the service network.
+type ServiceNetworkMigrationBootstrapResult struct {
+	// AdditionalServiceNetworks are rendered in the ovnkube configuration
+	// alongside spec.serviceNetwork
+	AdditionalServiceNetworks []string
+	// ServiceCIDRs are the additional service networks that the apiserver
+	// may allocate cluster IPs from
+	ServiceCIDRs []string
+	// OriginalKubernetesService, if set, keeps the cluster IP that the
+	// apiserver Service had in the original service network, for the pods
+	// started before it was recreated in the target
+	OriginalKubernetesService *OriginalKubernetesService
+	// OriginalDNSServiceIP, if set, is the cluster IP that the cluster DNS
+	// Service had in the original service network, kept for the pods
+	// started before the kubelets were rolled out with the target
+	OriginalDNSServiceIP string
+}
+
+// OriginalKubernetesService is the apiserver Service in the original service
+// network, with the endpoints of the apiserver.
+type OriginalKubernetesService struct {
+	ClusterIP   string
+	AddressType string
+	Addresses   []string
+	Port        int32
+}
+
+// OperandOverridesBootstrapResult are the overrides of the rendered DaemonSets
+// and Deployments.
+type OperandOverridesBootstrapResult struct {
+	// Data is the data of the openshift-network-operator/operand-overrides
+	// ConfigMap, one YAML override per operand name
+	Data map[string]string
+}
+
 type BootstrapResult struct {
 	Infra InfraStatus
 
-	OVN             OVNBootstrapResult
-	IPTablesAlerter IPTablesAlerterBootstrapResult
+	OVN                     OVNBootstrapResult
+	IPTablesAlerter         IPTablesAlerterBootstrapResult
+	ExternalNetwork         ExternalNetworkBootstrapResult
+	ServiceNetworkMigration ServiceNetworkMigrationBootstrapResult
+	OperandOverrides        OperandOverridesBootstrapResult
 }
 
 type InfraStatus struct {
@@ -126,6 +210,14 @@ type InfraStatus struct {
 
 	// ConsolePluginCRDExists set to true when the consoleplugins.console.openshift.io has been deployed.
 	ConsolePluginCRDExists bool
+
+	// MachineNetworks are the machine network CIDRs of the install-config, if any.
+	// Only set by the operconfig controller, from its informers.
+	MachineNetworks []string
+
+	// NodeSubnets maps the name of each node to the pod subnets allocated to
+	// it by ovn-kubernetes. Only set by the operconfig controller, from its informers.
+	NodeSubnets map[string][]string
 }
 
 // APIServer is the hostname & port of a given APIServer. (This is the
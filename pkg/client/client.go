@@ -107,15 +107,7 @@ func NewClient(cfg, protocfg *rest.Config, inClusterClientName string, extraClus
 	cli.clusterClients[inClusterClientName] = inClusterClient
 
 	for name, kubeConfig := range extraClusters {
-		clientConfig, err := clientConfig.GetClientConfig(kubeConfig, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get config for cluster %s from %s: %w", name, kubeConfig, err)
-		}
-		protoConfig := rest.CopyConfig(clientConfig)
-		protoConfig.AcceptContentTypes = "application/vnd.kubernetes.protobuf,application/json"
-		protoConfig.ContentType = "application/vnd.kubernetes.protobuf"
-
-		clusterCli, err := NewClusterClient(clientConfig, protoConfig)
+		clusterCli, err := NewClusterClientForKubeConfig(kubeConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed create new cluster client for cluster %s: %w", name, err)
 		}
@@ -124,6 +116,20 @@ func NewClient(cfg, protocfg *rest.Config, inClusterClientName string, extraClus
 	return cli, nil
 }
 
+// NewClusterClientForKubeConfig returns a ClusterClient for the cluster
+// described by the kubeconfig file at the given path.
+func NewClusterClientForKubeConfig(kubeConfig string) (*OperatorClusterClient, error) {
+	clientConfig, err := clientConfig.GetClientConfig(kubeConfig, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config from %s: %w", kubeConfig, err)
+	}
+	protoConfig := rest.CopyConfig(clientConfig)
+	protoConfig.AcceptContentTypes = "application/vnd.kubernetes.protobuf,application/json"
+	protoConfig.ContentType = "application/vnd.kubernetes.protobuf"
+
+	return NewClusterClient(clientConfig, protoConfig)
+}
+
 type OperatorClient struct {
 	clusterClients map[string]*OperatorClusterClient
 }
@@ -161,6 +167,39 @@ func (c *OperatorClient) Clients() map[string]ClusterClient {
 	return out
 }
 
+// NewSingleClusterClient returns a Client whose only, and so default, cluster
+// is cc. It is used to reconcile an extra cluster as if the operator was
+// running in it.
+func NewSingleClusterClient(cc *OperatorClusterClient) Client {
+	return &singleClusterClient{clusterClient: cc}
+}
+
+type singleClusterClient struct {
+	clusterClient *OperatorClusterClient
+}
+
+// ensure singleClusterClient implements Client
+var _ Client = &singleClusterClient{}
+
+func (c *singleClusterClient) ClientFor(name string) ClusterClient {
+	if len(name) == 0 || name == names.DefaultClusterName {
+		return c.clusterClient
+	}
+	return nil
+}
+
+func (c *singleClusterClient) Default() ClusterClient {
+	return c.clusterClient
+}
+
+func (c *singleClusterClient) Start(ctx context.Context) error {
+	return c.clusterClient.Start(ctx)
+}
+
+func (c *singleClusterClient) Clients() map[string]ClusterClient {
+	return map[string]ClusterClient{names.DefaultClusterName: c.clusterClient}
+}
+
 func NewClusterClient(cfg, protocfg *rest.Config) (*OperatorClusterClient, error) {
 	cfgCopy := rest.CopyConfig(cfg)
 	protoCfgCopy := rest.CopyConfig(protocfg)
@@ -137,8 +137,14 @@ func (f *fakeRESTMapper) ResourcesFor(input schema.GroupVersionResource) ([]sche
 	return nil, nil
 }
 
+// RESTMapping guesses the resource of a kind, and assumes it is namespaced
 func (f *fakeRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
-	return nil, nil
+	gvk := gk.WithVersion("")
+	if len(versions) > 0 {
+		gvk.Version = versions[0]
+	}
+	resource, _ := meta.UnsafeGuessKindToResource(gvk)
+	return &meta.RESTMapping{Resource: resource, GroupVersionKind: gvk, Scope: meta.RESTScopeNamespace}, nil
 }
 
 func (f *fakeRESTMapper) RESTMappings(gk schema.GroupKind, versions ...string) ([]*meta.RESTMapping, error) {
@@ -174,7 +180,7 @@ func (fc *FakeClusterClient) RESTMapper() meta.RESTMapper {
 }
 
 func (fc *FakeClusterClient) Scheme() *runtime.Scheme {
-	panic("not implemented!")
+	return scheme.Scheme
 }
 func (fc *FakeClusterClient) OperatorHelperClient() operatorv1helpers.OperatorClient {
 	panic("not implemented!")
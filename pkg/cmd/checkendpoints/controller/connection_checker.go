@@ -3,9 +3,11 @@ package controller
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"regexp"
+	"strings"
 	"time"
 
 	operatorcontrolplanev1alpha1 "github.com/openshift/api/operatorcontrolplane/v1alpha1"
@@ -127,7 +129,7 @@ func (c *connectionChecker) updateStatus(ctx context.Context, flush bool) {
 
 // checkEndpoint performs the check and manages the PodNetworkConnectivityCheck.Status changes that result.
 func (c *connectionChecker) checkEndpoint(ctx context.Context, check *operatorcontrolplanev1alpha1.PodNetworkConnectivityCheck) {
-	latencyInfo, err := c.getTCPConnectLatency(ctx, check.Spec.TargetEndpoint)
+	latencyInfo, err := c.probe(ctx, check.Spec.TargetEndpoint)
 	statusUpdates, timestamp := manageStatusLogs(check, err, latencyInfo)
 	if len(statusUpdates) > 0 {
 		statusUpdates = append(statusUpdates, manageStatusOutage(c.recorder))
@@ -140,8 +142,6 @@ func (c *connectionChecker) checkEndpoint(ctx context.Context, check *operatorco
 
 // getTCPConnectLatency connects to a tcp endpoint and collects latency info
 func (c *connectionChecker) getTCPConnectLatency(ctx context.Context, address string) (*trace.LatencyInfo, error) {
-	klog.V(4).Infof("Check BEGIN: %v", address)
-	defer klog.V(4).Infof("Check END  : %v", address)
 	ctx, latencyInfo := trace.WithLatencyInfoCapture(ctx)
 
 	// tcp connection
@@ -150,7 +150,6 @@ func (c *connectionChecker) getTCPConnectLatency(ctx context.Context, address st
 	}
 	tcpConn, err := dialer.DialContext(ctx, "tcp", address)
 	if err != nil {
-		c.metrics.Update(address, latencyInfo, err)
 		return latencyInfo, err
 	}
 
@@ -161,25 +160,19 @@ func (c *connectionChecker) getTCPConnectLatency(ctx context.Context, address st
 		// ignore any error. most likely non-tls connection, plus we're not really testing tls
 		klog.V(4).Infof("%s: tls error ignored: %v", address, err)
 		_ = tcpConn.Close()
-		c.metrics.Update(address, latencyInfo, nil)
 		return latencyInfo, nil
 	}
 
 	// gracefully close connection (ignore error)
 	_ = tlsConn.Close()
 
-	c.metrics.Update(address, latencyInfo, err)
 	return latencyInfo, err
 }
 
 // isDNSError returns true if the cause of the net operation error is a DNS error
 func isDNSError(err error) bool {
-	if opErr, ok := err.(*net.OpError); ok {
-		if _, ok := opErr.Err.(*net.DNSError); ok {
-			return true
-		}
-	}
-	return false
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
 }
 
 // manageStatusLogs returns status update functions that updates the PodNetworkConnectivityCheck.Status's
@@ -187,7 +180,14 @@ func isDNSError(err error) bool {
 func manageStatusLogs(check *operatorcontrolplanev1alpha1.PodNetworkConnectivityCheck, checkErr error, latency *trace.LatencyInfo) ([]v1alpha1helpers.UpdateStatusFunc, time.Time) {
 	var statusUpdates []v1alpha1helpers.UpdateStatusFunc
 	description := regexp.MustCompile(".*-to-").ReplaceAllString(check.Name, "")
-	host, _, _ := net.SplitHostPort(check.Spec.TargetEndpoint)
+	target, err := parseTarget(check.Spec.TargetEndpoint)
+	if err != nil {
+		target = &checkTarget{scheme: schemeTCP, address: check.Spec.TargetEndpoint}
+	}
+	host := target.host
+	if target.scheme == schemeDNS {
+		return manageDNSStatusLogs(description, target, checkErr, latency)
+	}
 	if isDNSError(checkErr) {
 		klog.V(2).Infof("%7s | %-15s | %10s | Failure looking up host %s: %v", "Failure", "DNSError", latency.DNS, host, checkErr)
 		return append(statusUpdates, v1alpha1helpers.AddFailureLogEntry(operatorcontrolplanev1alpha1.LogEntry{
@@ -213,26 +213,98 @@ func manageStatusLogs(check *operatorcontrolplanev1alpha1.PodNetworkConnectivity
 	if overallStart.IsZero() {
 		overallStart = latency.ConnectStart
 	}
+	result := probeResultFor(target, checkErr, latency)
 	if checkErr != nil {
-		klog.V(2).Infof("%7s | %-15s | %10s | Failed to establish a TCP connection to %s: %v", "Failure", "TCPConnectError", latency.Connect, check.Spec.TargetEndpoint, checkErr)
+		klog.V(2).Infof("%7s | %-15s | %10s | Failed to %s %s: %v", "Failure", result.reason, result.latency, result.action, check.Spec.TargetEndpoint, checkErr)
 		return append(statusUpdates, v1alpha1helpers.AddFailureLogEntry(operatorcontrolplanev1alpha1.LogEntry{
 			Start:   metav1.NewTime(latency.ConnectStart),
 			Success: false,
-			Reason:  operatorcontrolplanev1alpha1.LogEntryReasonTCPConnectError,
-			Message: fmt.Sprintf("%s: failed to establish a TCP connection to %s: %v", description, check.Spec.TargetEndpoint, checkErr),
-			Latency: metav1.Duration{Duration: latency.Connect},
+			Reason:  result.reason,
+			Message: fmt.Sprintf("%s: failed to %s %s: %v", description, result.action, check.Spec.TargetEndpoint, checkErr),
+			Latency: metav1.Duration{Duration: result.latency},
 		})), overallStart
 	}
-	klog.V(2).Infof("%7s | %-15s | %10s | TCP connection to %v succeeded", "Success", "TCPConnect", latency.Connect, check.Spec.TargetEndpoint)
+	klog.V(2).Infof("%7s | %-15s | %10s | %s to %v succeeded%s", "Success", result.reason, result.latency, result.success, check.Spec.TargetEndpoint, result.details)
 	return append(statusUpdates, v1alpha1helpers.AddSuccessLogEntry(operatorcontrolplanev1alpha1.LogEntry{
 		Start:   metav1.NewTime(latency.ConnectStart),
 		Success: true,
-		Reason:  operatorcontrolplanev1alpha1.LogEntryReasonTCPConnect,
-		Message: fmt.Sprintf("%s: tcp connection to %s succeeded", description, check.Spec.TargetEndpoint),
-		Latency: metav1.Duration{Duration: latency.Connect},
+		Reason:  result.reason,
+		Message: fmt.Sprintf("%s: %s to %s succeeded%s", description, result.success, check.Spec.TargetEndpoint, result.details),
+		Latency: metav1.Duration{Duration: result.latency},
 	})), overallStart
 }
 
+// probeResult describes the result of a check for the status logs.
+type probeResult struct {
+	reason string
+	// action is what failed, e.g. "establish a TCP connection to"
+	action string
+	// success is what succeeded, e.g. "tcp connection"
+	success string
+	// details is appended to the success message
+	details string
+	latency time.Duration
+}
+
+// probeResultFor returns how to log the result of a check of the given target.
+func probeResultFor(target *checkTarget, checkErr error, latency *trace.LatencyInfo) probeResult {
+	switch target.scheme {
+	case schemeHTTP, schemeHTTPS:
+		r := probeResult{
+			reason:  LogEntryReasonHTTPRequest,
+			action:  fmt.Sprintf("send an %s request to", strings.ToUpper(target.scheme)),
+			success: fmt.Sprintf("%s request", target.scheme),
+			latency: latency.Connect + latency.TLS + latency.TTFB,
+		}
+		if checkErr != nil {
+			r.reason = LogEntryReasonHTTPRequestError
+		}
+		r.details = fmt.Sprintf(" (connect %v, tls %v, time to first byte %v)", latency.Connect, latency.TLS, latency.TTFB)
+		return r
+	case schemeUDP:
+		r := probeResult{reason: LogEntryReasonUDPProbe, action: "send a UDP datagram to", success: "udp probe", latency: latency.Connect}
+		if checkErr != nil {
+			r.reason = LogEntryReasonUDPProbeError
+		}
+		return r
+	case schemeICMP:
+		r := probeResult{reason: LogEntryReasonICMPEcho, action: "get an ICMP echo reply from", success: "icmp echo", latency: latency.Connect}
+		if checkErr != nil {
+			r.reason = LogEntryReasonICMPEchoError
+		}
+		return r
+	default:
+		r := probeResult{reason: operatorcontrolplanev1alpha1.LogEntryReasonTCPConnect, action: "establish a TCP connection to", success: "tcp connection", latency: latency.Connect}
+		if checkErr != nil {
+			r.reason = operatorcontrolplanev1alpha1.LogEntryReasonTCPConnectError
+		}
+		return r
+	}
+}
+
+// manageDNSStatusLogs returns the status update functions for the result of a
+// dns check, which only consists of a DNS lookup.
+func manageDNSStatusLogs(description string, target *checkTarget, checkErr error, latency *trace.LatencyInfo) ([]v1alpha1helpers.UpdateStatusFunc, time.Time) {
+	if checkErr != nil {
+		klog.V(2).Infof("%7s | %-15s | %10s | Failure looking up %s using %s: %v", "Failure", "DNSError", latency.DNS, target.query, target.address, checkErr)
+		return []v1alpha1helpers.UpdateStatusFunc{v1alpha1helpers.AddFailureLogEntry(operatorcontrolplanev1alpha1.LogEntry{
+			Start:   metav1.NewTime(latency.DNSStart),
+			Success: false,
+			Reason:  operatorcontrolplanev1alpha1.LogEntryReasonDNSError,
+			Message: fmt.Sprintf("%s: failure looking up %s using %s: %v", description, target.query, target.address, checkErr),
+			Latency: metav1.Duration{Duration: latency.DNS},
+		})}, latency.DNSStart
+	}
+	klog.V(2).Infof("%7s | %-15s | %10s | Resolved %s using %s successfully", "Success", "DNSResolve", latency.DNS, target.query, target.address)
+	return []v1alpha1helpers.UpdateStatusFunc{v1alpha1helpers.AddSuccessLogEntry(operatorcontrolplanev1alpha1.LogEntry{
+		Start:   metav1.NewTime(latency.DNSStart),
+		Success: true,
+		Reason:  operatorcontrolplanev1alpha1.LogEntryReasonDNSResolve,
+		Message: fmt.Sprintf("%s: resolved %s using %s successfully", description, target.query, target.address),
+		Latency: metav1.Duration{Duration: latency.DNS},
+	})}, latency.DNSStart
+}
+
 // manageStatusOutage returns a status update function that manages the
 // PodNetworkConnectivityCheck.Status.Outage entries based on Successes/Failures log entries.
 func manageStatusOutage(recorder Recorder) v1alpha1helpers.UpdateStatusFunc {
@@ -310,6 +382,9 @@ func manageStatusConditions(status *operatorcontrolplanev1alpha1.PodNetworkConne
 		}
 		reachableCondition.Status = metav1.ConditionTrue
 		reachableCondition.Reason = "TCPConnectSuccess"
+		if latestSuccessLogEntry.Reason != "" {
+			reachableCondition.Reason = latestSuccessLogEntry.Reason + "Success"
+		}
 		reachableCondition.Message = latestSuccessLogEntry.Message
 	} else {
 		var latestFailureLogEntry operatorcontrolplanev1alpha1.LogEntry
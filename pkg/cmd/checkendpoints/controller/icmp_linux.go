@@ -0,0 +1,90 @@
+//go:build linux
+// +build linux
+
+package controller
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+const (
+	icmpv4EchoRequest = 8
+	icmpv4EchoReply   = 0
+	icmpv6EchoRequest = 128
+	icmpv6EchoReply   = 129
+
+	icmpEchoSeq = 1
+)
+
+// icmpEcho sends an ICMP echo request to ip and waits for the reply. It uses an
+// unprivileged ICMP socket, which requires the group of the process to be in
+// the net.ipv4.ping_group_range sysctl, rather than a raw socket.
+func icmpEcho(ctx context.Context, ip net.IP) error {
+	family, proto, echoRequest, echoReply := syscall.AF_INET, syscall.IPPROTO_ICMP, byte(icmpv4EchoRequest), byte(icmpv4EchoReply)
+	if ip.To4() == nil {
+		family, proto, echoRequest, echoReply = syscall.AF_INET6, syscall.IPPROTO_ICMPV6, icmpv6EchoRequest, icmpv6EchoReply
+	}
+
+	fd, err := syscall.Socket(family, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, proto)
+	if err != nil {
+		return &net.OpError{Op: "dial", Net: "icmp", Err: os.NewSyscallError("socket", err)}
+	}
+	f := os.NewFile(uintptr(fd), "icmp")
+	conn, err := net.FilePacketConn(f)
+	_ = f.Close()
+	if err != nil {
+		return &net.OpError{Op: "dial", Net: "icmp", Err: err}
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(checkTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	// The kernel sets the identifier of unprivileged ICMP sockets, and
+	// computes the checksum of ICMPv6 messages.
+	msg := []byte{echoRequest, 0, 0, 0, 0, 0, 0, icmpEchoSeq}
+	msg = append(msg, "check-endpoints"...)
+	if family == syscall.AF_INET {
+		binary.BigEndian.PutUint16(msg[2:], icmpChecksum(msg))
+	}
+	if _, err := conn.WriteTo(msg, &net.UDPAddr{IP: ip}); err != nil {
+		return err
+	}
+
+	// Skip anything that is not the reply to our request
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+		if n >= 8 && buf[0] == echoReply && binary.BigEndian.Uint16(buf[6:8]) == icmpEchoSeq {
+			return nil
+		}
+	}
+}
+
+// icmpChecksum computes the internet checksum (RFC 1071) of an ICMP message.
+func icmpChecksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(b[i])<<8 | uint32(b[i+1])
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum > 0xffff {
+		sum = (sum >> 16) + (sum & 0xffff)
+	}
+	return ^uint16(sum)
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+func icmpEcho(ctx context.Context, ip net.IP) error {
+	return fmt.Errorf("icmp checks are only supported on linux")
+}
@@ -11,9 +11,11 @@ import (
 var (
 	registerMetrics sync.Once
 
-	endpointCheckCounter   *metrics.CounterVec
-	tcpConnectLatencyGauge *metrics.GaugeVec
-	dnsResolveLatencyGauge *metrics.GaugeVec
+	endpointCheckCounter     *metrics.CounterVec
+	tcpConnectLatencyGauge   *metrics.GaugeVec
+	dnsResolveLatencyGauge   *metrics.GaugeVec
+	tlsHandshakeLatencyGauge *metrics.GaugeVec
+	timeToFirstByteGauge     *metrics.GaugeVec
 )
 
 // RegisterMetrics in the global registry
@@ -33,9 +35,21 @@ func RegisterMetrics() {
 			Name: "pod_network_connectivity_check_dns_resolve_latency_gauge",
 			Help: "Report latency of DNS resolve of target endpoint over time.",
 		}, []string{"component", "checkName", "targetEndpoint"})
+
+		tlsHandshakeLatencyGauge = metrics.NewGaugeVec(&metrics.GaugeOpts{
+			Name: "pod_network_connectivity_check_tls_handshake_latency_gauge",
+			Help: "Report latency of the TLS handshake of HTTPS requests to target endpoint over time.",
+		}, []string{"component", "checkName", "targetEndpoint"})
+
+		timeToFirstByteGauge = metrics.NewGaugeVec(&metrics.GaugeOpts{
+			Name: "pod_network_connectivity_check_time_to_first_byte_gauge",
+			Help: "Report time to first byte of the response to HTTP requests to target endpoint over time.",
+		}, []string{"component", "checkName", "targetEndpoint"})
 		legacyregistry.MustRegister(endpointCheckCounter)
 		legacyregistry.MustRegister(tcpConnectLatencyGauge)
 		legacyregistry.MustRegister(dnsResolveLatencyGauge)
+		legacyregistry.MustRegister(tlsHandshakeLatencyGauge)
+		legacyregistry.MustRegister(timeToFirstByteGauge)
 	})
 }
 
@@ -67,6 +81,12 @@ func (m *metricsContext) Update(targetEndpoint string, latency *trace.LatencyInf
 	if latency.DNS > 0 {
 		dnsResolveLatencyGauge.With(m.getMetricLabels(targetEndpoint)).Set(float64(latency.DNS.Nanoseconds()))
 	}
+	if latency.TLS > 0 {
+		tlsHandshakeLatencyGauge.With(m.getMetricLabels(targetEndpoint)).Set(float64(latency.TLS.Nanoseconds()))
+	}
+	if latency.TTFB > 0 {
+		timeToFirstByteGauge.With(m.getMetricLabels(targetEndpoint)).Set(float64(latency.TTFB.Nanoseconds()))
+	}
 }
 
 func (m *metricsContext) getCounterMetricLabels(targetEndpoint string, latency *trace.LatencyInfo, checkErr error) map[string]string {
@@ -0,0 +1,254 @@
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/cluster-network-operator/pkg/cmd/checkendpoints/trace"
+)
+
+const (
+	schemeTCP   = "tcp"
+	schemeHTTP  = "http"
+	schemeHTTPS = "https"
+	schemeUDP   = "udp"
+	schemeDNS   = "dns"
+	schemeICMP  = "icmp"
+
+	// defaultDNSQuery is the name looked up by dns:// checks that don't name one
+	defaultDNSQuery = "kubernetes.default.svc.cluster.local."
+
+	// udpReplyTimeout is how long udp:// checks wait for an ICMP port unreachable
+	udpReplyTimeout = 2 * time.Second
+)
+
+// Log entry reasons of the checks other than tcp and dns, which use the
+// reasons defined by the API.
+const (
+	LogEntryReasonHTTPRequest      = "HTTPRequest"
+	LogEntryReasonHTTPRequestError = "HTTPRequestError"
+	LogEntryReasonUDPProbe         = "UDPProbe"
+	LogEntryReasonUDPProbeError    = "UDPProbeError"
+	LogEntryReasonICMPEcho         = "ICMPEcho"
+	LogEntryReasonICMPEchoError    = "ICMPEchoError"
+)
+
+// checkTarget is a parsed PodNetworkConnectivityCheck target endpoint. Plain
+// host:port endpoints are tcp targets, other protocols are selected with a
+// scheme: http://host:port, https://host:port, udp://host:port,
+// dns://[name@]server:port and icmp://host:.
+type checkTarget struct {
+	scheme string
+	// host is the host name or IP of the target
+	host string
+	// address is host:port, or host for icmp
+	address string
+	// url is the URL requested by http and https checks
+	url string
+	// query is the name looked up by dns checks
+	query string
+}
+
+// parseTarget parses a PodNetworkConnectivityCheck target endpoint.
+func parseTarget(endpoint string) (*checkTarget, error) {
+	if !strings.Contains(endpoint, "://") {
+		host, _, err := net.SplitHostPort(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		return &checkTarget{scheme: schemeTCP, host: host, address: endpoint}, nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	t := &checkTarget{scheme: strings.ToLower(u.Scheme), host: u.Hostname()}
+	if t.host == "" {
+		return nil, fmt.Errorf("no host in target endpoint %q", endpoint)
+	}
+	port := u.Port()
+
+	switch t.scheme {
+	case schemeHTTP, schemeHTTPS:
+		u.User = nil
+		t.url = u.String()
+	case schemeDNS:
+		if port == "" {
+			port = "53"
+		}
+		t.query = defaultDNSQuery
+		if u.User != nil && u.User.Username() != "" {
+			t.query = u.User.Username()
+		}
+	case schemeUDP:
+		if port == "" {
+			return nil, fmt.Errorf("no port in target endpoint %q", endpoint)
+		}
+	case schemeICMP:
+		t.address = t.host
+		return t, nil
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q in target endpoint %q", u.Scheme, endpoint)
+	}
+
+	if port == "" {
+		t.address = u.Host
+	} else {
+		t.address = net.JoinHostPort(t.host, port)
+	}
+	return t, nil
+}
+
+// probe checks the target endpoint with the protocol of its scheme and collects latency info
+func (c *connectionChecker) probe(ctx context.Context, endpoint string) (*trace.LatencyInfo, error) {
+	klog.V(4).Infof("Check BEGIN: %v", endpoint)
+	defer klog.V(4).Infof("Check END  : %v", endpoint)
+
+	target, err := parseTarget(endpoint)
+	if err != nil {
+		latencyInfo := &trace.LatencyInfo{ConnectStart: time.Now()}
+		c.metrics.Update(endpoint, latencyInfo, err)
+		return latencyInfo, err
+	}
+
+	var latencyInfo *trace.LatencyInfo
+	switch target.scheme {
+	case schemeHTTP, schemeHTTPS:
+		latencyInfo, err = c.getHTTPLatency(ctx, target)
+	case schemeUDP:
+		latencyInfo, err = getUDPLatency(ctx, target)
+	case schemeDNS:
+		latencyInfo, err = getDNSLatency(ctx, target)
+	case schemeICMP:
+		latencyInfo, err = getICMPLatency(ctx, target)
+	default:
+		latencyInfo, err = c.getTCPConnectLatency(ctx, target.address)
+	}
+	c.metrics.Update(endpoint, latencyInfo, err)
+	return latencyInfo, err
+}
+
+// httpStatusError is returned by http checks when the server responds with an error.
+type httpStatusError struct {
+	status string
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("server responded with %s", e.status)
+}
+
+// getHTTPLatency sends a GET request to an http(s) endpoint and collects latency
+// info. Server errors (5xx) are failures, any other response is a success.
+func (c *connectionChecker) getHTTPLatency(ctx context.Context, target *checkTarget) (*trace.LatencyInfo, error) {
+	ctx, latencyInfo := trace.WithLatencyInfoCapture(ctx)
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:             http.ProxyFromEnvironment,
+			DialContext:       (&net.Dialer{Timeout: checkTimeout}).DialContext,
+			TLSClientConfig:   &tls.Config{Certificates: c.clientCertGetter(), InsecureSkipVerify: true},
+			DisableKeepAlives: true,
+		},
+		// Check the endpoint itself, not wherever it redirects to
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.url, nil)
+	if err != nil {
+		return latencyInfo, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return latencyInfo, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return latencyInfo, &httpStatusError{status: resp.Status}
+	}
+	return latencyInfo, nil
+}
+
+// getUDPLatency sends a datagram to a udp endpoint. Since most UDP services
+// don't reply to unexpected datagrams, the check only fails if the target
+// reports the port as unreachable.
+func getUDPLatency(ctx context.Context, target *checkTarget) (*trace.LatencyInfo, error) {
+	ctx, latencyInfo := trace.WithLatencyInfoCapture(ctx)
+
+	dialer := &net.Dialer{
+		Timeout: checkTimeout,
+	}
+	conn, err := dialer.DialContext(ctx, "udp", target.address)
+	if err != nil {
+		return latencyInfo, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(udpReplyTimeout)); err != nil {
+		return latencyInfo, err
+	}
+	if _, err := conn.Write([]byte{0}); err != nil {
+		return latencyInfo, err
+	}
+	buf := make([]byte, 1)
+	if _, err := conn.Read(buf); err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			// no reply, and no ICMP error either
+			return latencyInfo, nil
+		}
+		return latencyInfo, err
+	}
+	return latencyInfo, nil
+}
+
+// getDNSLatency looks up the query name of a dns endpoint using the DNS server
+// of the endpoint, and collects the latency of the lookup.
+func getDNSLatency(ctx context.Context, target *checkTarget) (*trace.LatencyInfo, error) {
+	latencyInfo := &trace.LatencyInfo{}
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			dialer := &net.Dialer{
+				Timeout: checkTimeout,
+			}
+			return dialer.DialContext(ctx, network, target.address)
+		},
+	}
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	latencyInfo.DNSStart = time.Now()
+	_, err := resolver.LookupHost(ctx, target.query)
+	latencyInfo.DNS = time.Since(latencyInfo.DNSStart)
+	return latencyInfo, err
+}
+
+// getICMPLatency sends an ICMP echo request to an icmp endpoint and collects
+// the round-trip time as the connect latency.
+func getICMPLatency(ctx context.Context, target *checkTarget) (*trace.LatencyInfo, error) {
+	ctx, latencyInfo := trace.WithLatencyInfoCapture(ctx)
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, target.host)
+	if err != nil {
+		return latencyInfo, &net.OpError{Op: "dial", Net: "icmp", Err: err}
+	}
+	latencyInfo.ConnectStart = time.Now()
+	err = icmpEcho(ctx, addrs[0].IP)
+	latencyInfo.Connect = time.Since(latencyInfo.ConnectStart)
+	return latencyInfo, err
+}
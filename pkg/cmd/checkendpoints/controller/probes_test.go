@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/api/operatorcontrolplane/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/cluster-network-operator/pkg/cmd/checkendpoints/trace"
+)
+
+func TestParseTarget(t *testing.T) {
+	testCases := []struct {
+		endpoint string
+		expected *checkTarget
+		err      string
+	}{
+		{
+			endpoint: "host:6443",
+			expected: &checkTarget{scheme: schemeTCP, host: "host", address: "host:6443"},
+		},
+		{
+			endpoint: "[fd00::1]:6443",
+			expected: &checkTarget{scheme: schemeTCP, host: "fd00::1", address: "[fd00::1]:6443"},
+		},
+		{
+			endpoint: "https://host:6443",
+			expected: &checkTarget{scheme: schemeHTTPS, host: "host", address: "host:6443", url: "https://host:6443"},
+		},
+		{
+			endpoint: "HTTP://host:80",
+			expected: &checkTarget{scheme: schemeHTTP, host: "host", address: "host:80", url: "http://host:80"},
+		},
+		{
+			endpoint: "udp://host:4789",
+			expected: &checkTarget{scheme: schemeUDP, host: "host", address: "host:4789"},
+		},
+		{
+			endpoint: "dns://172.30.0.10:53",
+			expected: &checkTarget{scheme: schemeDNS, host: "172.30.0.10", address: "172.30.0.10:53", query: defaultDNSQuery},
+		},
+		{
+			endpoint: "dns://example.com@172.30.0.10:",
+			expected: &checkTarget{scheme: schemeDNS, host: "172.30.0.10", address: "172.30.0.10:53", query: "example.com"},
+		},
+		{
+			endpoint: "icmp://10.0.0.1:",
+			expected: &checkTarget{scheme: schemeICMP, host: "10.0.0.1", address: "10.0.0.1"},
+		},
+		{
+			endpoint: "udp://host:",
+			err:      "no port",
+		},
+		{
+			endpoint: "sctp://host:1234",
+			err:      "unsupported scheme",
+		},
+		{
+			endpoint: "host",
+			err:      "missing port",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.endpoint, func(t *testing.T) {
+			target, err := parseTarget(tc.endpoint)
+			if tc.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.err)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, target)
+		})
+	}
+}
+
+func TestHTTPProbe(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	c := &connectionChecker{
+		clientCertGetter: func() []tls.Certificate { return nil },
+		metrics:          NewMetricsContext("test", "test"),
+	}
+	endpoint := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+
+	latencyInfo, err := c.probe(context.Background(), endpoint)
+	assert.NoError(t, err)
+	assert.NotZero(t, latencyInfo.Connect)
+	assert.NotZero(t, latencyInfo.TLS)
+	assert.NotZero(t, latencyInfo.TTFB)
+
+	status = http.StatusServiceUnavailable
+	_, err = c.probe(context.Background(), endpoint)
+	assert.EqualError(t, err, "server responded with 503 Service Unavailable")
+}
+
+func TestUDPProbe(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := conn.LocalAddr().String()
+
+	c := &connectionChecker{metrics: NewMetricsContext("test", "test")}
+	_, err = c.probe(context.Background(), "udp://"+address)
+	assert.NoError(t, err)
+
+	// Once nothing listens, the port is reported as unreachable
+	assert.NoError(t, conn.Close())
+	_, err = c.probe(context.Background(), "udp://"+address)
+	assert.Error(t, err)
+}
+
+func TestDNSProbeFailure(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := conn.LocalAddr().String()
+	assert.NoError(t, conn.Close())
+
+	c := &connectionChecker{metrics: NewMetricsContext("test", "test")}
+	latencyInfo, err := c.probe(context.Background(), "dns://"+address)
+	assert.True(t, isDNSError(err), "expected a DNS error, got %v", err)
+	assert.NotZero(t, latencyInfo.DNSStart)
+}
+
+func TestManageStatusLogsProtocols(t *testing.T) {
+	start := testTime(0)
+	testCases := []struct {
+		name     string
+		endpoint string
+		err      error
+		trace    *trace.LatencyInfo
+		expected v1alpha1.LogEntry
+	}{
+		{
+			name:     "HTTPRequest",
+			endpoint: "https://host:443",
+			trace:    &trace.LatencyInfo{ConnectStart: start, Connect: time.Millisecond, TLS: 2 * time.Millisecond, TTFB: 3 * time.Millisecond},
+			expected: v1alpha1.LogEntry{
+				Start:   metav1.NewTime(start),
+				Success: true,
+				Reason:  LogEntryReasonHTTPRequest,
+				Message: "target-endpoint: https request to https://host:443 succeeded (connect 1ms, tls 2ms, time to first byte 3ms)",
+				Latency: metav1.Duration{Duration: 6 * time.Millisecond},
+			},
+		},
+		{
+			name:     "HTTPRequestError",
+			endpoint: "http://host:80",
+			err:      &httpStatusError{status: "503 Service Unavailable"},
+			trace:    &trace.LatencyInfo{ConnectStart: start, Connect: time.Millisecond, TTFB: 3 * time.Millisecond},
+			expected: v1alpha1.LogEntry{
+				Start:   metav1.NewTime(start),
+				Reason:  LogEntryReasonHTTPRequestError,
+				Message: "target-endpoint: failed to send an HTTP request to http://host:80: server responded with 503 Service Unavailable",
+				Latency: metav1.Duration{Duration: 4 * time.Millisecond},
+			},
+		},
+		{
+			name:     "DNSResolve",
+			endpoint: "dns://example.com@10.0.0.10:53",
+			trace:    &trace.LatencyInfo{DNSStart: start, DNS: time.Millisecond},
+			expected: v1alpha1.LogEntry{
+				Start:   metav1.NewTime(start),
+				Success: true,
+				Reason:  v1alpha1.LogEntryReasonDNSResolve,
+				Message: "target-endpoint: resolved example.com using 10.0.0.10:53 successfully",
+				Latency: metav1.Duration{Duration: time.Millisecond},
+			},
+		},
+		{
+			name:     "ICMPEchoError",
+			endpoint: "icmp://10.0.0.1:",
+			err:      &net.OpError{Op: "read", Net: "udp", Err: errTimeout{}},
+			trace:    &trace.LatencyInfo{ConnectStart: start, Connect: time.Millisecond},
+			expected: v1alpha1.LogEntry{
+				Start:   metav1.NewTime(start),
+				Reason:  LogEntryReasonICMPEchoError,
+				Message: "target-endpoint: failed to get an ICMP echo reply from icmp://10.0.0.1:: read udp: i/o timeout",
+				Latency: metav1.Duration{Duration: time.Millisecond},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := podNetworkConnectivityCheckStatus()
+			updateStatusFuncs, timestamp := manageStatusLogs(&v1alpha1.PodNetworkConnectivityCheck{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-to-target-endpoint"},
+				Spec:       v1alpha1.PodNetworkConnectivityCheckSpec{TargetEndpoint: tc.endpoint},
+			}, tc.err, tc.trace)
+			for _, updateStatusFunc := range updateStatusFuncs {
+				updateStatusFunc(status)
+			}
+			assert.Equal(t, start, timestamp)
+			if tc.expected.Success {
+				assert.Equal(t, []v1alpha1.LogEntry{tc.expected}, status.Successes)
+			} else {
+				assert.Equal(t, []v1alpha1.LogEntry{tc.expected}, status.Failures)
+			}
+		})
+	}
+}
+
+type errTimeout struct{}
+
+func (errTimeout) Error() string   { return "i/o timeout" }
+func (errTimeout) Timeout() bool   { return true }
+func (errTimeout) Temporary() bool { return true }
@@ -2,6 +2,7 @@ package trace
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http/httptrace"
 	"time"
 
@@ -13,6 +14,13 @@ type LatencyInfo struct {
 	Connect      time.Duration
 	DNSStart     time.Time
 	ConnectStart time.Time
+	// TLS is the duration of the TLS handshake of HTTPS requests
+	TLS      time.Duration
+	TLSStart time.Time
+	// TTFB is the time between writing an HTTP request and reading the
+	// first byte of the response
+	TTFB      time.Duration
+	TTFBStart time.Time
 }
 
 func (r *LatencyInfo) dnsStart() {
@@ -33,6 +41,22 @@ func (r *LatencyInfo) connectDone(addr string) {
 	r.Connect = time.Since(r.ConnectStart)
 }
 
+func (r *LatencyInfo) tlsHandshakeStart() {
+	r.TLSStart = time.Now()
+}
+
+func (r *LatencyInfo) tlsHandshakeDone() {
+	r.TLS = time.Since(r.TLSStart)
+}
+
+func (r *LatencyInfo) wroteRequest() {
+	r.TTFBStart = time.Now()
+}
+
+func (r *LatencyInfo) gotFirstResponseByte() {
+	r.TTFB = time.Since(r.TTFBStart)
+}
+
 func WithLatencyInfoCapture(ctx context.Context) (context.Context, *LatencyInfo) {
 	trace := &LatencyInfo{}
 	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
@@ -52,5 +76,21 @@ func WithLatencyInfoCapture(ctx context.Context) (context.Context, *LatencyInfo)
 			trace.connectDone(addr)
 			klog.V(5).Infof("ConnectDone: %s,%s,%v\n", network, addr, err)
 		},
+		TLSHandshakeStart: func() {
+			trace.tlsHandshakeStart()
+			klog.V(5).Infof("TLSHandshakeStart\n")
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			trace.tlsHandshakeDone()
+			klog.V(5).Infof("TLSHandshakeDone: %v\n", err)
+		},
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			trace.wroteRequest()
+			klog.V(5).Infof("WroteRequest: %v\n", info.Err)
+		},
+		GotFirstResponseByte: func() {
+			trace.gotFirstResponseByte()
+			klog.V(5).Infof("GotFirstResponseByte\n")
+		},
 	}), trace
 }
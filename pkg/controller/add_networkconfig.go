@@ -6,6 +6,7 @@ import (
 	configmapcainjector "github.com/openshift/cluster-network-operator/pkg/controller/configmap_ca_injector"
 	"github.com/openshift/cluster-network-operator/pkg/controller/dashboards"
 	"github.com/openshift/cluster-network-operator/pkg/controller/egress_router"
+	"github.com/openshift/cluster-network-operator/pkg/controller/extraclusters"
 	"github.com/openshift/cluster-network-operator/pkg/controller/infrastructureconfig"
 	"github.com/openshift/cluster-network-operator/pkg/controller/ingressconfig"
 	"github.com/openshift/cluster-network-operator/pkg/controller/operconfig"
@@ -28,5 +29,6 @@ func init() {
 		infrastructureconfig.Add,
 		allowlist.Add,
 		dashboards.Add,
+		extraclusters.Add,
 	)
 }
@@ -68,6 +68,9 @@ type ReconcileClusterConfig struct {
 func (r *ReconcileClusterConfig) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
 	defer utilruntime.HandleCrash(r.status.SetDegradedOnPanicAndCrash)
 	log.Printf("Reconciling Network.config.openshift.io %s\n", request.Name)
+	if r.status.MetricsDisabled() {
+		ctx = apply.WithoutMetrics(ctx)
+	}
 
 	// We won't create more than one network
 	if request.Name != names.CLUSTER_CONFIG {
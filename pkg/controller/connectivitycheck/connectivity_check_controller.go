@@ -48,6 +48,7 @@ type NetworkConnectivityCheckController interface {
 // Checks between network-check-source pod and every openshift apiserver service and endpoints
 // Checks between network-check-source pod and every LB
 // Checks between network-check-source pod and network-check-target service and endpoints this being managed by a Daemonset
+// Checks between network-check-source pod and every user-defined target in the network-check-targets ConfigMap
 func NewNetworkConnectivityCheckController(
 	operatorClient v1helpers.OperatorClient,
 	configClient *configv1client.Clientset,
@@ -72,6 +73,7 @@ func NewNetworkConnectivityCheckController(
 				kubeInformersForNamespaces.InformersFor("openshift-network-diagnostics").Core().V1().Pods().Informer(),
 				kubeInformersForNamespaces.InformersFor("openshift-network-diagnostics").Core().V1().Endpoints().Informer(),
 				kubeInformersForNamespaces.InformersFor("openshift-network-diagnostics").Core().V1().Services().Informer(),
+				kubeInformersForNamespaces.InformersFor("openshift-network-diagnostics").Core().V1().ConfigMaps().Informer(),
 				kubeInformersForNamespaces.InformersFor("openshift-kube-apiserver").Core().V1().Endpoints().Informer(),
 				kubeInformersForNamespaces.InformersFor("openshift-kube-apiserver").Core().V1().Services().Informer(),
 				kubeInformersForNamespaces.InformersFor("openshift-apiserver").Core().V1().Endpoints().Informer(),
@@ -90,6 +92,7 @@ func NewNetworkConnectivityCheckController(
 		diagnosticsPodLister:              kubeInformersForNamespaces.InformersFor("openshift-network-diagnostics").Core().V1().Pods().Lister(),
 		diagnosticsEndpointsLister:        kubeInformersForNamespaces.InformersFor("openshift-network-diagnostics").Core().V1().Endpoints().Lister(),
 		diagnosticsServiceLister:          kubeInformersForNamespaces.InformersFor("openshift-network-diagnostics").Core().V1().Services().Lister(),
+		diagnosticsConfigMapLister:        kubeInformersForNamespaces.InformersFor("openshift-network-diagnostics").Core().V1().ConfigMaps().Lister(),
 		kubeAPIServerEndpointsLister:      kubeInformersForNamespaces.InformersFor("openshift-kube-apiserver").Core().V1().Endpoints().Lister(),
 		kubeAPIServerServiceLister:        kubeInformersForNamespaces.InformersFor("openshift-kube-apiserver").Core().V1().Services().Lister(),
 		defaultServiceLister:              kubeInformersForNamespaces.InformersFor("default").Core().V1().Services().Lister(),
@@ -113,6 +116,7 @@ type connectivityCheckTemplateProvider struct {
 	diagnosticsPodLister              corev1listers.PodLister
 	diagnosticsEndpointsLister        corev1listers.EndpointsLister
 	diagnosticsServiceLister          corev1listers.ServiceLister
+	diagnosticsConfigMapLister        corev1listers.ConfigMapLister
 	kubeAPIServerEndpointsLister      corev1listers.EndpointsLister
 	kubeAPIServerServiceLister        corev1listers.ServiceLister
 	defaultServiceLister              corev1listers.ServiceLister
@@ -144,6 +148,8 @@ func (c *connectivityCheckTemplateProvider) generate(ctx context.Context, syncCo
 	templates = append(templates, c.getTemplatesForGenericPodServiceCheck(syncContext.Recorder())...)
 	// each generic pod endpoint IP
 	templates = append(templates, c.getTemplatesForGenericPodServiceEndpointsChecks(syncContext.Recorder())...)
+	// each user-defined target
+	templates = append(templates, c.getTemplatesForCustomTargets(syncContext.Recorder())...)
 
 	pods, err := c.diagnosticsPodLister.List(labels.Set{"app": "network-check-source"}.AsSelector())
 	if err != nil {
@@ -0,0 +1,73 @@
+package connectivitycheck
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	applyconfigv1alpha1 "github.com/openshift/client-go/operatorcontrolplane/applyconfigurations/operatorcontrolplane/v1alpha1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// customTargetsConfigMap is the name of the ConfigMap, in the
+// openshift-network-diagnostics namespace, where users declare additional
+// connectivity check targets. Each key names a target and its value is the
+// target endpoint, for example:
+//
+//	registry: registry.example.com:443
+//	database: postgres.db.svc:5432
+//	proxy: http://proxy.example.com:3128
+//
+// Every network-check-source pod checks every target.
+const customTargetsConfigMap = "network-check-targets"
+
+// targetEndpointPattern is the pattern of PodNetworkConnectivityCheck target endpoints
+var targetEndpointPattern = regexp.MustCompile(`^\S+:\d*$`)
+
+// customTarget is a user-defined connectivity check target
+type customTarget struct {
+	name     string
+	endpoint string
+}
+
+// parseCustomTargets parses the data of the network-check-targets ConfigMap,
+// sorted by target name. Invalid targets are returned as errors, and skipped.
+func parseCustomTargets(data map[string]string) ([]customTarget, []error) {
+	var targets []customTarget
+	var errs []error
+	for name, endpoint := range data {
+		if msgs := validation.IsDNS1123Label(name); len(msgs) > 0 {
+			errs = append(errs, fmt.Errorf("invalid target name %q: %v", name, msgs))
+			continue
+		}
+		if !targetEndpointPattern.MatchString(endpoint) {
+			errs = append(errs, fmt.Errorf("invalid endpoint %q for target %q: must be of the form [scheme://]host:port", endpoint, name))
+			continue
+		}
+		targets = append(targets, customTarget{name: name, endpoint: endpoint})
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].name < targets[j].name })
+	return targets, errs
+}
+
+func (c *connectivityCheckTemplateProvider) getTemplatesForCustomTargets(recorder events.Recorder) []*applyconfigv1alpha1.PodNetworkConnectivityCheckApplyConfiguration {
+	var templates []*applyconfigv1alpha1.PodNetworkConnectivityCheckApplyConfiguration
+	cm, err := c.diagnosticsConfigMapLister.ConfigMaps("openshift-network-diagnostics").Get(customTargetsConfigMap)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			recorder.Warningf("EndpointDetectionFailure", "unable to read custom connectivity check targets: %v", err)
+		}
+		return nil
+	}
+
+	targets, errs := parseCustomTargets(cm.Data)
+	for _, err := range errs {
+		recorder.Warningf("InvalidCustomTarget", "ignoring custom connectivity check target in %s: %v", customTargetsConfigMap, err)
+	}
+	for _, target := range targets {
+		templates = append(templates, NewPodNetworkConnectivityCheckTemplate(target.endpoint, "openshift-network-diagnostics", withTarget("custom-target", target.name)))
+	}
+	return templates
+}
@@ -0,0 +1,26 @@
+package connectivitycheck
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseCustomTargets(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	targets, errs := parseCustomTargets(map[string]string{
+		"registry":   "registry.example.com:443",
+		"database":   "postgres.db.svc:5432",
+		"proxy":      "http://proxy.example.com:3128",
+		"no-port":    "registry.example.com",
+		"Bad_Name":   "registry.example.com:443",
+		"whitespace": "registry example.com:443",
+	})
+	g.Expect(targets).To(Equal([]customTarget{
+		{name: "database", endpoint: "postgres.db.svc:5432"},
+		{name: "proxy", endpoint: "http://proxy.example.com:3128"},
+		{name: "registry", endpoint: "registry.example.com:443"},
+	}))
+	g.Expect(errs).To(HaveLen(3))
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
+	"github.com/openshift/cluster-network-operator/pkg/controller/eventrecorder"
 	"github.com/openshift/cluster-network-operator/pkg/controller/statusmanager"
 	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -12,6 +13,7 @@ var AddToManagerFuncs []func(manager.Manager, *statusmanager.StatusManager, cnoc
 
 // AddToManager adds all Controllers to the Manager
 func AddToManager(m manager.Manager, sm *statusmanager.StatusManager, c cnoclient.Client, featureGates featuregates.FeatureGate) error {
+	sm.SetEventRecorder(m.GetEventRecorderFor(eventrecorder.Component))
 	for _, f := range AddToManagerFuncs {
 		if err := f(m, sm, c, featureGates); err != nil {
 			return err
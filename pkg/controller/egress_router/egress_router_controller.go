@@ -16,20 +16,28 @@ import (
 	"github.com/pkg/errors"
 
 	"path/filepath"
-	"reflect"
+	"sort"
 	"strings"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	appsinformers "k8s.io/client-go/informers/apps/v1"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
 	netopv1 "github.com/openshift/api/networkoperator/v1"
+	"github.com/openshift/cluster-network-operator/pkg/controller/eventrecorder"
 	"github.com/openshift/cluster-network-operator/pkg/controller/statusmanager"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -38,6 +46,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+const (
+	// the names of the objects rendered for a router, in its namespace
+	nadName        = "egress-router-cni-nad"
+	deploymentName = "egress-router-cni-deployment"
+	// deploymentLabelSelector selects the Deployments of the routers
+	deploymentLabelSelector = "app=egress-router-cni"
+)
+
 // Attach control loop to the manager and watch for Egress Router objects
 func Add(mgr manager.Manager, status *statusmanager.StatusManager, cli cnoclient.Client, _ featuregates.FeatureGate) error {
 	r, err := newEgressRouterReconciler(mgr, status, cli)
@@ -51,46 +67,75 @@ func Add(mgr manager.Manager, status *statusmanager.StatusManager, cli cnoclient
 		return err
 	}
 
-	// Watch for changes to primary resource EgressRouter.network.operator.openshift.io/v1
-	err = c.Watch(source.Kind[crclient.Object](mgr.GetCache(), &netopv1.EgressRouter{}, &handler.EnqueueRequestForObject{}))
+	// Watch for changes to primary resource EgressRouter.network.operator.openshift.io/v1.
+	// Status and observed generation updates do not change the generation.
+	err = c.Watch(source.Kind[crclient.Object](mgr.GetCache(), &netopv1.EgressRouter{}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{}))
 	if err != nil {
 		return err
 	}
 
+	// Watch the Deployments of the routers, to follow their rollout
+	return c.Watch(&source.Informer{
+		Informer: r.deploymentInformer,
+		Handler:  handler.EnqueueRequestsFromMapFunc(reconcileOwnerRouter),
+		Predicates: []predicate.TypedPredicate[crclient.Object]{
+			predicate.ResourceVersionChangedPredicate{},
+		},
+	})
+}
+
+// reconcileOwnerRouter maps an object to the router that owns it
+func reconcileOwnerRouter(ctx context.Context, obj crclient.Object) []reconcile.Request {
+	for _, owner := range obj.GetOwnerReferences() {
+		if owner.Kind == "EgressRouter" && owner.Controller != nil && *owner.Controller {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{
+				Namespace: obj.GetNamespace(),
+				Name:      owner.Name,
+			}}}
+		}
+	}
 	return nil
 }
 
 var _ reconcile.Reconciler = &EgressRouterReconciler{}
 var manifestDir = "bindata/"
 
-type egressrouter struct {
-	spec netopv1.EgressRouterSpec
-}
-
+// EgressRouterReconciler deploys EgressRouters and reports their status. It
+// keeps no state of its own: every reconcile applies the objects of the router
+// and derives its conditions from them.
 type EgressRouterReconciler struct {
-	mgr    manager.Manager
-	client cnoclient.Client
-	status *statusmanager.StatusManager
+	mgr      manager.Manager
+	client   cnoclient.Client
+	status   *statusmanager.StatusManager
+	recorder record.EventRecorder
 
-	egressrouters    map[types.NamespacedName]*egressrouter
-	egressrouterErrs map[types.NamespacedName]error
+	deploymentInformer cache.SharedIndexInformer
 }
 
 var ResyncPeriod = 5 * time.Minute
 
-func newEgressRouterReconciler(mgr manager.Manager, status *statusmanager.StatusManager, c cnoclient.Client) (reconcile.Reconciler, error) {
+func newEgressRouterReconciler(mgr manager.Manager, status *statusmanager.StatusManager, c cnoclient.Client) (*EgressRouterReconciler, error) {
+	deploymentInformer := appsinformers.NewFilteredDeploymentInformer(
+		c.Default().Kubernetes(),
+		metav1.NamespaceAll,
+		0, // don't resync
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+		func(options *metav1.ListOptions) {
+			options.LabelSelector = deploymentLabelSelector
+		})
+	c.Default().AddCustomInformer(deploymentInformer) // Tell the ClusterClient about this informer
 
 	return &EgressRouterReconciler{
-		mgr:    mgr,
-		status: status,
-		client: c,
+		mgr:      mgr,
+		status:   status,
+		client:   c,
+		recorder: mgr.GetEventRecorderFor(eventrecorder.Component),
 
-		egressrouters:    map[types.NamespacedName]*egressrouter{},
-		egressrouterErrs: map[types.NamespacedName]error{},
+		deploymentInformer: deploymentInformer,
 	}, nil
 }
 
-func (r EgressRouterReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+func (r *EgressRouterReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
 	defer utilruntime.HandleCrash(r.status.SetDegradedOnPanicAndCrash)
 	klog.Infof("Reconciling egressrouter.network.operator.openshift.io %s\n", request.NamespacedName)
 
@@ -99,73 +144,132 @@ func (r EgressRouterReconciler) Reconcile(ctx context.Context, request reconcile
 
 	if err != nil {
 		if apierrors.IsNotFound(err) {
+			// The garbage collector deletes the objects of the router, which it owns
 			klog.Infof("Egress Router %s seems to have been deleted\n", request.NamespacedName)
+			r.setStatus(ctx, request.NamespacedName, nil)
 			return reconcile.Result{}, nil
 		}
 		klog.Error(err)
 		return reconcile.Result{}, err
 	}
 
-	// Check to see if we already know this object
-	existing := r.egressrouters[request.NamespacedName]
-	if existing != nil {
-		if !reflect.DeepEqual(obj.Spec, existing.spec) {
-			klog.Infof("Egress Router %s has changed, refreshing\n", request.NamespacedName)
-			delete(r.egressrouters, request.NamespacedName)
-			existing = nil
+	if errs := validateEgressRouter(&obj.Spec); len(errs) > 0 {
+		err := errs.ToAggregate()
+		reason := invalidRouterReason(errs)
+		klog.Errorf("Invalid Egress Router %s: %v", request.NamespacedName, err)
+		if r.setRouterConditions(ctx, obj,
+			routerCondition(netopv1.EgressRouterAvailable, netopv1.ConditionFalse, reason, "The egress router is not deployed"),
+			routerCondition(netopv1.EgressRouterProgressing, netopv1.ConditionFalse, reason, ""),
+			routerCondition(netopv1.EgressRouterDegraded, netopv1.ConditionTrue, reason, err.Error()),
+		) {
+			r.recorder.Eventf(obj, corev1.EventTypeWarning, "InvalidEgressRouter", "Not deploying the egress router: %v", err)
 		}
+		r.setStatus(ctx, request.NamespacedName, obj)
+		// Nothing to retry until the spec changes
+		return reconcile.Result{}, nil
 	}
 
-	if existing == nil {
-		klog.Infof("Creating a new Egress Router")
-		// Set owner reference to the controller
-		boolTrue := bool(true)
-		EgressRouterOwnerReferences := []metav1.OwnerReference{
-			{
-				APIVersion: "network.operator.openshift.io/v1",
-				Kind:       "EgressRouter",
-				Name:       obj.Name,
-				UID:        obj.UID,
-				Controller: &boolTrue,
-			},
-		}
-		err := r.ensureEgressRouter(ctx, manifestDir, request.Namespace, obj, EgressRouterOwnerReferences)
-
-		if err != nil {
-			klog.Error(err)
-			r.egressrouterErrs[request.NamespacedName] =
-				errors.Wrapf(err, "could not reconcile Egress Router %s", request.NamespacedName)
-			r.setStatus()
-			return reconcile.Result{}, err
-		}
-
-		r.egressrouters[request.NamespacedName] = existing
+	// Set owner reference to the controller
+	boolTrue := bool(true)
+	EgressRouterOwnerReferences := []metav1.OwnerReference{
+		{
+			APIVersion: "network.operator.openshift.io/v1",
+			Kind:       "EgressRouter",
+			Name:       obj.Name,
+			UID:        obj.UID,
+			Controller: &boolTrue,
+		},
+	}
+	changed, err := r.ensureEgressRouter(ctx, manifestDir, request.Namespace, obj, EgressRouterOwnerReferences)
+	if err != nil {
+		klog.Error(err)
+		r.recorder.Eventf(obj, corev1.EventTypeWarning, "EgressRouterFailed", "Failed to deploy the egress router: %v", err)
+		r.setRouterConditions(ctx, obj,
+			routerCondition(netopv1.EgressRouterDegraded, netopv1.ConditionTrue, "ApplyFailed", err.Error()))
+		r.setStatus(ctx, request.NamespacedName, obj)
+		return reconcile.Result{}, err
+	}
+	if changed {
+		r.recorder.Event(obj, corev1.EventTypeNormal, "EgressRouterDeployed", "Deployed the egress router")
 	}
 
+	nadFound, err := r.nadExists(ctx, request.Namespace)
+	if err != nil {
+		klog.Error(err)
+		return reconcile.Result{}, err
+	}
+	deployment, err := r.getDeployment(request.Namespace)
 	if err != nil {
 		klog.Error(err)
-		r.egressrouterErrs[request.NamespacedName] =
-			errors.Wrapf(err, "could not reconcile Egress Router %s", request.NamespacedName)
-		r.setStatus()
 		return reconcile.Result{}, err
 	}
+	r.setRouterConditions(ctx, obj, routerConditions(nadFound, deployment)...)
 
 	klog.Infof("successful reconciliation")
-	delete(r.egressrouterErrs, request.NamespacedName)
-	r.setStatus()
+	r.setStatus(ctx, request.NamespacedName, obj)
 	return reconcile.Result{RequeueAfter: ResyncPeriod}, nil
 }
 
+// nadExists returns true if the NetworkAttachmentDefinition of the router in
+// namespace exists
+func (r *EgressRouterReconciler) nadExists(ctx context.Context, namespace string) (bool, error) {
+	nad := &uns.Unstructured{}
+	nad.SetAPIVersion("k8s.cni.cncf.io/v1")
+	nad.SetKind("NetworkAttachmentDefinition")
+	err := r.client.Default().CRClient().Get(ctx, types.NamespacedName{Namespace: namespace, Name: nadName}, nad)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "could not get NetworkAttachmentDefinition %s/%s", namespace, nadName)
+	}
+	return true, nil
+}
+
+// getDeployment returns the Deployment of the router in namespace, or nil if
+// the informer does not know it yet
+func (r *EgressRouterReconciler) getDeployment(namespace string) (*appsv1.Deployment, error) {
+	obj, exists, err := r.deploymentInformer.GetStore().GetByKey(namespace + "/" + deploymentName)
+	if err != nil || !exists {
+		return nil, err
+	}
+	return obj.(*appsv1.Deployment), nil
+}
+
 // setStatus summarizes the status of all Egress Router objects and updates the statusmanager
-// as appropriate.
-func (r *EgressRouterReconciler) setStatus() {
-	if len(r.egressrouterErrs) == 0 {
+// as appropriate. Only the routers that the operator failed to deploy degrade
+// it; the other problems are reported in the conditions of the routers. current
+// is the router named name as just reconciled, or nil if it was deleted.
+func (r *EgressRouterReconciler) setStatus(ctx context.Context, name types.NamespacedName, current *netopv1.EgressRouter) {
+	routers := &netopv1.EgressRouterList{}
+	if err := r.mgr.GetClient().List(ctx, routers); err != nil {
+		klog.Errorf("Failed to list the Egress Routers: %v", err)
+		return
+	}
+
+	msgs := []string{}
+	addMessage := func(router *netopv1.EgressRouter) {
+		for _, c := range router.Status.Conditions {
+			if c.Type == netopv1.EgressRouterDegraded && c.Status == netopv1.ConditionTrue && c.Reason == "ApplyFailed" {
+				msgs = append(msgs, fmt.Sprintf("EgressRouter %s/%s: %s", router.Namespace, router.Name, c.Message))
+			}
+		}
+	}
+	for i := range routers.Items {
+		// the cache may not have seen the last status update yet
+		if routers.Items[i].Namespace == name.Namespace && routers.Items[i].Name == name.Name {
+			continue
+		}
+		addMessage(&routers.Items[i])
+	}
+	if current != nil {
+		addMessage(current)
+	}
+
+	if len(msgs) == 0 {
 		r.status.SetNotDegraded(statusmanager.EgressRouterConfig)
 	} else {
-		msgs := []string{}
-		for _, e := range r.egressrouterErrs {
-			msgs = append(msgs, e.Error())
-		}
+		sort.Strings(msgs)
 		r.status.SetDegraded(statusmanager.EgressRouterConfig, "EgressRouterError", strings.Join(msgs, ", "))
 	}
 }
@@ -195,57 +299,121 @@ func getAllowedDestinationsConfigJSON(RedirectRules []netopv1.L4RedirectRule) (s
 	return string(jsonByte), nil
 }
 
-func (r *EgressRouterReconciler) ensureEgressRouter(ctx context.Context, manifestDir string, namespace string, router *netopv1.EgressRouter, EgressRouterOwnerReferences []metav1.OwnerReference) error {
-	var err error
-	if len(router.Spec.Addresses) == 0 {
-		return fmt.Errorf("Error: router without addresses")
+// validateEgressRouter validates what the CRD schema does not, and what the
+// egress router CNI plugin does not support
+func validateEgressRouter(spec *netopv1.EgressRouterSpec) field.ErrorList {
+	specPath := field.NewPath("spec")
+	errs := field.ErrorList{}
+
+	if spec.Mode != netopv1.EgressRouterModeRedirect {
+		errs = append(errs, field.NotSupported(specPath.Child("mode"), spec.Mode, []string{string(netopv1.EgressRouterModeRedirect)}))
+	} else if spec.Redirect == nil {
+		errs = append(errs, field.Required(specPath.Child("redirect"), "must be set in Redirect mode"))
+	}
+
+	if len(spec.Addresses) == 0 {
+		errs = append(errs, field.Required(specPath.Child("addresses"), "must have at least 1 entry"))
+	}
+	gateway := ""
+	for i, address := range spec.Addresses {
+		addressPath := specPath.Child("addresses").Index(i)
+		_, subnet, err := net.ParseCIDR(address.IP)
+		if err != nil {
+			errs = append(errs, field.Invalid(addressPath.Child("ip"), address.IP, "must be an IP address with a prefix length"))
+		}
+		if address.Gateway == "" {
+			continue
+		}
+		ip := net.ParseIP(address.Gateway)
+		switch {
+		case ip == nil:
+			errs = append(errs, field.Invalid(addressPath.Child("gateway"), address.Gateway, "must be an IP address"))
+		case subnet != nil && !subnet.Contains(ip):
+			errs = append(errs, field.Invalid(addressPath.Child("gateway"), address.Gateway, fmt.Sprintf("must be in the subnet of %s", address.IP)))
+		case gateway != "" && gateway != address.Gateway:
+			// The egress router CNI plugin configures a single gateway
+			errs = append(errs, field.Forbidden(addressPath.Child("gateway"),
+				fmt.Sprintf("must be the gateway of the other addresses (%s), only one gateway is supported", gateway)))
+		default:
+			gateway = address.Gateway
+		}
+	}
+	return errs
+}
+
+// invalidRouterReason returns the reason of the conditions of a router that is
+// not deployed because of errs. What the egress router CNI plugin does not
+// support has its own reason, so that it is not mistaken for a typo.
+func invalidRouterReason(errs field.ErrorList) string {
+	for _, err := range errs {
+		switch {
+		case err.Type == field.ErrorTypeNotSupported && err.Field == "spec.mode":
+			return "UnsupportedMode"
+		case err.Type == field.ErrorTypeForbidden && strings.HasSuffix(err.Field, ".gateway"):
+			return "MultipleGatewaysUnsupported"
+		}
+	}
+	return "InvalidSpec"
+}
+
+// routerGateway returns the gateway of the addresses of a valid router, or ""
+// if the CNI plugin has to determine it
+func routerGateway(spec *netopv1.EgressRouterSpec) string {
+	for _, address := range spec.Addresses {
+		if address.Gateway != "" {
+			return address.Gateway
+		}
+	}
+	return ""
+}
+
+// renderEgressRouter renders the objects of a valid router
+func renderEgressRouter(manifestDir string, namespace string, router *netopv1.EgressRouter) ([]*uns.Unstructured, error) {
+	addresses := []string{}
+	for _, address := range router.Spec.Addresses {
+		addresses = append(addresses, address.IP)
 	}
-	out := []*uns.Unstructured{}
+	addressesJSON, err := json.Marshal(addresses)
+	if err != nil {
+		return nil, err
+	}
+
 	data := render.MakeRenderData()
 	data.Data["ReleaseVersion"] = os.Getenv("RELEASE_VERSION")
 	data.Data["EgressRouterNamespace"] = namespace
-	if isItValidCidr(router.Spec.Addresses[0].IP) {
-		data.Data["Addresses"] = router.Spec.Addresses[0].IP
-	}
-	if isItValidIPAddress(router.Spec.Addresses[0].Gateway) {
-		data.Data["Gateway"] = router.Spec.Addresses[0].Gateway
-	}
+	data.Data["Addresses"] = string(addressesJSON)
+	data.Data["Gateway"] = routerGateway(&router.Spec)
 	data.Data["AllowedDestinations"], err = getAllowedDestinationsConfigJSON(router.Spec.Redirect.RedirectRules)
 	if err != nil {
-		return errors.Wrap(err, "failed to render AllowedDestinations config")
+		return nil, errors.Wrap(err, "failed to render AllowedDestinations config")
 	}
 	data.Data["FallbackIP"] = router.Spec.Redirect.FallbackIP
 	data.Data["mode"] = router.Spec.Mode
 	data.Data["network_interfaces"] = router.Spec.NetworkInterface
 	data.Data["EgressRouterPodImage"] = os.Getenv("EGRESS_ROUTER_CNI_IMAGE")
-	manifests, err := render.RenderDir(filepath.Join(manifestDir, "egress-router"), &data)
+	return render.RenderDir(filepath.Join(manifestDir, "egress-router"), &data)
+}
+
+// ensureEgressRouter renders and applies a valid router. It returns true if
+// the apply created or changed any of its objects.
+func (r *EgressRouterReconciler) ensureEgressRouter(ctx context.Context, manifestDir string, namespace string, router *netopv1.EgressRouter, EgressRouterOwnerReferences []metav1.OwnerReference) (bool, error) {
+	out, err := renderEgressRouter(manifestDir, namespace, router)
 	if err != nil {
-		return err
+		return false, err
 	}
-	out = append(out, manifests...)
 
+	changed := false
 	for _, obj := range out {
 		klog.Infof("Assigning owner references")
 		obj.SetOwnerReferences(EgressRouterOwnerReferences)
 		klog.Infof("Applying manifest")
-		if err := apply.ApplyObject(ctx, r.client, obj, "egress_router"); err != nil {
+		objChanged, err := apply.ApplyObjectChanged(ctx, r.client, obj, "egress_router")
+		if err != nil {
 			klog.Infof("could not apply egress router object: %v", err)
-			return err
+			return false, err
 		}
+		changed = changed || objChanged
 	}
 
-	return nil
-}
-
-func isItValidCidr(cidr string) bool {
-	_, _, err := net.ParseCIDR(cidr)
-	if err != nil {
-		klog.Error(err)
-		return false
-	}
-	return true
-}
-
-func isItValidIPAddress(ip string) bool {
-	return net.ParseIP(ip) != nil
+	return changed, nil
 }
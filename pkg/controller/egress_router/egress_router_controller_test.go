@@ -0,0 +1,113 @@
+package egress_router
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	netopv1 "github.com/openshift/api/networkoperator/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func redirectRouter(addresses ...netopv1.EgressRouterAddress) *netopv1.EgressRouter {
+	return &netopv1.EgressRouter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "egress", Name: "router"},
+		Spec: netopv1.EgressRouterSpec{
+			Mode: netopv1.EgressRouterModeRedirect,
+			Redirect: &netopv1.RedirectConfig{
+				RedirectRules: []netopv1.L4RedirectRule{{DestinationIP: "203.0.113.25", Port: 80, Protocol: netopv1.ProtocolTypeTCP}},
+				FallbackIP:    "203.0.113.26",
+			},
+			NetworkInterface: netopv1.EgressRouterInterface{Macvlan: netopv1.MacvlanConfig{Mode: netopv1.MacvlanModeBridge}},
+			Addresses:        addresses,
+		},
+	}
+}
+
+func TestValidateEgressRouter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := redirectRouter(
+		netopv1.EgressRouterAddress{IP: "192.168.12.99/24", Gateway: "192.168.12.1"},
+		netopv1.EgressRouterAddress{IP: "192.168.12.100/24"},
+		netopv1.EgressRouterAddress{IP: "192.168.12.101/24", Gateway: "192.168.12.1"},
+	)
+	g.Expect(validateEgressRouter(&router.Spec)).To(BeEmpty())
+	g.Expect(routerGateway(&router.Spec)).To(Equal("192.168.12.1"))
+
+	router = redirectRouter(
+		netopv1.EgressRouterAddress{IP: "192.168.12.99", Gateway: "192.168.12.1"},
+		netopv1.EgressRouterAddress{IP: "192.168.12.100/24", Gateway: "10.0.0.1"},
+		netopv1.EgressRouterAddress{IP: "192.168.12.101/24", Gateway: "192.168.12.254"},
+	)
+	router.Spec.Redirect = nil
+	messages := []string{}
+	errs := validateEgressRouter(&router.Spec)
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	g.Expect(messages).To(ConsistOf(
+		"spec.redirect: Required value: must be set in Redirect mode",
+		`spec.addresses[0].ip: Invalid value: "192.168.12.99": must be an IP address with a prefix length`,
+		`spec.addresses[1].gateway: Invalid value: "10.0.0.1": must be in the subnet of 192.168.12.100/24`,
+		"spec.addresses[2].gateway: Forbidden: must be the gateway of the other addresses (192.168.12.1), only one gateway is supported",
+	))
+	g.Expect(invalidRouterReason(errs)).To(Equal("MultipleGatewaysUnsupported"))
+	g.Expect(invalidRouterReason(errs[:3])).To(Equal("InvalidSpec"))
+
+	router = redirectRouter()
+	router.Spec.Mode = "HTTP"
+	messages = []string{}
+	errs = validateEgressRouter(&router.Spec)
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	g.Expect(messages).To(ConsistOf(
+		`spec.mode: Unsupported value: "HTTP": supported values: "Redirect"`,
+		"spec.addresses: Required value: must have at least 1 entry",
+	))
+	g.Expect(invalidRouterReason(errs)).To(Equal("UnsupportedMode"))
+}
+
+func TestRenderEgressRouter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	router := redirectRouter(
+		netopv1.EgressRouterAddress{IP: "192.168.12.99/24", Gateway: "192.168.12.1"},
+		netopv1.EgressRouterAddress{IP: "192.168.12.100/24"},
+	)
+	objs, err := renderEgressRouter("../../../bindata", "egress", router)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(objs).To(HaveLen(2))
+
+	config, found, err := uns.NestedString(objs[0].Object, "spec", "config")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeTrue())
+	cniConfig := struct {
+		IP struct {
+			Addresses    []string `json:"addresses"`
+			Destinations []string `json:"destinations"`
+			FallbackIP   string   `json:"fallbackIP"`
+			Gateway      string   `json:"gateway"`
+		} `json:"ip"`
+	}{}
+	g.Expect(json.Unmarshal([]byte(config), &cniConfig)).To(Succeed())
+	g.Expect(cniConfig.IP.Addresses).To(Equal([]string{"192.168.12.99/24", "192.168.12.100/24"}))
+	g.Expect(cniConfig.IP.Destinations).To(Equal([]string{"80 TCP 203.0.113.25"}))
+	g.Expect(cniConfig.IP.FallbackIP).To(Equal("203.0.113.26"))
+	g.Expect(cniConfig.IP.Gateway).To(Equal("192.168.12.1"))
+
+	networks, _, err := uns.NestedString(objs[1].Object, "spec", "template", "metadata", "annotations", "k8s.v1.cni.cncf.io/networks")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(networks).To(MatchJSON(`[{"name": "egress-router-cni-nad", "default-route": ["192.168.12.1"]}]`))
+
+	// no default route is requested without a gateway
+	router = redirectRouter(netopv1.EgressRouterAddress{IP: "192.168.12.99/24"})
+	objs, err = renderEgressRouter("../../../bindata", "egress", router)
+	g.Expect(err).NotTo(HaveOccurred())
+	networks, _, err = uns.NestedString(objs[1].Object, "spec", "template", "metadata", "annotations", "k8s.v1.cni.cncf.io/networks")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(networks).To(MatchJSON(`[{"name": "egress-router-cni-nad"}]`))
+}
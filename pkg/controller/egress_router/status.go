@@ -0,0 +1,128 @@
+package egress_router
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	netopv1 "github.com/openshift/api/networkoperator/v1"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// routerCondition returns an EgressRouter condition
+func routerCondition(conditionType netopv1.EgressRouterStatusConditionType, status netopv1.ConditionStatus, reason, message string) netopv1.EgressRouterStatusCondition {
+	return netopv1.EgressRouterStatusCondition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// routerConditions derives the conditions of a deployed router from whether
+// its NetworkAttachmentDefinition exists and from its Deployment, which is nil
+// if it is not known yet.
+func routerConditions(nadFound bool, deployment *appsv1.Deployment) []netopv1.EgressRouterStatusCondition {
+	if !nadFound {
+		return []netopv1.EgressRouterStatusCondition{
+			routerCondition(netopv1.EgressRouterAvailable, netopv1.ConditionFalse, "NetworkAttachmentDefinitionMissing",
+				fmt.Sprintf("The NetworkAttachmentDefinition %s does not exist", nadName)),
+			routerCondition(netopv1.EgressRouterProgressing, netopv1.ConditionTrue, "NetworkAttachmentDefinitionMissing", ""),
+			routerCondition(netopv1.EgressRouterDegraded, netopv1.ConditionFalse, "AsExpected", ""),
+		}
+	}
+	if deployment == nil {
+		return []netopv1.EgressRouterStatusCondition{
+			routerCondition(netopv1.EgressRouterAvailable, netopv1.ConditionFalse, "DeploymentMissing",
+				fmt.Sprintf("The Deployment %s does not exist", deploymentName)),
+			routerCondition(netopv1.EgressRouterProgressing, netopv1.ConditionTrue, "DeploymentMissing", ""),
+			routerCondition(netopv1.EgressRouterDegraded, netopv1.ConditionFalse, "AsExpected", ""),
+		}
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+
+	available := routerCondition(netopv1.EgressRouterAvailable, netopv1.ConditionTrue, "DeploymentAvailable", "")
+	if status.AvailableReplicas == 0 {
+		available = routerCondition(netopv1.EgressRouterAvailable, netopv1.ConditionFalse, "DeploymentUnavailable",
+			fmt.Sprintf("The Deployment %s has no available pod", deploymentName))
+	}
+
+	progressing := routerCondition(netopv1.EgressRouterProgressing, netopv1.ConditionFalse, "AsExpected", "")
+	if status.ObservedGeneration < deployment.Generation || status.UpdatedReplicas < replicas ||
+		status.Replicas > status.UpdatedReplicas || status.AvailableReplicas < replicas {
+		progressing = routerCondition(netopv1.EgressRouterProgressing, netopv1.ConditionTrue, "DeploymentRollingOut",
+			fmt.Sprintf("The Deployment %s has %d out of %d pods updated and %d available", deploymentName, status.UpdatedReplicas, replicas, status.AvailableReplicas))
+	}
+
+	degraded := routerCondition(netopv1.EgressRouterDegraded, netopv1.ConditionFalse, "AsExpected", "")
+	for _, c := range status.Conditions {
+		if (c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionFalse && c.Reason == "ProgressDeadlineExceeded") ||
+			(c.Type == appsv1.DeploymentReplicaFailure && c.Status == corev1.ConditionTrue) {
+			degraded = routerCondition(netopv1.EgressRouterDegraded, netopv1.ConditionTrue, "DeploymentFailed",
+				fmt.Sprintf("The Deployment %s failed: %s", deploymentName, c.Message))
+			break
+		}
+	}
+
+	return []netopv1.EgressRouterStatusCondition{available, progressing, degraded}
+}
+
+// setCondition sets condition in conditions, keeping its last transition time
+// if its status did not change. It returns true if conditions changed.
+func setCondition(conditions *[]netopv1.EgressRouterStatusCondition, condition netopv1.EgressRouterStatusCondition) bool {
+	for i := range *conditions {
+		existing := &(*conditions)[i]
+		if existing.Type != condition.Type {
+			continue
+		}
+		if existing.Status == condition.Status && existing.Reason == condition.Reason && existing.Message == condition.Message {
+			return false
+		}
+		if existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		} else {
+			condition.LastTransitionTime = metav1.Now()
+		}
+		*existing = condition
+		return true
+	}
+	condition.LastTransitionTime = metav1.Now()
+	*conditions = append(*conditions, condition)
+	return true
+}
+
+// setRouterConditions sets status conditions of router, and records that they
+// are computed for its current generation. It returns true if the conditions
+// changed. Failures are only logged, they are retried with the next reconcile.
+func (r *EgressRouterReconciler) setRouterConditions(ctx context.Context, router *netopv1.EgressRouter, conditions ...netopv1.EgressRouterStatusCondition) bool {
+	changed := false
+	for _, condition := range conditions {
+		changed = setCondition(&router.Status.Conditions, condition) || changed
+	}
+	if changed {
+		if err := r.mgr.GetClient().Status().Update(ctx, router); err != nil {
+			klog.Errorf("Failed to update the status of Egress Router %s/%s: %v", router.Namespace, router.Name, err)
+			return changed
+		}
+	}
+
+	generation := strconv.FormatInt(router.Generation, 10)
+	if router.Annotations[names.EgressRouterObservedGenerationAnnotation] != generation {
+		patch := crclient.MergeFrom(router.DeepCopy())
+		metav1.SetMetaDataAnnotation(&router.ObjectMeta, names.EgressRouterObservedGenerationAnnotation, generation)
+		if err := r.mgr.GetClient().Patch(ctx, router, patch); err != nil {
+			klog.Errorf("Failed to set the observed generation of Egress Router %s/%s: %v", router.Namespace, router.Name, err)
+		}
+	}
+	return changed
+}
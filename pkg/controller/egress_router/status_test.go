@@ -0,0 +1,106 @@
+package egress_router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	netopv1 "github.com/openshift/api/networkoperator/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// conditionsByType returns the status, reason and message of conditions, by type
+func conditionsByType(conditions []netopv1.EgressRouterStatusCondition) map[netopv1.EgressRouterStatusConditionType]string {
+	out := map[netopv1.EgressRouterStatusConditionType]string{}
+	for _, c := range conditions {
+		out[c.Type] = string(c.Status) + " " + c.Reason + " " + c.Message
+	}
+	return out
+}
+
+func TestRouterConditions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(conditionsByType(routerConditions(false, nil))).To(Equal(map[netopv1.EgressRouterStatusConditionType]string{
+		netopv1.EgressRouterAvailable:   "False NetworkAttachmentDefinitionMissing The NetworkAttachmentDefinition egress-router-cni-nad does not exist",
+		netopv1.EgressRouterProgressing: "True NetworkAttachmentDefinitionMissing ",
+		netopv1.EgressRouterDegraded:    "False AsExpected ",
+	}))
+	g.Expect(conditionsByType(routerConditions(true, nil))).To(Equal(map[netopv1.EgressRouterStatusConditionType]string{
+		netopv1.EgressRouterAvailable:   "False DeploymentMissing The Deployment egress-router-cni-deployment does not exist",
+		netopv1.EgressRouterProgressing: "True DeploymentMissing ",
+		netopv1.EgressRouterDegraded:    "False AsExpected ",
+	}))
+
+	// rolling out
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](1)},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1},
+	}
+	g.Expect(conditionsByType(routerConditions(true, deployment))).To(Equal(map[netopv1.EgressRouterStatusConditionType]string{
+		netopv1.EgressRouterAvailable:   "False DeploymentUnavailable The Deployment egress-router-cni-deployment has no available pod",
+		netopv1.EgressRouterProgressing: "True DeploymentRollingOut The Deployment egress-router-cni-deployment has 1 out of 1 pods updated and 0 available",
+		netopv1.EgressRouterDegraded:    "False AsExpected ",
+	}))
+
+	// stuck
+	deployment.Status.Conditions = []appsv1.DeploymentCondition{{
+		Type:    appsv1.DeploymentProgressing,
+		Status:  corev1.ConditionFalse,
+		Reason:  "ProgressDeadlineExceeded",
+		Message: `ReplicaSet "egress-router-cni-deployment-5d8f" has timed out progressing.`,
+	}}
+	g.Expect(conditionsByType(routerConditions(true, deployment))[netopv1.EgressRouterDegraded]).To(Equal(
+		`True DeploymentFailed The Deployment egress-router-cni-deployment failed: ReplicaSet "egress-router-cni-deployment-5d8f" has timed out progressing.`))
+
+	// rolled out
+	deployment.Status.AvailableReplicas = 1
+	deployment.Status.Conditions = nil
+	g.Expect(conditionsByType(routerConditions(true, deployment))).To(Equal(map[netopv1.EgressRouterStatusConditionType]string{
+		netopv1.EgressRouterAvailable:   "True DeploymentAvailable ",
+		netopv1.EgressRouterProgressing: "False AsExpected ",
+		netopv1.EgressRouterDegraded:    "False AsExpected ",
+	}))
+}
+
+func TestSetCondition(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	past := metav1.NewTime(time.Now().Add(-time.Hour))
+	conditions := []netopv1.EgressRouterStatusCondition{
+		{Type: netopv1.EgressRouterAvailable, Status: netopv1.ConditionFalse, Reason: "DeploymentUnavailable", LastTransitionTime: past},
+		{Type: netopv1.EgressRouterDegraded, Status: netopv1.ConditionFalse, Reason: "AsExpected", LastTransitionTime: past},
+	}
+
+	g.Expect(setCondition(&conditions, routerCondition(netopv1.EgressRouterDegraded, netopv1.ConditionFalse, "AsExpected", ""))).To(BeFalse())
+
+	// the transition time only changes with the status
+	g.Expect(setCondition(&conditions, routerCondition(netopv1.EgressRouterAvailable, netopv1.ConditionFalse, "DeploymentMissing", ""))).To(BeTrue())
+	g.Expect(conditions[0].Reason).To(Equal("DeploymentMissing"))
+	g.Expect(conditions[0].LastTransitionTime).To(Equal(past))
+	g.Expect(setCondition(&conditions, routerCondition(netopv1.EgressRouterAvailable, netopv1.ConditionTrue, "DeploymentAvailable", ""))).To(BeTrue())
+	g.Expect(conditions[0].LastTransitionTime.After(past.Time)).To(BeTrue())
+
+	g.Expect(setCondition(&conditions, routerCondition(netopv1.EgressRouterProgressing, netopv1.ConditionFalse, "AsExpected", ""))).To(BeTrue())
+	g.Expect(conditions).To(HaveLen(3))
+	g.Expect(conditions[2].LastTransitionTime.IsZero()).To(BeFalse())
+}
+
+func TestReconcileOwnerRouter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "egress", Name: deploymentName}}
+	g.Expect(reconcileOwnerRouter(context.TODO(), deployment)).To(BeEmpty())
+
+	deployment.OwnerReferences = []metav1.OwnerReference{{Kind: "EgressRouter", Name: "router", Controller: ptr.To(true)}}
+	requests := reconcileOwnerRouter(context.TODO(), deployment)
+	g.Expect(requests).To(HaveLen(1))
+	g.Expect(requests[0].Namespace).To(Equal("egress"))
+	g.Expect(requests[0].Name).To(Equal("router"))
+}
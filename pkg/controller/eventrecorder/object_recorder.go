@@ -0,0 +1,77 @@
+package eventrecorder
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// Component is the source component of the Events emitted by the operator
+const Component = "cluster-network-operator"
+
+// ObjectRecorder implements the library-go events.Recorder interface by
+// emitting Kubernetes Events on a single object, through a controller-runtime
+// EventRecorder. Events are logged as well, like with the LoggingRecorder.
+type ObjectRecorder struct {
+	recorder  record.EventRecorder
+	object    runtime.Object
+	component string
+}
+
+var _ events.Recorder = &ObjectRecorder{}
+
+// NewObjectRecorder returns a Recorder that emits the events on object.
+func NewObjectRecorder(recorder record.EventRecorder, object runtime.Object) *ObjectRecorder {
+	return &ObjectRecorder{
+		recorder:  recorder,
+		object:    object,
+		component: Component,
+	}
+}
+
+func (r *ObjectRecorder) Event(reason, message string) {
+	log.Println(message)
+	r.recorder.Event(r.object, corev1.EventTypeNormal, reason, message)
+}
+
+func (r *ObjectRecorder) Eventf(reason, messageFmt string, args ...interface{}) {
+	r.Event(reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *ObjectRecorder) Warning(reason, message string) {
+	log.Println(message)
+	r.recorder.Event(r.object, corev1.EventTypeWarning, reason, message)
+}
+
+func (r *ObjectRecorder) Warningf(reason, messageFmt string, args ...interface{}) {
+	r.Warning(reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// ForComponent only changes the ComponentName: the source of the events is the
+// one of the underlying EventRecorder.
+func (r *ObjectRecorder) ForComponent(componentName string) events.Recorder {
+	out := *r
+	out.component = componentName
+	return &out
+}
+
+func (r *ObjectRecorder) WithComponentSuffix(componentNameSuffix string) events.Recorder {
+	return r.ForComponent(fmt.Sprintf("%s-%s", r.component, componentNameSuffix))
+}
+
+func (r *ObjectRecorder) ComponentName() string {
+	return r.component
+}
+
+func (r *ObjectRecorder) Shutdown() {
+	//not implemented
+}
+
+func (r *ObjectRecorder) WithContext(_ context.Context) events.Recorder {
+	return r
+}
@@ -0,0 +1,30 @@
+package eventrecorder
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	netopv1 "github.com/openshift/cluster-network-operator/pkg/apis/network/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestObjectRecorder(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	fakeRecorder := record.NewFakeRecorder(10)
+	pki := &netopv1.OperatorPKI{ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-ovn-kubernetes", Name: "ovn"}}
+	recorder := NewObjectRecorder(fakeRecorder, pki)
+
+	// library-go controllers derive the recorder for their component
+	derived := recorder.WithComponentSuffix("cert-rotation-controller")
+	g.Expect(derived.ComponentName()).To(Equal("cluster-network-operator-cert-rotation-controller"))
+	g.Expect(recorder.ComponentName()).To(Equal(Component))
+
+	derived.Eventf("SignerUpdateRequired", "%q in %q requires a new signing cert/key pair", "ovn-ca", "openshift-ovn-kubernetes")
+	derived.Warning("RotationError", "failed")
+	g.Expect(fakeRecorder.Events).To(HaveLen(2))
+	g.Expect(<-fakeRecorder.Events).To(Equal(`Normal SignerUpdateRequired "ovn-ca" in "openshift-ovn-kubernetes" requires a new signing cert/key pair`))
+	g.Expect(<-fakeRecorder.Events).To(Equal("Warning RotationError failed"))
+}
@@ -0,0 +1,326 @@
+package extraclusters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
+	"github.com/openshift/cluster-network-operator/pkg/controller/clusterconfig"
+	"github.com/openshift/cluster-network-operator/pkg/controller/eventrecorder"
+	"github.com/openshift/cluster-network-operator/pkg/controller/operconfig"
+	"github.com/openshift/cluster-network-operator/pkg/controller/statusmanager"
+	"github.com/openshift/cluster-network-operator/pkg/hypershift"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	v1coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// AddToManagerFuncs are the controllers run for every extra cluster: the
+// operator configuration of the extra cluster is reconciled into it, like the
+// operator running in the cluster would.
+var AddToManagerFuncs = []func(manager.Manager, *statusmanager.StatusManager, cnoclient.Client, featuregates.FeatureGate) error{
+	operconfig.Add,
+	clusterconfig.Add,
+}
+
+// retryInterval is how long to wait before retrying to start the extra
+// clusters that failed to start, e.g. because their kubeconfig is not mounted yet.
+var retryInterval = time.Minute
+
+// Add creates a new ExtraClusters Controller and adds it to the Manager.
+func Add(mgr manager.Manager, status *statusmanager.StatusManager, c cnoclient.Client, featureGates featuregates.FeatureGate) error {
+	r := newReconciler(status, c, featureGates)
+	// Stop reconciling the extra clusters when the manager stops
+	if err := mgr.Add(r); err != nil {
+		return err
+	}
+	return add(mgr, r)
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(status *statusmanager.StatusManager, c cnoclient.Client, featureGates featuregates.FeatureGate) *ReconcileExtraClusters {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &ReconcileExtraClusters{
+		client:       c,
+		status:       status,
+		featureGates: featureGates,
+		hyperShift:   hypershift.NewHyperShiftConfig().Enabled,
+		ctx:          ctx,
+		cancel:       cancel,
+		clusters:     map[string]*extraCluster{},
+		failed:       map[string]error{},
+		failures:     make(chan event.GenericEvent, 1),
+	}
+	r.startCluster = r.startExtraCluster
+	return r
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r *ReconcileExtraClusters) error {
+	c, err := controller.New("extraclusters-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Only watch the extra-clusters ConfigMap in our namespace
+	cmInformer := v1coreinformers.NewFilteredConfigMapInformer(
+		r.client.Default().Kubernetes(),
+		names.APPLIED_NAMESPACE,
+		0, // don't resync
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+		func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", names.EXTRA_CLUSTERS_CONFIGMAP).String()
+		})
+	r.client.Default().AddCustomInformer(cmInformer) // Tell the ClusterClient about this informer
+
+	if err := c.Watch(&source.Informer{
+		Informer: cmInformer,
+		Handler:  &handler.EnqueueRequestForObject{},
+	}); err != nil {
+		return err
+	}
+
+	// Reconcile again when an extra cluster stops on a failure
+	return c.Watch(source.Channel(r.failures, &handler.EnqueueRequestForObject{}))
+}
+
+var _ reconcile.Reconciler = &ReconcileExtraClusters{}
+
+// ReconcileExtraClusters starts and stops the reconciliation of the extra
+// clusters listed in the extra-clusters ConfigMap. Each extra cluster gets its
+// own controllers and StatusManager, so its operator configuration and
+// ClusterOperator are handled as if the operator was running in it.
+type ReconcileExtraClusters struct {
+	client       cnoclient.Client
+	status       *statusmanager.StatusManager
+	featureGates featuregates.FeatureGate
+	hyperShift   bool
+
+	// ctx is the parent context of the extra clusters, cancelled when the
+	// manager stops.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// startCluster starts reconciling the cluster with the given name and
+	// kubeconfig path, until ctx is cancelled.
+	startCluster func(ctx context.Context, name, kubeConfig string) error
+
+	lock     sync.Mutex
+	clusters map[string]*extraCluster
+	// failed are the errors of the extra clusters that stopped on a failure
+	// since the last reconcile
+	failed map[string]error
+	// failures triggers a reconcile when an extra cluster stops on a failure
+	failures chan event.GenericEvent
+}
+
+// extraCluster is an extra cluster being reconciled
+type extraCluster struct {
+	kubeConfig string
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+// Start implements manager.Runnable. It stops every extra cluster when ctx is done.
+func (r *ReconcileExtraClusters) Start(ctx context.Context) error {
+	<-ctx.Done()
+	r.cancel()
+	return nil
+}
+
+// Reconcile starts reconciling the extra clusters added to the extra-clusters
+// ConfigMap, and stops reconciling the removed ones. A cluster whose kubeconfig
+// path changes is restarted.
+func (r *ReconcileExtraClusters) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer utilruntime.HandleCrash(r.status.SetDegradedOnPanicAndCrash)
+
+	if request.Namespace != names.APPLIED_NAMESPACE || request.Name != names.EXTRA_CLUSTERS_CONFIGMAP {
+		return reconcile.Result{}, nil
+	}
+	klog.Infof("Reconciling ConfigMap %s", request.NamespacedName)
+
+	desired := map[string]string{}
+	cm, err := r.client.Default().Kubernetes().CoreV1().ConfigMaps(request.Namespace).Get(ctx, request.Name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return reconcile.Result{}, fmt.Errorf("failed to get ConfigMap %s: %w", request.NamespacedName, err)
+	} else if err == nil && cm.Data != nil {
+		desired = cm.Data
+	}
+
+	errs := []error{}
+	if r.hyperShift && len(desired) > 0 {
+		errs = append(errs, fmt.Errorf("extra clusters cannot be reconciled by the operator of a hosted control plane"))
+		desired = map[string]string{}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for name, cluster := range r.clusters {
+		if kubeConfig, ok := desired[name]; ok && kubeConfig == cluster.kubeConfig {
+			continue
+		}
+		klog.Infof("Stopping the reconciliation of extra cluster %s", name)
+		cluster.cancel()
+		delete(r.clusters, name)
+	}
+
+	clusterNames := make([]string, 0, len(desired))
+	for name := range desired {
+		clusterNames = append(clusterNames, name)
+	}
+	sort.Strings(clusterNames)
+
+	for _, name := range clusterNames {
+		if _, ok := r.clusters[name]; ok {
+			continue
+		}
+		if err := validateClusterName(name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// A cluster that stopped on a failure is restarted after retryInterval
+		if err, ok := r.failed[name]; ok {
+			delete(r.failed, name)
+			errs = append(errs, fmt.Errorf("the reconciliation of cluster %s failed: %w", name, err))
+			continue
+		}
+		klog.Infof("Starting the reconciliation of extra cluster %s", name)
+		clusterCtx, cancel := context.WithCancel(r.ctx)
+		if err := r.startCluster(clusterCtx, name, desired[name]); err != nil {
+			cancel()
+			errs = append(errs, fmt.Errorf("failed to start the reconciliation of cluster %s: %w", name, err))
+			continue
+		}
+		r.clusters[name] = &extraCluster{kubeConfig: desired[name], ctx: clusterCtx, cancel: cancel}
+	}
+	// Forget the failures of the clusters that were removed
+	for name := range r.failed {
+		if _, ok := desired[name]; !ok {
+			delete(r.failed, name)
+		}
+	}
+
+	if len(errs) > 0 {
+		r.status.SetDegraded(statusmanager.ExtraClustersConfig, "InvalidExtraClusters",
+			fmt.Sprintf("Invalid extra clusters in ConfigMap %s: %v", request.NamespacedName, utilerrors.NewAggregate(errs)))
+		return reconcile.Result{RequeueAfter: retryInterval}, nil
+	}
+	r.status.SetNotDegraded(statusmanager.ExtraClustersConfig)
+	return reconcile.Result{}, nil
+}
+
+// validateClusterName checks that name can be used for an extra cluster
+func validateClusterName(name string) error {
+	if name == names.DefaultClusterName || name == names.ManagementClusterName {
+		return fmt.Errorf("cluster name %s is reserved", name)
+	}
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return fmt.Errorf("invalid cluster name %s: %v", name, errs)
+	}
+	return nil
+}
+
+// startExtraCluster creates the client, the controller-runtime manager, the
+// StatusManager and the controllers of an extra cluster, and runs them until
+// ctx is cancelled.
+func (r *ReconcileExtraClusters) startExtraCluster(ctx context.Context, name, kubeConfig string) error {
+	clusterClient, err := cnoclient.NewClusterClientForKubeConfig(kubeConfig)
+	if err != nil {
+		return err
+	}
+	c := cnoclient.NewSingleClusterClient(clusterClient)
+
+	mgr, err := manager.New(clusterClient.Config(), manager.Options{
+		MapperProvider: func(cfg *rest.Config, httpClient *http.Client) (meta.RESTMapper, error) {
+			return clusterClient.RESTMapper(), nil
+		},
+		// The controller metrics are served by the manager of the operator's own cluster
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		// Every extra cluster runs controllers with the same names
+		Controller: config.Controller{SkipNameValidation: ptr.To(true)},
+		Logger:     klog.Background().WithValues("cluster", name),
+	})
+	if err != nil {
+		return err
+	}
+
+	// From its own point of view, the extra cluster is a stand-alone cluster.
+	// Its controllers don't report the metrics of the operator's own cluster.
+	status := statusmanager.New(c, "network", names.StandAloneClusterName)
+	status.DisableMetrics()
+	status.SetEventRecorder(mgr.GetEventRecorderFor(eventrecorder.Component))
+	for _, f := range AddToManagerFuncs {
+		if err := f(mgr, status, c, r.featureGates); err != nil {
+			return err
+		}
+	}
+	if err := status.AddPodWatcher(mgr); err != nil {
+		return err
+	}
+	if err := status.AddMachineConfigWatchers(mgr); err != nil {
+		return err
+	}
+
+	go func() {
+		// Start informers; this blocks until they are synced, or ctx is cancelled
+		if err := c.Start(ctx); err != nil {
+			klog.Errorf("Failed to start the client of extra cluster %s: %v", name, err)
+			r.clusterFailed(ctx, name, fmt.Errorf("failed to start the client: %w", err))
+			return
+		}
+		if err := mgr.Start(ctx); err != nil {
+			klog.Errorf("Failed to run the controller-runtime manager of extra cluster %s: %v", name, err)
+			r.clusterFailed(ctx, name, fmt.Errorf("failed to run the controller-runtime manager: %w", err))
+			return
+		}
+		klog.Infof("Stopped the reconciliation of extra cluster %s", name)
+	}()
+	return nil
+}
+
+// clusterFailed stops the extra cluster that was started with ctx, and
+// reconciles the extra clusters again to report the failure and retry.
+func (r *ReconcileExtraClusters) clusterFailed(ctx context.Context, name string, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	cluster, ok := r.clusters[name]
+	if !ok || cluster.ctx != ctx || ctx.Err() != nil {
+		// The cluster was stopped or restarted in the meantime
+		return
+	}
+	cluster.cancel()
+	delete(r.clusters, name)
+	r.failed[name] = err
+	select {
+	case r.failures <- event.GenericEvent{Object: &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: names.APPLIED_NAMESPACE, Name: names.EXTRA_CLUSTERS_CONFIGMAP},
+	}}:
+	default:
+		// a reconcile is already pending
+	}
+}
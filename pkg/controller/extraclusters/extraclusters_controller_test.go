@@ -0,0 +1,119 @@
+package extraclusters
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/cluster-network-operator/pkg/client/fake"
+	"github.com/openshift/cluster-network-operator/pkg/controller/statusmanager"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+)
+
+func TestReconcileExtraClusters(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	client := fake.NewFakeClient(&configv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{Name: "network"}})
+	_, err := client.Default().OpenshiftOperatorClient().OperatorV1().Networks().Create(ctx,
+		&operv1.Network{ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG}}, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	r := newReconciler(statusmanager.New(client, "network", names.StandAloneClusterName), client, nil)
+	started := map[string]context.Context{}
+	r.startCluster = func(ctx context.Context, name, kubeConfig string) error {
+		if kubeConfig == "/missing" {
+			return fmt.Errorf("stat %s: no such file or directory", kubeConfig)
+		}
+		started[name+"="+kubeConfig] = ctx
+		return nil
+	}
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: names.APPLIED_NAMESPACE, Name: names.EXTRA_CLUSTERS_CONFIGMAP}}
+	reconcileData := func(data map[string]string) (reconcile.Result, *operv1.OperatorCondition) {
+		t.Helper()
+		cms := client.Default().Kubernetes().CoreV1().ConfigMaps(names.APPLIED_NAMESPACE)
+		if data == nil {
+			_ = cms.Delete(ctx, names.EXTRA_CLUSTERS_CONFIGMAP, metav1.DeleteOptions{})
+		} else {
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: names.APPLIED_NAMESPACE, Name: names.EXTRA_CLUSTERS_CONFIGMAP}, Data: data}
+			if _, err := cms.Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+				_, err = cms.Create(ctx, cm, metav1.CreateOptions{})
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		}
+		result, err := r.Reconcile(ctx, request)
+		g.Expect(err).NotTo(HaveOccurred())
+		oc, err := client.Default().OpenshiftOperatorClient().OperatorV1().Networks().Get(ctx, names.OPERATOR_CONFIG, metav1.GetOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+		return result, v1helpers.FindOperatorCondition(oc.Status.Conditions, operv1.OperatorStatusTypeDegraded)
+	}
+
+	// clusters are started
+	result, degraded := reconcileData(map[string]string{"edge-1": "/etc/edge-1/kubeconfig", "edge-2": "/etc/edge-2/kubeconfig"})
+	g.Expect(result).To(Equal(reconcile.Result{}))
+	g.Expect(degraded.Status).To(Equal(operv1.ConditionFalse))
+	g.Expect(started).To(HaveLen(2))
+	edge1 := started["edge-1=/etc/edge-1/kubeconfig"]
+	edge2 := started["edge-2=/etc/edge-2/kubeconfig"]
+	g.Expect(edge1).NotTo(BeNil())
+	g.Expect(edge2).NotTo(BeNil())
+
+	// a cluster whose kubeconfig changes is restarted, the others are left alone
+	result, degraded = reconcileData(map[string]string{"edge-1": "/etc/edge-1/kubeconfig", "edge-2": "/etc/edge-2/kubeconfig.new"})
+	g.Expect(result).To(Equal(reconcile.Result{}))
+	g.Expect(degraded.Status).To(Equal(operv1.ConditionFalse))
+	g.Expect(started).To(HaveLen(3))
+	g.Expect(edge1.Err()).NotTo(HaveOccurred())
+	g.Expect(edge2.Err()).To(MatchError(context.Canceled))
+
+	// invalid clusters degrade the operator, and are retried
+	result, degraded = reconcileData(map[string]string{"edge-1": "/etc/edge-1/kubeconfig", "default": "/etc/default/kubeconfig",
+		"Edge_3": "/etc/edge-3/kubeconfig", "edge-4": "/missing"})
+	g.Expect(result.RequeueAfter).To(Equal(retryInterval))
+	g.Expect(degraded.Status).To(Equal(operv1.ConditionTrue))
+	g.Expect(degraded.Reason).To(Equal("InvalidExtraClusters"))
+	g.Expect(degraded.Message).To(ContainSubstring("cluster name default is reserved"))
+	g.Expect(degraded.Message).To(ContainSubstring("invalid cluster name Edge_3"))
+	g.Expect(degraded.Message).To(ContainSubstring("failed to start the reconciliation of cluster edge-4"))
+	g.Expect(r.clusters).To(HaveLen(1))
+	g.Expect(r.clusters).To(HaveKey("edge-1"))
+
+	// a cluster whose controllers fail is stopped, reported and restarted on the next retry
+	r.clusterFailed(edge1, "edge-1", fmt.Errorf("failed to run the controller-runtime manager: boom"))
+	g.Expect(edge1.Err()).To(MatchError(context.Canceled))
+	g.Expect(r.clusters).To(BeEmpty())
+	g.Expect(r.failures).To(HaveLen(1))
+	<-r.failures
+	result, degraded = reconcileData(map[string]string{"edge-1": "/etc/edge-1/kubeconfig"})
+	g.Expect(result.RequeueAfter).To(Equal(retryInterval))
+	g.Expect(degraded.Status).To(Equal(operv1.ConditionTrue))
+	g.Expect(degraded.Message).To(ContainSubstring("the reconciliation of cluster edge-1 failed: failed to run the controller-runtime manager: boom"))
+	result, degraded = reconcileData(map[string]string{"edge-1": "/etc/edge-1/kubeconfig"})
+	g.Expect(result).To(Equal(reconcile.Result{}))
+	g.Expect(degraded.Status).To(Equal(operv1.ConditionFalse))
+	g.Expect(r.clusters).To(HaveKey("edge-1"))
+	g.Expect(started["edge-1=/etc/edge-1/kubeconfig"]).NotTo(Equal(edge1))
+	edge1 = started["edge-1=/etc/edge-1/kubeconfig"]
+
+	// failures of a stopped cluster are ignored
+	r.clusterFailed(edge2, "edge-2", fmt.Errorf("failed to start the client: boom"))
+	g.Expect(r.failures).To(BeEmpty())
+
+	// removing the ConfigMap stops every cluster
+	result, degraded = reconcileData(nil)
+	g.Expect(result).To(Equal(reconcile.Result{}))
+	g.Expect(degraded.Status).To(Equal(operv1.ConditionFalse))
+	g.Expect(r.clusters).To(BeEmpty())
+	g.Expect(edge1.Err()).To(MatchError(context.Canceled))
+}
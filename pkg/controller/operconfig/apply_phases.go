@@ -0,0 +1,62 @@
+package operconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/openshift/cluster-network-operator/pkg/apply"
+
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+var (
+	// applyPhaseWaitTimeout is how long a reconcile waits for the objects of an
+	// apply phase to become ready, before requeueing.
+	applyPhaseWaitTimeout = 10 * time.Second
+	applyPhasePollPeriod  = time.Second
+
+	// applyPhaseRequeuePeriod is when to try again after an apply phase did
+	// not become ready in time.
+	applyPhaseRequeuePeriod = 10 * time.Second
+
+	// applyPhaseDegradedTimeout is how long the remaining objects can be held
+	// back by an apply phase, before the operator reports Degraded.
+	applyPhaseDegradedTimeout = 10 * time.Minute
+)
+
+// applyPhaseWaitTime records that the remaining objects of the given generation
+// of the operator configuration are held back by an apply phase, and returns
+// for how long they have been.
+func (r *ReconcileOperConfig) applyPhaseWaitTime(generation int64, now time.Time) time.Duration {
+	if r.applyPhaseWaitStart.IsZero() || r.applyPhaseWaitGeneration != generation {
+		r.applyPhaseWaitStart = now
+		r.applyPhaseWaitGeneration = generation
+	}
+	return now.Sub(r.applyPhaseWaitStart)
+}
+
+// waitForApplyPhase waits for the objects of an apply phase that have the
+// wait-for annotation to be ready. If they aren't ready in time, it returns a
+// message saying what the phase is waiting for.
+func (r *ReconcileOperConfig) waitForApplyPhase(ctx context.Context, objs []*uns.Unstructured) (string, error) {
+	waitingFor := ""
+	err := wait.PollUntilContextTimeout(ctx, applyPhasePollPeriod, applyPhaseWaitTimeout, true, func(ctx context.Context) (bool, error) {
+		for _, obj := range objs {
+			ready, message, err := apply.IsReady(ctx, r.client, obj)
+			if err != nil {
+				return false, err
+			}
+			if !ready {
+				waitingFor = message
+				return false, nil
+			}
+		}
+		waitingFor = ""
+		return true, nil
+	})
+	if err != nil && waitingFor != "" && wait.Interrupted(err) {
+		return waitingFor, nil
+	}
+	return "", err
+}
@@ -0,0 +1,26 @@
+package operconfig
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestApplyPhaseWaitTime(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	r := &ReconcileOperConfig{}
+	start := time.Now()
+	g.Expect(r.applyPhaseWaitTime(1, start)).To(BeZero())
+	g.Expect(r.applyPhaseWaitTime(1, start.Add(5*time.Minute))).To(Equal(5 * time.Minute))
+	g.Expect(r.applyPhaseWaitTime(1, start.Add(11*time.Minute))).To(BeNumerically(">", applyPhaseDegradedTimeout))
+
+	// A new generation of the configuration starts waiting again
+	g.Expect(r.applyPhaseWaitTime(2, start.Add(12*time.Minute))).To(BeZero())
+	g.Expect(r.applyPhaseWaitTime(2, start.Add(13*time.Minute))).To(Equal(time.Minute))
+
+	// So does a reconcile that is no longer held back
+	r.applyPhaseWaitStart = time.Time{}
+	g.Expect(r.applyPhaseWaitTime(2, start.Add(14*time.Minute))).To(BeZero())
+}
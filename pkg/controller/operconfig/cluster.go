@@ -44,7 +44,10 @@ func (r *ReconcileOperConfig) MergeClusterConfig(ctx context.Context, operConfig
 		return nil
 	}
 	// If there are changes to the "downstream" networkconfig, commit it back
-	// to the apiserver
+	// to the apiserver. A dry run only renders the merged configuration.
+	if isDryRun(operConfig) {
+		return nil
+	}
 	log.Println("WARNING: Network.operator.openshift.io has fields being overwritten by Network.config.openshift.io configuration")
 	return r.UpdateOperConfig(ctx, operConfig)
 }
@@ -87,7 +90,7 @@ func (r *ReconcileOperConfig) ClusterNetworkStatus(ctx context.Context, operConf
 	}
 
 	// Update the cluster config status
-	status := network.StatusFromOperatorConfig(&operConfig.Spec, &clusterConfig.Status)
+	status := network.StatusFromOperatorConfig(&operConfig.Spec, bootstrapResult, &clusterConfig.Status)
 	if status == nil {
 		return nil, nil
 	}
@@ -109,7 +112,10 @@ func (r *ReconcileOperConfig) ClusterNetworkStatus(ctx context.Context, operConf
 		} else {
 			resetMigrationConditions(&clusterConfigWithConditions.Status.Conditions, nowTimestamp)
 		}
-		syncLiveMigrationConditionMetric(clusterConfigWithConditions.Status.Conditions)
+		if !r.status.MetricsDisabled() {
+			syncLiveMigrationConditionMetric(clusterConfigWithConditions.Status.Conditions)
+		}
+		r.recordNetworkTypeMigrationEvents(operConfig, clusterConfig.Status.Conditions, clusterConfigWithConditions.Status.Conditions)
 	}
 
 	status.Conditions = clusterConfigWithConditions.Status.Conditions
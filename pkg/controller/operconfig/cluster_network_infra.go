@@ -0,0 +1,63 @@
+package operconfig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+	"github.com/openshift/cluster-network-operator/pkg/platform"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	v1coreinformers "k8s.io/client-go/informers/core/v1"
+	v1corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// the ConfigMap holding the install-config
+	installConfigNamespace = "kube-system"
+	installConfigName      = "cluster-config-v1"
+)
+
+// newInstallConfigInformer returns an informer on the install-config ConfigMap
+// only, so that it isn't read from the apiserver on every reconcile.
+func (r *ReconcileOperConfig) newInstallConfigInformer() cache.SharedIndexInformer {
+	inf := v1coreinformers.NewFilteredConfigMapInformer(
+		r.client.Default().Kubernetes(),
+		installConfigNamespace,
+		0, // don't resync
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+		func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", installConfigName).String()
+		})
+	r.installConfigs = v1corelisters.NewConfigMapLister(inf.GetIndexer())
+	return inf
+}
+
+// setClusterNetworkInfra fills in the machine networks and the pod subnets of
+// the nodes, which changes of the cluster network are checked against. They
+// are read from the informers, as listing every node on each reconcile is too
+// expensive on large clusters.
+func (r *ReconcileOperConfig) setClusterNetworkInfra(ctx context.Context, infraStatus *bootstrap.InfraStatus) error {
+	if r.installConfigs != nil {
+		cm, err := r.installConfigs.ConfigMaps(installConfigNamespace).Get(installConfigName)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to retrieve ConfigMap %s/%s: %w", installConfigNamespace, installConfigName, err)
+		}
+		if infraStatus.MachineNetworks, err = platform.MachineNetworks(cm); err != nil {
+			return err
+		}
+	}
+
+	if r.nodes != nil {
+		nodes := &corev1.NodeList{}
+		if err := r.nodes.List(ctx, nodes); err != nil {
+			return fmt.Errorf("failed to list nodes: %w", err)
+		}
+		infraStatus.NodeSubnets = platform.NodeSubnets(nodes.Items)
+	}
+	return nil
+}
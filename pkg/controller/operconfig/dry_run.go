@@ -0,0 +1,134 @@
+package operconfig
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ghodss/yaml"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-network-operator/pkg/apply"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DryRunReport is the content of the dry-run report ConfigMap.
+type DryRunReport struct {
+	// Generation is the generation of the operator configuration that was dry-run.
+	Generation int64 `json:"generation"`
+	// Timestamp is when the dry run happened.
+	Timestamp metav1.Time `json:"timestamp"`
+	// Rendered is the number of objects that were rendered.
+	Rendered int `json:"rendered"`
+	// Changes are the objects that would be created or changed.
+	Changes []apply.ObjectChange `json:"changes,omitempty"`
+	// Error is why the operator configuration could not be dry-run, e.g.
+	// because it is invalid or the change is unsafe. Nothing is rendered then.
+	Error string `json:"error,omitempty"`
+}
+
+// isDryRun returns true if the operator configuration asks for a dry run
+// instead of applying the rendered objects.
+func isDryRun(operConfig *operv1.Network) bool {
+	return operConfig.GetAnnotations()[names.DryRunAnnotation] == "true"
+}
+
+// dryRunObjects dry-runs every rendered object and records the changes that
+// applying them would make in the dry-run report ConfigMap.
+// Errors for individual objects are recorded in the report rather than
+// returned, since e.g. objects in a namespace that doesn't exist yet can't be
+// dry-run.
+func (r *ReconcileOperConfig) dryRunObjects(ctx context.Context, operConfig *operv1.Network, objs []*uns.Unstructured) (*DryRunReport, error) {
+	report := &DryRunReport{
+		Generation: operConfig.Generation,
+		Timestamp:  metav1.NewTime(time.Now()),
+		Rendered:   len(objs),
+	}
+
+	for _, obj := range objs {
+		if apply.GetClusterName(obj) == "" {
+			// Set the same reference as a real apply, so that it doesn't show up as a change.
+			if err := controllerutil.SetControllerReference(operConfig, obj, r.client.ClientFor(apply.GetClusterName(obj)).Scheme()); err != nil {
+				return nil, fmt.Errorf("could not set reference for (%s) %s/%s: %w", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+			}
+		}
+
+		change, err := apply.DryRunObject(ctx, r.client, obj, ControllerName)
+		if err != nil {
+			log.Printf("Failed to dry-run (%s) %s/%s: %v", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+			change = &apply.ObjectChange{
+				Group:       obj.GroupVersionKind().Group,
+				Kind:        obj.GetKind(),
+				Namespace:   obj.GetNamespace(),
+				Name:        obj.GetName(),
+				ClusterName: apply.GetClusterName(obj),
+				Error:       err.Error(),
+			}
+		}
+		if change != nil {
+			report.Changes = append(report.Changes, *change)
+		}
+	}
+
+	cm, err := dryRunReportConfigMap(report)
+	if err != nil {
+		return nil, err
+	}
+	if err := apply.ApplyObject(ctx, r.client, cm, ControllerName); err != nil {
+		return nil, fmt.Errorf("could not apply dry-run report: %w", err)
+	}
+	return report, nil
+}
+
+// reportDryRunError records in the dry-run report why the operator
+// configuration could not be dry-run. It is not reported in the operator
+// conditions, which describe the applied configuration.
+func (r *ReconcileOperConfig) reportDryRunError(ctx context.Context, operConfig *operv1.Network, reason string) (reconcile.Result, error) {
+	log.Printf("Not dry-running the operator configuration: %s", reason)
+	cm, err := dryRunReportConfigMap(&DryRunReport{
+		Generation: operConfig.Generation,
+		Timestamp:  metav1.NewTime(time.Now()),
+		Error:      reason,
+	})
+	if err == nil {
+		err = apply.ApplyObject(ctx, r.client, cm, ControllerName)
+	}
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("could not apply dry-run report: %w", err)
+	}
+	// Nothing to retry until the operator configuration changes
+	return reconcile.Result{}, nil
+}
+
+// dryRunReportConfigMap renders the ConfigMap in which we store the dry-run report.
+func dryRunReportConfigMap(report *DryRunReport) (*corev1.ConfigMap, error) {
+	buf, err := yaml.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	summary := fmt.Sprintf("%d of %d rendered objects would change", len(report.Changes), report.Rendered)
+	if report.Error != "" {
+		summary = fmt.Sprintf("the operator configuration cannot be dry-run: %s", report.Error)
+	}
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: names.APPLIED_NAMESPACE,
+			Name:      names.DRY_RUN_REPORT_CONFIGMAP,
+		},
+		Data: map[string]string{
+			"summary": summary,
+			"report":  string(buf),
+		},
+	}, nil
+}
@@ -0,0 +1,48 @@
+package operconfig
+
+import (
+	"context"
+	"testing"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	cnofake "github.com/openshift/cluster-network-operator/pkg/client/fake"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReportDryRunError(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	client := cnofake.NewFakeClient()
+	var applied *corev1.ConfigMap
+	client.Default().Dynamic().(*fakedynamic.FakeDynamicClient).PrependReactor("patch", "configmaps",
+		func(action clienttesting.Action) (bool, runtime.Object, error) {
+			obj := &uns.Unstructured{}
+			g.Expect(obj.UnmarshalJSON(action.(clienttesting.PatchAction).GetPatch())).To(Succeed())
+			applied = &corev1.ConfigMap{}
+			g.Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, applied)).To(Succeed())
+			return true, obj, nil
+		})
+
+	r := &ReconcileOperConfig{client: client}
+	operConfig := &operv1.Network{ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG, Generation: 7,
+		Annotations: map[string]string{names.DryRunAnnotation: "true"}}}
+	result, err := r.reportDryRunError(context.Background(), operConfig, "unsafe configuration change: cannot change ClusterNetwork")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(reconcile.Result{}))
+
+	g.Expect(applied).NotTo(BeNil())
+	g.Expect(applied.Name).To(Equal(names.DRY_RUN_REPORT_CONFIGMAP))
+	g.Expect(applied.Data["summary"]).To(Equal("the operator configuration cannot be dry-run: unsafe configuration change: cannot change ClusterNetwork"))
+	g.Expect(applied.Data["report"]).To(ContainSubstring("generation: 7"))
+	g.Expect(applied.Data["report"]).To(ContainSubstring("error: 'unsafe configuration change: cannot change ClusterNetwork'"))
+}
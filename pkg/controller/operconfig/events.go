@@ -0,0 +1,66 @@
+package operconfig
+
+import (
+	"github.com/openshift/cluster-network-operator/pkg/controller/statusmanager"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// configVerdict is the outcome of the validation of a generation of the
+// operator configuration.
+type configVerdict struct {
+	generation int64
+	accepted   bool
+	message    string
+}
+
+// eventf emits an event on the operator configuration. It does nothing if the
+// reconciler has no event recorder, as in unit tests.
+func (r *ReconcileOperConfig) eventf(operConfig *operv1.Network, eventtype, reason, messageFmt string, args ...interface{}) {
+	if r.recorder == nil {
+		return
+	}
+	r.recorder.Eventf(operConfig, eventtype, reason, messageFmt, args...)
+}
+
+// recordConfigAccepted emits an event when a generation of the operator
+// configuration is accepted, unless it already was.
+func (r *ReconcileOperConfig) recordConfigAccepted(operConfig *operv1.Network) {
+	r.recordConfigVerdict(operConfig, configVerdict{generation: operConfig.Generation, accepted: true})
+}
+
+// recordConfigRejected emits an event when a generation of the operator
+// configuration is rejected, unless it already was for the same reason.
+func (r *ReconcileOperConfig) recordConfigRejected(operConfig *operv1.Network, message string) {
+	r.recordConfigVerdict(operConfig, configVerdict{generation: operConfig.Generation, message: message})
+}
+
+func (r *ReconcileOperConfig) recordConfigVerdict(operConfig *operv1.Network, verdict configVerdict) {
+	// The verdict is only recorded for the generation that is applied
+	if isDryRun(operConfig) {
+		return
+	}
+	if r.lastConfigVerdict != nil && *r.lastConfigVerdict == verdict {
+		return
+	}
+	r.lastConfigVerdict = &verdict
+	if verdict.accepted {
+		r.eventf(operConfig, corev1.EventTypeNormal, "OperatorConfigAccepted", "Accepted generation %d of the operator configuration", verdict.generation)
+	} else {
+		r.eventf(operConfig, corev1.EventTypeWarning, "OperatorConfigRejected", "Rejected generation %d of the operator configuration: %s", verdict.generation, verdict.message)
+	}
+}
+
+// recordNetworkTypeMigrationEvents emits an event on the operator
+// configuration for every network type migration condition of the cluster
+// configuration whose status changes from oldConditions to newConditions.
+func (r *ReconcileOperConfig) recordNetworkTypeMigrationEvents(operConfig *operv1.Network, oldConditions, newConditions []metav1.Condition) {
+	conditionTypes := append([]string{names.NetworkTypeMigrationInProgress}, networkTypeMigrationConditionTypes...)
+	eventf := func(eventtype, reason, messageFmt string, args ...interface{}) {
+		r.eventf(operConfig, eventtype, reason, messageFmt, args...)
+	}
+	statusmanager.RecordMigrationPhaseChanges(eventf, conditionTypes, oldConditions, newConditions)
+}
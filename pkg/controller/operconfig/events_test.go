@@ -0,0 +1,64 @@
+package operconfig
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestRecordConfigVerdict(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileOperConfig{recorder: recorder}
+	operConfig := &operv1.Network{ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG, Generation: 3}}
+
+	// each verdict is emitted once per generation
+	r.recordConfigRejected(operConfig, "unsafe configuration change: cannot change ClusterNetwork")
+	r.recordConfigRejected(operConfig, "unsafe configuration change: cannot change ClusterNetwork")
+	r.recordConfigAccepted(operConfig)
+	r.recordConfigAccepted(operConfig)
+	operConfig.Generation = 4
+	r.recordConfigAccepted(operConfig)
+
+	g.Expect(recorder.Events).To(HaveLen(3))
+	g.Expect(<-recorder.Events).To(Equal("Warning OperatorConfigRejected Rejected generation 3 of the operator configuration: unsafe configuration change: cannot change ClusterNetwork"))
+	g.Expect(<-recorder.Events).To(Equal("Normal OperatorConfigAccepted Accepted generation 3 of the operator configuration"))
+	g.Expect(<-recorder.Events).To(Equal("Normal OperatorConfigAccepted Accepted generation 4 of the operator configuration"))
+
+	// dry runs don't record a verdict, so that it is emitted once the
+	// generation is applied
+	operConfig.Generation = 5
+	operConfig.Annotations = map[string]string{names.DryRunAnnotation: "true"}
+	r.recordConfigAccepted(operConfig)
+	g.Expect(recorder.Events).To(BeEmpty())
+	operConfig.Annotations = nil
+	r.recordConfigAccepted(operConfig)
+	g.Expect(<-recorder.Events).To(Equal("Normal OperatorConfigAccepted Accepted generation 5 of the operator configuration"))
+}
+
+func TestRecordNetworkTypeMigrationEvents(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileOperConfig{recorder: recorder}
+	operConfig := &operv1.Network{ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG}}
+
+	oldConditions := []metav1.Condition{
+		{Type: names.NetworkTypeMigrationInProgress, Status: metav1.ConditionTrue, Reason: "NetworkTypeMigrationStarted"},
+		{Type: names.NetworkTypeMigrationTargetCNIAvailable, Status: metav1.ConditionFalse},
+	}
+	newConditions := []metav1.Condition{
+		{Type: names.NetworkTypeMigrationInProgress, Status: metav1.ConditionTrue, Reason: "NetworkTypeMigrationStarted"},
+		{Type: names.NetworkTypeMigrationTargetCNIAvailable, Status: metav1.ConditionTrue, Reason: "TargetCNIAvailable", Message: "ovn-kubernetes is running"},
+	}
+	r.recordNetworkTypeMigrationEvents(operConfig, oldConditions, newConditions)
+
+	g.Expect(recorder.Events).To(HaveLen(1))
+	g.Expect(<-recorder.Events).To(Equal("Normal MigrationPhaseChanged NetworkTypeMigrationTargetCNIAvailable is True: TargetCNIAvailable ovn-kubernetes is running"))
+}
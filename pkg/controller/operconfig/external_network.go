@@ -0,0 +1,60 @@
+package operconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+	"github.com/openshift/cluster-network-operator/pkg/hypershift"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// labelExternalNetworkDaemonSets adds the names.GenerateStatusLabel label to
+// the DaemonSets of the third-party CNI of the external network mode, so that
+// the status manager includes them in the operator status like the DaemonSets
+// we render.
+func (r *ReconcileOperConfig) labelExternalNetworkDaemonSets(ctx context.Context, bootstrapResult *bootstrap.BootstrapResult) error {
+	ext := bootstrapResult.ExternalNetwork
+	if !ext.Enabled {
+		return nil
+	}
+
+	value := names.StandAloneClusterName
+	if hypershift.NewHyperShiftConfig().Enabled {
+		value = bootstrapResult.Infra.InfraName
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]string{names.GenerateStatusLabel: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, nsn := range ext.DaemonSets {
+		ds := &appsv1.DaemonSet{}
+		if err := r.client.Default().CRClient().Get(ctx, nsn, ds); err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Errorf("DaemonSet %s of the external network does not exist", nsn)
+			}
+			return fmt.Errorf("could not retrieve DaemonSet %s of the external network: %w", nsn, err)
+		}
+		if ds.GetLabels()[names.GenerateStatusLabel] == value {
+			continue
+		}
+		log.Printf("Labeling DaemonSet %s of the external network to include it in the operator status", nsn)
+		if err := r.client.Default().CRClient().Patch(ctx, ds, crclient.RawPatch(types.MergePatchType, patch)); err != nil {
+			return fmt.Errorf("could not label DaemonSet %s of the external network: %w", nsn, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package operconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/openshift/cluster-network-operator/pkg/apply"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxReconcileHistory is the number of records kept in the reconcile history.
+const maxReconcileHistory = 100
+
+// maxRecordedChanges is the number of changed objects listed in a record.
+const maxRecordedChanges = 20
+
+// ReconcileRecord describes a reconcile of the operator configuration. It is
+// stored in the reconcile history ConfigMap.
+type ReconcileRecord struct {
+	// Timestamp is when the reconcile started.
+	Timestamp metav1.Time `json:"timestamp"`
+	// Duration is how long the reconcile took.
+	Duration metav1.Duration `json:"duration"`
+	// Generation is the generation of the operator configuration.
+	Generation int64 `json:"generation"`
+	// SpecHash identifies the operator configuration that was reconciled,
+	// with the defaults filled in.
+	SpecHash string `json:"specHash"`
+	// RenderHash identifies the rendered objects, if rendering succeeded.
+	RenderHash string `json:"renderHash,omitempty"`
+	// DryRun is set if the objects were dry-run instead of applied.
+	DryRun bool `json:"dryRun,omitempty"`
+	// RolledBack is set if the last known-good configuration was reconciled
+	// instead of the operator configuration.
+	RolledBack bool `json:"rolledBack,omitempty"`
+	// Applied and Failed are the number of objects that were applied and that
+	// failed to apply.
+	Applied int `json:"applied"`
+	Failed  int `json:"failed,omitempty"`
+	// Changed is the number of objects the apply created or changed.
+	Changed int `json:"changed"`
+	// ChangedObjects lists the first objects that were created or changed.
+	ChangedObjects []string `json:"changedObjects,omitempty"`
+	// Error is the error the reconcile failed with, if any.
+	Error string `json:"error,omitempty"`
+	// Repeats is the number of identical reconciles that followed this one,
+	// and LastTimestamp when the last of them started.
+	Repeats       int          `json:"repeats,omitempty"`
+	LastTimestamp *metav1.Time `json:"lastTimestamp,omitempty"`
+}
+
+// sameOutcome returns true if the reconciles of a and b had the same result,
+// without changing anything.
+func (a *ReconcileRecord) sameOutcome(b *ReconcileRecord) bool {
+	return a.Changed == 0 && b.Changed == 0 &&
+		a.Generation == b.Generation &&
+		a.SpecHash == b.SpecHash &&
+		a.RenderHash == b.RenderHash &&
+		a.DryRun == b.DryRun &&
+		a.RolledBack == b.RolledBack &&
+		a.Applied == b.Applied &&
+		a.Failed == b.Failed &&
+		a.Error == b.Error
+}
+
+// addChangedObject records that the apply created or changed obj.
+func (a *ReconcileRecord) addChangedObject(obj *uns.Unstructured) {
+	a.Changed++
+	if len(a.ChangedObjects) < maxRecordedChanges {
+		a.ChangedObjects = append(a.ChangedObjects, apply.NewInventoryEntry(obj).String())
+	}
+}
+
+// GetReconcileHistory retrieves the reconcile history, oldest record first.
+// Returns nil with no error if there is no history yet.
+func GetReconcileHistory(ctx context.Context, client crclient.Client) ([]ReconcileRecord, error) {
+	cm := &corev1.ConfigMap{}
+	err := client.Get(ctx, types.NamespacedName{Namespace: names.APPLIED_NAMESPACE, Name: names.RECONCILE_HISTORY_CONFIGMAP}, cm)
+	if err != nil && apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return ReconcileHistoryFromConfigMap(cm)
+}
+
+// ReconcileHistoryFromConfigMap decodes the reconcile history stored in cm.
+func ReconcileHistoryFromConfigMap(cm *corev1.ConfigMap) ([]ReconcileRecord, error) {
+	history := []ReconcileRecord{}
+	if err := json.Unmarshal([]byte(cm.Data["history"]), &history); err != nil {
+		return nil, fmt.Errorf("failed to decode the reconcile history: %w", err)
+	}
+	return history, nil
+}
+
+// appendReconcileRecord adds record to history. A record with the same
+// outcome as the last one is folded into it, so that periodic resyncs don't
+// push everything else out of the history. Only the last
+// maxReconcileHistory records are kept.
+func appendReconcileRecord(history []ReconcileRecord, record ReconcileRecord) []ReconcileRecord {
+	if n := len(history); n > 0 && history[n-1].sameOutcome(&record) {
+		last := &history[n-1]
+		last.Repeats++
+		last.LastTimestamp = record.Timestamp.DeepCopy()
+		last.Duration = record.Duration
+		return history
+	}
+	history = append(history, record)
+	if len(history) > maxReconcileHistory {
+		history = history[len(history)-maxReconcileHistory:]
+	}
+	return history
+}
+
+// reconcileHistoryConfigMap renders the ConfigMap in which we store the reconcile history.
+func reconcileHistoryConfigMap(history []ReconcileRecord) (*corev1.ConfigMap, error) {
+	buf, err := json.Marshal(history)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: names.APPLIED_NAMESPACE,
+			Name:      names.RECONCILE_HISTORY_CONFIGMAP,
+			// Lets the console find and describe the history
+			Labels: map[string]string{
+				"app.kubernetes.io/component":  "reconcile-history",
+				"app.kubernetes.io/managed-by": "cluster-network-operator",
+				"app.kubernetes.io/part-of":    "cluster-network-operator",
+			},
+			Annotations: map[string]string{
+				"openshift.io/description": "The last reconciles of the network operator configuration, oldest first, as a JSON list in the history key.",
+			},
+		},
+		Data: map[string]string{
+			"history": string(buf),
+		},
+	}, nil
+}
+
+// recordReconcile adds record to the reconcile history. Failures are only
+// logged: the history must not get in the way of reconciling.
+func (r *ReconcileOperConfig) recordReconcile(ctx context.Context, record *ReconcileRecord) {
+	record.Duration = metav1.Duration{Duration: time.Since(record.Timestamp.Time).Round(time.Millisecond)}
+
+	history, err := GetReconcileHistory(ctx, r.client.Default().CRClient())
+	if err != nil {
+		log.Printf("Failed to retrieve the reconcile history, starting a new one: %v", err)
+	}
+	cm, err := reconcileHistoryConfigMap(appendReconcileRecord(history, *record))
+	if err == nil {
+		err = apply.ApplyObject(ctx, r.client, cm, ControllerName)
+	}
+	if err != nil {
+		log.Printf("Failed to record the reconcile in the reconcile history: %v", err)
+	}
+}
+
+// contentHash returns a short hash of the JSON representation of v.
+func contentHash(v interface{}) string {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(buf)
+	return hex.EncodeToString(sum[:8])
+}
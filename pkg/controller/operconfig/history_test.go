@@ -0,0 +1,88 @@
+package operconfig
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestAppendReconcileRecord(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	newRecord := func(minute int, specHash string) ReconcileRecord {
+		return ReconcileRecord{
+			Timestamp:  metav1.NewTime(start.Add(time.Duration(minute) * time.Minute)),
+			Duration:   metav1.Duration{Duration: time.Duration(minute+1) * time.Second},
+			Generation: 1,
+			SpecHash:   specHash,
+			RenderHash: "render-" + specHash,
+			Applied:    10,
+		}
+	}
+
+	// the first reconcile changes things
+	first := newRecord(0, "a")
+	obj := &uns.Unstructured{}
+	obj.SetAPIVersion("apps/v1")
+	obj.SetKind("DaemonSet")
+	obj.SetNamespace("openshift-ovn-kubernetes")
+	obj.SetName("ovnkube-node")
+	first.addChangedObject(obj)
+	history := appendReconcileRecord(nil, first)
+	g.Expect(history).To(HaveLen(1))
+	g.Expect(history[0].ChangedObjects).To(Equal([]string{"(apps/v1, Kind=DaemonSet) openshift-ovn-kubernetes/ovnkube-node"}))
+
+	// resyncs that change nothing are folded together
+	history = appendReconcileRecord(history, newRecord(3, "a"))
+	history = appendReconcileRecord(history, newRecord(6, "a"))
+	g.Expect(history).To(HaveLen(2))
+	g.Expect(history[1].Timestamp.Time).To(Equal(start.Add(3 * time.Minute)))
+	g.Expect(history[1].Repeats).To(Equal(1))
+	g.Expect(history[1].LastTimestamp.Time).To(Equal(start.Add(6 * time.Minute)))
+	g.Expect(history[1].Duration.Duration).To(Equal(7 * time.Second))
+
+	// but not with a failure
+	failed := newRecord(9, "a")
+	failed.Error = "could not apply"
+	history = appendReconcileRecord(history, failed)
+	g.Expect(history).To(HaveLen(3))
+
+	// only the last records are kept
+	for i := 0; i < maxReconcileHistory; i++ {
+		history = appendReconcileRecord(history, newRecord(12+i, fmt.Sprintf("spec-%d", i)))
+	}
+	g.Expect(history).To(HaveLen(maxReconcileHistory))
+	g.Expect(history[0].SpecHash).To(Equal("spec-0"))
+	g.Expect(history[maxReconcileHistory-1].SpecHash).To(Equal(fmt.Sprintf("spec-%d", maxReconcileHistory-1)))
+
+	// round trip through the ConfigMap
+	cm, err := reconcileHistoryConfigMap(history)
+	g.Expect(err).NotTo(HaveOccurred())
+	decoded, err := ReconcileHistoryFromConfigMap(cm)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(decoded).To(HaveLen(maxReconcileHistory))
+	reencoded, err := reconcileHistoryConfigMap(decoded)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reencoded.Data).To(Equal(cm.Data))
+}
+
+func TestAddChangedObject(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	record := &ReconcileRecord{}
+	for i := 0; i < maxRecordedChanges+5; i++ {
+		obj := &uns.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName(fmt.Sprintf("cm-%d", i))
+		record.addChangedObject(obj)
+	}
+	g.Expect(record.Changed).To(Equal(maxRecordedChanges + 5))
+	g.Expect(record.ChangedObjects).To(HaveLen(maxRecordedChanges))
+}
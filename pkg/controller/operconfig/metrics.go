@@ -0,0 +1,36 @@
+package operconfig
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var metricRenderDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "openshift_network_operator",
+	Name:      "render_duration_seconds",
+	Help:      "Time taken to render the manifests of the operator configuration, labeled with the default network type.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"network_type"})
+
+var metricAppliedObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "openshift_network_operator",
+	Name:      "reconcile_applied_objects",
+	Help: "The number of rendered objects handled by the last reconcile of the operator configuration, " +
+		"labeled with the result: 'applied' or 'failed'.",
+}, []string{"result"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(metricRenderDuration)
+	ctrlmetrics.Registry.MustRegister(metricAppliedObjects)
+}
+
+func observeRenderDuration(networkType string, start time.Time) {
+	metricRenderDuration.WithLabelValues(networkType).Observe(time.Since(start).Seconds())
+}
+
+func setAppliedObjectsMetric(applied, failed int) {
+	metricAppliedObjects.WithLabelValues("applied").Set(float64(applied))
+	metricAppliedObjects.WithLabelValues("failed").Set(float64(failed))
+}
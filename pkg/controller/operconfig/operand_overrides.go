@@ -0,0 +1,25 @@
+package operconfig
+
+import (
+	"context"
+
+	"github.com/openshift/cluster-network-operator/pkg/names"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// getOperandOverrides retrieves the ConfigMap with the operand overrides.
+// Returns nil with no error if there is none.
+func getOperandOverrides(ctx context.Context, client crclient.Client) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{}
+	err := client.Get(ctx, types.NamespacedName{Namespace: names.APPLIED_NAMESPACE, Name: names.OPERAND_OVERRIDES_CONFIGMAP}, cm)
+	if err != nil && apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return cm, nil
+}
@@ -16,6 +16,7 @@ import (
 	operv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/cluster-network-operator/pkg/apply"
 	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
+	"github.com/openshift/cluster-network-operator/pkg/controller/eventrecorder"
 	"github.com/openshift/cluster-network-operator/pkg/controller/statusmanager"
 	"github.com/openshift/cluster-network-operator/pkg/names"
 	"github.com/openshift/cluster-network-operator/pkg/network"
@@ -33,7 +34,9 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	v1coreinformers "k8s.io/client-go/informers/core/v1"
+	v1corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -73,7 +76,9 @@ func newReconciler(mgr manager.Manager, status *statusmanager.StatusManager, c c
 		client:       c,
 		status:       status,
 		mapper:       mgr.GetRESTMapper(),
+		nodes:        mgr.GetCache(),
 		featureGates: featureGates,
+		recorder:     mgr.GetEventRecorderFor(eventrecorder.Component),
 	}, nil
 }
 
@@ -106,7 +111,7 @@ func add(mgr manager.Manager, r *ReconcileOperConfig) error {
 		return err
 	}
 
-	// Watch for changes to primary resource Network (as long as the spec changes)
+	// Watch for changes to primary resource Network (as long as the spec or the dry-run annotation changes)
 	err = c.Watch(source.Kind[crclient.Object](mgr.GetCache(), &operv1.Network{}, &handler.EnqueueRequestForObject{}, predicate.Funcs{
 		UpdateFunc: func(evt event.UpdateEvent) bool {
 			old, ok := evt.ObjectOld.(*operv1.Network)
@@ -117,7 +122,8 @@ func add(mgr manager.Manager, r *ReconcileOperConfig) error {
 			if !ok {
 				return true
 			}
-			if reflect.DeepEqual(old.Spec, new.Spec) {
+			if reflect.DeepEqual(old.Spec, new.Spec) &&
+				old.GetAnnotations()[names.DryRunAnnotation] == new.GetAnnotations()[names.DryRunAnnotation] {
 				log.Printf("Skipping reconcile of Network.operator.openshift.io: spec unchanged")
 				return false
 			}
@@ -139,6 +145,9 @@ func add(mgr manager.Manager, r *ReconcileOperConfig) error {
 
 	r.client.Default().AddCustomInformer(cmInformer) // Tell the ClusterClient about this informer
 
+	// The install-config is only read, so it isn't watched
+	r.client.Default().AddCustomInformer(r.newInstallConfigInformer())
+
 	if err := c.Watch(&source.Informer{
 		Informer: cmInformer,
 		Handler:  handler.EnqueueRequestsFromMapFunc(reconcileOperConfig),
@@ -147,7 +156,12 @@ func add(mgr manager.Manager, r *ReconcileOperConfig) error {
 			predicate.NewPredicateFuncs(func(object crclient.Object) bool {
 				// Ignore ConfigMaps we manage as part of this loop
 				return !(object.GetName() == "network-operator-lock" ||
-					object.GetName() == "applied-cluster")
+					object.GetName() == "applied-cluster" ||
+					object.GetName() == names.DRY_RUN_REPORT_CONFIGMAP ||
+					object.GetName() == names.INVENTORY_CONFIGMAP ||
+					object.GetName() == names.KNOWN_GOOD_CONFIGMAP ||
+					object.GetName() == names.EXTRA_CLUSTERS_CONFIGMAP ||
+					object.GetName() == names.RECONCILE_HISTORY_CONFIGMAP)
 			}),
 		},
 	}); err != nil {
@@ -188,15 +202,52 @@ type ReconcileOperConfig struct {
 	status *statusmanager.StatusManager
 	mapper meta.RESTMapper
 
+	// nodes reads the nodes from the cache of the manager, which watches them
+	nodes crclient.Reader
+	// installConfigs lists the install-config ConfigMap
+	installConfigs v1corelisters.ConfigMapLister
+
 	// If we can skip cleaning up the MTU prober job.
 	mtuProberCleanedUp bool
 	// maintain the copy of feature gates in the cluster
 	featureGates featuregates.FeatureGate
+
+	// recorder emits the events on the operator configuration
+	recorder record.EventRecorder
+	// The last verdict on the operator configuration, so that it is only
+	// emitted once per generation
+	lastConfigVerdict *configVerdict
+	// Since when, and for which generation, the objects of the last apply
+	// phases have been held back
+	applyPhaseWaitStart      time.Time
+	applyPhaseWaitGeneration int64
+	// The UIDs of the Services that the service network migration deleted for
+	// their owner to recreate them in the target service network, by
+	// namespace/name
+	serviceNetworkMigrationDeleted map[string]types.UID
 }
 
 // Reconcile updates the state of the cluster to match that which is desired
-// in the operator configuration (Network.operator.openshift.io)
+// in the operator configuration (Network.operator.openshift.io), and records
+// what it did in the reconcile history.
 func (r *ReconcileOperConfig) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	if r.status.MetricsDisabled() {
+		ctx = apply.WithoutMetrics(ctx)
+	}
+	record := &ReconcileRecord{Timestamp: metav1.NewTime(time.Now())}
+	result, err := r.reconcile(ctx, request, record)
+	// Reconciles that did not get to the operator configuration are not recorded
+	if record.SpecHash != "" {
+		if err != nil {
+			record.Error = err.Error()
+		}
+		r.recordReconcile(ctx, record)
+	}
+	return result, err
+}
+
+// reconcile implements Reconcile, filling in record along the way.
+func (r *ReconcileOperConfig) reconcile(ctx context.Context, request reconcile.Request, record *ReconcileRecord) (reconcile.Result, error) {
 	defer utilruntime.HandleCrash(r.status.SetDegradedOnPanicAndCrash)
 
 	log.Printf("Reconciling Network.operator.openshift.io %s\n", request.Name)
@@ -230,6 +281,8 @@ func (r *ReconcileOperConfig) Reconcile(ctx context.Context, request reconcile.R
 		log.Printf("Operator configuration state is %s - skipping operconfig reconciliation", operConfig.Spec.ManagementState)
 		return reconcile.Result{}, nil
 	}
+	record.Generation = operConfig.Generation
+	record.SpecHash = contentHash(operConfig.Spec)
 
 	// Fetch the Network.config.openshift.io instance
 	clusterConfig := &configv1.Network{}
@@ -239,7 +292,7 @@ func (r *ReconcileOperConfig) Reconcile(ctx context.Context, request reconcile.R
 		return reconcile.Result{}, err
 	}
 	// Merge in the cluster configuration, in case the administrator has updated some "downstream" fields
-	// This will also commit the change back to the apiserver.
+	// This will also commit the change back to the apiserver, unless this is a dry run.
 	if err := r.MergeClusterConfig(ctx, operConfig, clusterConfig); err != nil {
 		log.Printf("Failed to merge the cluster configuration: %v", err)
 		// not set degraded if the err is a version conflict, but return a reconcile err for retry.
@@ -254,10 +307,20 @@ func (r *ReconcileOperConfig) Reconcile(ctx context.Context, request reconcile.R
 	network.DeprecatedCanonicalize(&operConfig.Spec)
 
 	// Validate the configuration
-	if err := network.Validate(&operConfig.Spec); err != nil {
+	validationErrs := network.ValidateFields(&operConfig.Spec)
+	// A dry run of a configuration must not change the status of the applied one
+	if !isDryRun(operConfig) {
+		r.status.SetConfigValidation(validationErrs)
+	}
+	if len(validationErrs) > 0 {
+		err := fmt.Errorf("invalid configuration: %v", validationErrs)
+		if isDryRun(operConfig) {
+			return r.reportDryRunError(ctx, operConfig, err.Error())
+		}
 		log.Printf("Failed to validate Network.operator.openshift.io.Spec: %v", err)
 		r.status.SetDegraded(statusmanager.OperatorConfig, "InvalidOperatorConfig",
 			fmt.Sprintf("The operator configuration is invalid (%v). Use 'oc edit network.operator.openshift.io cluster' to fix.", err))
+		r.recordConfigRejected(operConfig, err.Error())
 		return reconcile.Result{}, err
 	}
 
@@ -275,6 +338,10 @@ func (r *ReconcileOperConfig) Reconcile(ctx context.Context, request reconcile.R
 		log.Printf("Failed to retrieve infrastructure status: %v", err)
 		return reconcile.Result{}, err
 	}
+	if err := r.setClusterNetworkInfra(ctx, infraStatus); err != nil {
+		log.Printf("Failed to retrieve infrastructure status: %v", err)
+		return reconcile.Result{}, err
+	}
 
 	// If we need to, probe the host's MTU via a Job.
 	// Note that running clusters have no need of this but we want the configmap
@@ -283,6 +350,11 @@ func (r *ReconcileOperConfig) Reconcile(ctx context.Context, request reconcile.R
 	mtu := 0
 	err = r.client.Default().CRClient().Get(ctx, types.NamespacedName{Namespace: util.MTU_CM_NAMESPACE, Name: util.MTU_CM_NAME}, &corev1.ConfigMap{})
 	if network.NeedMTUProbe(prev, &operConfig.Spec) || (apierrors.IsNotFound(err) && infraStatus.HostedControlPlane == nil) {
+		// The probe runs a Job and records the MTU in a ConfigMap
+		if isDryRun(operConfig) {
+			return r.reportDryRunError(ctx, operConfig,
+				fmt.Sprintf("the host MTU must be probed first, remove the %s annotation to apply the configuration", names.DryRunAnnotation))
+		}
 		mtu, err = r.probeMTU(ctx, operConfig, infraStatus)
 		if err != nil {
 			log.Printf("Failed to probe MTU: %v", err)
@@ -291,6 +363,7 @@ func (r *ReconcileOperConfig) Reconcile(ctx context.Context, request reconcile.R
 			return reconcile.Result{}, fmt.Errorf("could not probe MTU -- maybe no available nodes: %w", err)
 		}
 		log.Printf("Using detected MTU %d", mtu)
+		r.eventf(operConfig, corev1.EventTypeNormal, "MTUProbed", "Detected a host MTU of %d", mtu)
 	}
 
 	// up-convert Prev by filling defaults
@@ -302,19 +375,51 @@ func (r *ReconcileOperConfig) Reconcile(ctx context.Context, request reconcile.R
 	// Fill all defaults explicitly
 	network.FillDefaults(&newOperConfig.Spec, prev, mtu)
 
+	serviceNetworkMigration, err := getServiceNetworkMigration(newOperConfig, infraStatus, r.featureGates)
+	if err != nil && isDryRun(operConfig) {
+		return r.reportDryRunError(ctx, operConfig, fmt.Sprintf("invalid service network migration: %v", err))
+	} else if err != nil {
+		log.Printf("Invalid service network migration: %v", err)
+		reason := "InvalidServiceNetworkMigration"
+		if errors.Is(err, errServiceNetworkMigrationAbandoned) {
+			reason = "ServiceNetworkMigrationAbandoned"
+		}
+		r.status.SetDegraded(statusmanager.OperatorConfig, reason,
+			fmt.Sprintf("Not migrating the service network: %v. Use 'oc edit network.operator.openshift.io cluster' to fix the %s annotation.",
+				err, names.ServiceNetworkMigrationAnnotation))
+		r.recordConfigRejected(operConfig, fmt.Sprintf("invalid service network migration: %v", err))
+		return reconcile.Result{}, err
+	}
+
 	// Compare against previous applied configuration to see if this change
 	// is safe.
 	if prev != nil {
+		safePrev := prev
+		if serviceNetworkMigration.allowsChange(prev, &newOperConfig.Spec) {
+			// The switch to the target service network is part of the migration
+			safePrev = prev.DeepCopy()
+			safePrev.ServiceNetwork = newOperConfig.Spec.ServiceNetwork
+		}
 		// We may need to fill defaults here -- sort of as a poor-man's
 		// upconversion scheme -- if we add additional fields to the config.
-		err = network.IsChangeSafe(prev, &newOperConfig.Spec, infraStatus)
-		if err != nil {
+		err = network.IsChangeSafe(safePrev, &newOperConfig.Spec, infraStatus)
+		if err != nil && isDryRun(operConfig) {
+			return r.reportDryRunError(ctx, operConfig, fmt.Sprintf("unsafe configuration change: %v", err))
+		} else if err != nil {
 			log.Printf("Not applying unsafe change: %v", err)
 			r.status.SetDegraded(statusmanager.OperatorConfig, "InvalidOperatorConfig",
 				fmt.Sprintf("Not applying unsafe configuration change: %v. Use 'oc edit network.operator.openshift.io cluster' to undo the change.", err))
+			r.recordConfigRejected(operConfig, fmt.Sprintf("unsafe configuration change: %v", err))
 			return reconcile.Result{}, err
 		}
 	}
+	r.recordConfigAccepted(operConfig)
+
+	// Nodes keep the pod subnet they have when the hostPrefix changes
+	// A dry run of a configuration must not change the status of the applied one
+	if !isDryRun(operConfig) {
+		r.status.SetStaleNodeSubnets(network.NodesWithStaleSubnets(&newOperConfig.Spec, infraStatus))
+	}
 
 	// Bootstrap any resources
 	bootstrapResult, err := network.Bootstrap(newOperConfig, r.client)
@@ -325,7 +430,8 @@ func (r *ReconcileOperConfig) Reconcile(ctx context.Context, request reconcile.R
 		return reconcile.Result{}, err
 	}
 
-	if !reflect.DeepEqual(operConfig, newOperConfig) {
+	// In dry-run mode, the filled-in defaults are only used to render
+	if !isDryRun(operConfig) && !reflect.DeepEqual(operConfig, newOperConfig) {
 		if err := r.UpdateOperConfig(ctx, newOperConfig); err != nil {
 			log.Printf("Failed to update the operator configuration: %v", err)
 			// not set degraded if the err is a version conflict, but return a reconcile err for retry.
@@ -337,20 +443,98 @@ func (r *ReconcileOperConfig) Reconcile(ctx context.Context, request reconcile.R
 		}
 	}
 
-	updateIPsecMetric(&newOperConfig.Spec)
+	if !isDryRun(operConfig) && !r.status.MetricsDisabled() {
+		updateIPsecMetric(&newOperConfig.Spec)
+	}
 	// once updated, use the new config
 	operConfig = newOperConfig
 
+	// If the rollout of the desired configuration failed, render the last
+	// known-good configuration instead.
+	rollback, err := getRollbackState(ctx, r.client.Default().CRClient())
+	if err != nil {
+		log.Printf("Failed to retrieve the last known-good configuration: %v", err)
+		r.status.SetDegraded(statusmanager.OperatorConfig, "RollbackError",
+			fmt.Sprintf("Internal error while retrieving the last known-good configuration: %v", err))
+		return reconcile.Result{}, err
+	}
+	rolledBack := false
+	if !isDryRun(operConfig) {
+		knownGood, err := r.rollbackSpec(ctx, operConfig, prev, rollback, r.status.HungRollouts())
+		if err != nil {
+			log.Printf("Failed to roll back the operator configuration: %v", err)
+			r.status.SetDegraded(statusmanager.OperatorConfig, "RollbackError",
+				fmt.Sprintf("Internal error while rolling back to the last known-good configuration: %v", err))
+			return reconcile.Result{}, err
+		}
+		if knownGood != nil {
+			rolledBackConfig := operConfig.DeepCopy()
+			rolledBackConfig.Spec = *knownGood.DeepCopy()
+			bootstrapResult, err = network.Bootstrap(rolledBackConfig, r.client)
+			if err != nil {
+				log.Printf("Failed to reconcile platform networking resources: %v", err)
+				r.status.SetDegraded(statusmanager.OperatorConfig, "BootstrapError",
+					fmt.Sprintf("Internal error while reconciling platform networking resources: %v", err))
+				return reconcile.Result{}, err
+			}
+			operConfig = rolledBackConfig
+			rolledBack = true
+		}
+		r.setRollbackStatus(rollback, rolledBack)
+	}
+
+	// The service network migration is on hold while rolled back
+	if !rolledBack {
+		if err := r.syncServiceNetworkMigration(ctx, serviceNetworkMigration, operConfig, bootstrapResult); err != nil {
+			log.Printf("Failed to migrate the service network: %v", err)
+			r.status.SetDegraded(statusmanager.OperatorConfig, "ServiceNetworkMigrationError",
+				fmt.Sprintf("Internal error while migrating the service network: %v", err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	record.SpecHash = contentHash(operConfig.Spec)
+	record.RolledBack = rolledBack
+
+	// The supported overrides of the rendered operands are applied by Render
+	operandOverrides, err := getOperandOverrides(ctx, r.client.Default().CRClient())
+	if err != nil {
+		log.Printf("Failed to retrieve the operand overrides: %v", err)
+		return reconcile.Result{}, err
+	}
+	if operandOverrides != nil {
+		bootstrapResult.OperandOverrides.Data = operandOverrides.Data
+	}
+
 	// Generate the objects.
 	// Note that Render might have side effects in the passed in operConfig that
 	// will be reflected later on in the updated status.
+	renderStart := time.Now()
 	objs, progressing, err := network.Render(&operConfig.Spec, &clusterConfig.Spec, ManifestPath, r.client, r.featureGates, bootstrapResult)
+	var operandOverridesErr *network.OperandOverridesError
+	if errors.As(err, &operandOverridesErr) {
+		// Invalid overrides don't block the reconcile: the operands are
+		// rendered without them until the ConfigMap is fixed
+		log.Printf("Not applying the operand overrides: %v", err)
+		if !isDryRun(operConfig) {
+			r.status.SetDegraded(statusmanager.OperandOverridesConfig, "InvalidOperandOverrides",
+				fmt.Sprintf("Not applying the operand overrides: %v. Use 'oc -n %s edit configmap %s' to fix.",
+					operandOverridesErr.Err, names.APPLIED_NAMESPACE, names.OPERAND_OVERRIDES_CONFIGMAP))
+		}
+		bootstrapResult.OperandOverrides.Data = nil
+		objs, progressing, err = network.Render(&operConfig.Spec, &clusterConfig.Spec, ManifestPath, r.client, r.featureGates, bootstrapResult)
+	} else if err == nil && !isDryRun(operConfig) {
+		r.status.SetNotDegraded(statusmanager.OperandOverridesConfig)
+	}
 	if err != nil {
 		log.Printf("Failed to render: %v", err)
 		r.status.SetDegraded(statusmanager.OperatorConfig, "RenderError",
 			fmt.Sprintf("Internal error while rendering operator configuration: %v", err))
 		return reconcile.Result{}, err
 	}
+	if !r.status.MetricsDisabled() {
+		observeRenderDuration(string(operConfig.Spec.DefaultNetwork.Type), renderStart)
+	}
 
 	if progressing {
 		r.status.SetProgressing(statusmanager.OperatorRender, "RenderProgressing",
@@ -368,6 +552,7 @@ func (r *ReconcileOperConfig) Reconcile(ctx context.Context, request reconcile.R
 		return reconcile.Result{}, err
 	}
 	objs = append([]*uns.Unstructured{app}, objs...)
+	record.RenderHash = contentHash(objs)
 
 	relatedObjects := []configv1.ObjectReference{}
 	relatedClusterObjects := []hypershift.RelatedObject{}
@@ -393,6 +578,14 @@ func (r *ReconcileOperConfig) Reconcile(ctx context.Context, request reconcile.R
 				obj.SetLabels(l)
 			}
 		}
+		// Label all objects as applied by the operator, so that they can be pruned.
+		l := obj.GetLabels()
+		if l == nil {
+			l = map[string]string{}
+		}
+		l[names.AppliedByLabel] = names.AppliedByLabelValue
+		obj.SetLabels(l)
+
 		restMapping, err := r.mapper.RESTMapping(obj.GroupVersionKind().GroupKind())
 		if err != nil {
 			log.Printf("Failed to get REST mapping for storing related object: %v", err)
@@ -429,6 +622,31 @@ func (r *ReconcileOperConfig) Reconcile(ctx context.Context, request reconcile.R
 		}
 	}
 
+	// In dry-run mode, only report what would change. Nothing else may be
+	// updated, including the related objects, since that deletes objects
+	// that are no longer rendered.
+	if isDryRun(operConfig) {
+		record.DryRun = true
+		report, err := r.dryRunObjects(ctx, operConfig, objs)
+		if err != nil {
+			log.Printf("Failed to dry-run: %v", err)
+			r.status.SetDegraded(statusmanager.OperatorConfig, "DryRunError",
+				fmt.Sprintf("Internal error while dry-running operator configuration: %v", err))
+			return reconcile.Result{}, err
+		}
+		log.Printf("Operator configuration has the %s annotation set, not applying: %d of %d rendered objects would change. See ConfigMap %s/%s for details.",
+			names.DryRunAnnotation, len(report.Changes), report.Rendered, names.APPLIED_NAMESPACE, names.DRY_RUN_REPORT_CONFIGMAP)
+		r.status.SetNotDegraded(statusmanager.OperatorConfig)
+		return reconcile.Result{RequeueAfter: ResyncPeriod}, nil
+	}
+
+	if err := network.RegisterNetworkingConsolePlugin(bootstrapResult, r.client); err != nil {
+		log.Printf("Failed to register the networking console plugin: %v", err)
+		r.status.SetDegraded(statusmanager.OperatorConfig, "ConsolePluginError",
+			fmt.Sprintf("Internal error while registering the networking console plugin: %v", err))
+		return reconcile.Result{}, err
+	}
+
 	relatedObjects = append(relatedObjects, configv1.ObjectReference{
 		Resource: "namespaces",
 		Name:     names.APPLIED_NAMESPACE,
@@ -457,43 +675,94 @@ func (r *ReconcileOperConfig) Reconcile(ctx context.Context, request reconcile.R
 		return reconcile.Result{}, err
 	}
 
-	// Apply the objects to the cluster
+	// Apply the objects to the cluster, one phase at a time
+	phases, err := apply.SplitApplyPhases(objs)
+	if err != nil {
+		log.Printf("Failed to order rendered objects: %v", err)
+		r.status.SetDegraded(statusmanager.OperatorConfig, "RenderError",
+			fmt.Sprintf("Internal error while ordering rendered objects: %v", err))
+		return reconcile.Result{}, err
+	}
 	setDegraded := false
 	var degradedErr error
-	for _, obj := range objs {
-		// TODO: OwnerRef for non default clusters. For HyperShift this should probably be HostedControlPlane CR
-		if apply.GetClusterName(obj) == "" {
-			// Mark the object to be GC'd if the owner is deleted.
-			if err := controllerutil.SetControllerReference(operConfig, obj, r.client.ClientFor(apply.GetClusterName(obj)).Scheme()); err != nil {
-				err = errors.Wrapf(err, "could not set reference for (%s) %s/%s", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
-				log.Println(err)
-				r.status.SetDegraded(statusmanager.OperatorConfig, "InternalError",
-					fmt.Sprintf("Internal error while updating operator configuration: %v", err))
-				return reconcile.Result{}, err
+	applied, failed := 0, 0
+	waitingFor := ""
+	changedRollouts := []string{}
+	for i, phase := range phases {
+		for _, obj := range phase {
+			// TODO: OwnerRef for non default clusters. For HyperShift this should probably be HostedControlPlane CR
+			if apply.GetClusterName(obj) == "" {
+				// Mark the object to be GC'd if the owner is deleted.
+				if err := controllerutil.SetControllerReference(operConfig, obj, r.client.ClientFor(apply.GetClusterName(obj)).Scheme()); err != nil {
+					err = errors.Wrapf(err, "could not set reference for (%s) %s/%s", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+					log.Println(err)
+					r.status.SetDegraded(statusmanager.OperatorConfig, "InternalError",
+						fmt.Sprintf("Internal error while updating operator configuration: %v", err))
+					return reconcile.Result{}, err
+				}
 			}
-		}
-
-		// Open question: should an error here indicate we will never retry?
-		if err := apply.ApplyObject(ctx, r.client, obj, ControllerName); err != nil {
-			err = errors.Wrapf(err, "could not apply (%s) %s/%s", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
 
-			// If error comes from nonexistent namespace print out a help message.
-			if obj.GroupVersionKind().Kind == "NetworkAttachmentDefinition" && strings.Contains(err.Error(), "namespaces") {
-				err = errors.Wrapf(err, "could not apply (%s) %s/%s; Namespace error for networkattachment definition, consider possible solutions: (1) Edit config files to include existing namespace (2) Create non-existent namespace (3) Delete erroneous network-attachment-definition", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
-			}
+			// Open question: should an error here indicate we will never retry?
+			changed, err := apply.ApplyObjectChanged(ctx, r.client, obj, ControllerName)
+			if err != nil {
+				err = errors.Wrapf(err, "could not apply (%s) %s/%s", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
 
-			log.Println(err)
+				// If error comes from nonexistent namespace print out a help message.
+				if obj.GroupVersionKind().Kind == "NetworkAttachmentDefinition" && strings.Contains(err.Error(), "namespaces") {
+					err = errors.Wrapf(err, "could not apply (%s) %s/%s; Namespace error for networkattachment definition, consider possible solutions: (1) Edit config files to include existing namespace (2) Create non-existent namespace (3) Delete erroneous network-attachment-definition", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+				}
 
-			// Ignore errors if we've asked to do so.
-			anno := obj.GetAnnotations()
-			if anno != nil {
-				if _, ok := anno[names.IgnoreObjectErrorAnnotation]; ok {
-					log.Println("Object has ignore-errors annotation set, continuing")
-					continue
+				log.Println(err)
+				failed++
+
+				// Ignore errors if we've asked to do so.
+				anno := obj.GetAnnotations()
+				if anno != nil {
+					if _, ok := anno[names.IgnoreObjectErrorAnnotation]; ok {
+						log.Println("Object has ignore-errors annotation set, continuing")
+						continue
+					}
+				}
+				setDegraded = true
+				degradedErr = err
+				continue
+			}
+			applied++
+			if changed {
+				record.addChangedObject(obj)
+				if isRollout(obj) {
+					changedRollouts = append(changedRollouts, rolloutKey(obj.GetKind(), statusmanager.NewClusteredName(obj)))
 				}
 			}
+		}
+
+		// Objects of later phases depend on the objects of this one, so don't
+		// apply them until this phase is ready.
+		if setDegraded || i == len(phases)-1 {
+			break
+		}
+		if waitingFor, err = r.waitForApplyPhase(ctx, phase); err != nil {
 			setDegraded = true
 			degradedErr = err
+			break
+		}
+		if waitingFor != "" {
+			break
+		}
+	}
+	if !r.status.MetricsDisabled() {
+		setAppliedObjectsMetric(applied, failed)
+	}
+	record.Applied, record.Failed = applied, failed
+
+	// Remember which rollouts the applied configuration changed, so that only
+	// their hangs roll it back.
+	if !rolledBack {
+		if err := r.recordPending(ctx, operConfig, rollback, changedRollouts, record.Timestamp.Time); err != nil {
+			log.Printf("Failed to record the pending configuration: %v", err)
+			r.status.SetDegraded(statusmanager.OperatorConfig, "RollbackError",
+				fmt.Sprintf("Internal error while recording the pending configuration: %v", err))
+			return reconcile.Result{}, err
 		}
 	}
 
@@ -502,6 +771,34 @@ func (r *ReconcileOperConfig) Reconcile(ctx context.Context, request reconcile.R
 			fmt.Sprintf("Error while updating operator configuration: %v", degradedErr))
 		return reconcile.Result{}, degradedErr
 	}
+	if waitingFor != "" {
+		log.Printf("Not applying the remaining objects yet: %s", waitingFor)
+		r.status.SetProgressing(statusmanager.OperatorConfig, "WaitingForApplyPhase",
+			fmt.Sprintf("Waiting to apply the remaining objects: %s", waitingFor))
+		if waited := r.applyPhaseWaitTime(operConfig.Generation, time.Now()); waited > applyPhaseDegradedTimeout {
+			r.status.SetDegraded(statusmanager.OperatorConfig, "ApplyPhaseTimeout",
+				fmt.Sprintf("The remaining objects have not been applied for %v: %s", waited.Round(time.Second), waitingFor))
+		}
+		return reconcile.Result{RequeueAfter: applyPhaseRequeuePeriod}, nil
+	}
+	r.applyPhaseWaitStart = time.Time{}
+	r.status.UnsetProgressing(statusmanager.OperatorConfig)
+
+	// Delete whatever we applied before but no longer render
+	if err := r.pruneObjects(ctx, operConfig, objs); err != nil {
+		log.Printf("Failed to prune objects: %v", err)
+		r.status.SetDegraded(statusmanager.OperatorConfig, "PruneError",
+			fmt.Sprintf("Internal error while deleting objects that are no longer rendered: %v", err))
+		return reconcile.Result{}, err
+	}
+
+	// Report the status of the third-party CNI of the external network mode
+	if err := r.labelExternalNetworkDaemonSets(ctx, bootstrapResult); err != nil {
+		log.Printf("Failed to watch the external network: %v", err)
+		r.status.SetDegraded(statusmanager.OperatorConfig, "ExternalNetworkError",
+			fmt.Sprintf("Error while watching the external network: %v", err))
+		return reconcile.Result{}, err
+	}
 
 	if operConfig.Spec.Migration != nil && operConfig.Spec.Migration.NetworkType != "" {
 		if !(operConfig.Spec.Migration.NetworkType == string(operv1.NetworkTypeOpenShiftSDN) || operConfig.Spec.Migration.NetworkType == string(operv1.NetworkTypeOVNKubernetes)) {
@@ -553,6 +850,17 @@ func (r *ReconcileOperConfig) Reconcile(ctx context.Context, request reconcile.R
 		}
 	}
 
+	// Record the applied configuration once it is completely rolled out, so
+	// that we can go back to it if a later change fails.
+	if !rolledBack {
+		if err := r.recordKnownGood(ctx, operConfig, rollback); err != nil {
+			log.Printf("Failed to record the known-good configuration: %v", err)
+			r.status.SetDegraded(statusmanager.OperatorConfig, "RollbackError",
+				fmt.Sprintf("Internal error while recording the known-good configuration: %v", err))
+			return reconcile.Result{}, err
+		}
+	}
+
 	r.status.SetNotDegraded(statusmanager.OperatorConfig)
 
 	// All was successful. Request that this be re-triggered after ResyncPeriod,
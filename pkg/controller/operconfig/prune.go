@@ -0,0 +1,53 @@
+package operconfig
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-network-operator/pkg/apply"
+
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// pruneObjects deletes the objects that were applied by a previous reconcile
+// but are no longer rendered, and records what was applied this time in the
+// inventory ConfigMap.
+// Objects that could not be deleted are kept in the inventory, so that
+// deleting them is retried on the next reconcile.
+func (r *ReconcileOperConfig) pruneObjects(ctx context.Context, operConfig *operv1.Network, objs []*uns.Unstructured) error {
+	previous, err := apply.GetInventory(ctx, r.client.Default().CRClient())
+	if err != nil {
+		return fmt.Errorf("could not retrieve inventory of applied objects: %w", err)
+	}
+
+	current := make([]apply.InventoryEntry, 0, len(objs))
+	for _, obj := range objs {
+		current = append(current, apply.NewInventoryEntry(obj))
+	}
+
+	// The very first time, there is nothing to compare against: objects
+	// from older releases are cleaned up by the status manager.
+	inventory := current
+	if previous != nil {
+		stale := apply.StaleInventoryEntries(previous, current)
+		if len(stale) > 0 {
+			log.Printf("Pruning %d objects that are no longer rendered", len(stale))
+			inventory = append(inventory, apply.PruneObjects(ctx, r.client, stale)...)
+		}
+	}
+
+	cm, err := apply.InventoryConfigMap(inventory)
+	if err != nil {
+		return err
+	}
+	if err := controllerutil.SetControllerReference(operConfig, cm, r.client.Default().Scheme()); err != nil {
+		return err
+	}
+	if err := apply.ApplyObject(ctx, r.client, cm, ControllerName); err != nil {
+		return fmt.Errorf("could not apply inventory of applied objects: %w", err)
+	}
+	return nil
+}
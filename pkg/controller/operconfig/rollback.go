@@ -0,0 +1,273 @@
+package operconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-network-operator/pkg/apply"
+	"github.com/openshift/cluster-network-operator/pkg/controller/statusmanager"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// knownGoodSoakTime is how long a configuration must have been applied before
+// it can be considered known-good, so that the status manager has seen the
+// rollout it triggered.
+var knownGoodSoakTime = 1 * time.Minute
+
+// rollbackState is the content of the known-good ConfigMap.
+type rollbackState struct {
+	// KnownGood is the last configuration that was completely rolled out.
+	KnownGood *operv1.NetworkSpec
+	// Pending is the configuration applied since, and PendingSince when it was first applied.
+	Pending      *operv1.NetworkSpec
+	PendingSince time.Time
+	// PendingObjects are the DaemonSets, Deployments and StatefulSets that
+	// applying the pending configuration changed.
+	PendingObjects []string
+	// Failed is the configuration whose rollout failed and was rolled back, and
+	// Reason why its rollout failed.
+	Failed *operv1.NetworkSpec
+	Reason string
+}
+
+// isRollbackEnabled returns true if the operator configuration opted in to
+// automatic rollbacks.
+func isRollbackEnabled(operConfig *operv1.Network) bool {
+	return operConfig.GetAnnotations()[names.RollbackOnFailureAnnotation] == "true"
+}
+
+// getRollbackState retrieves the known-good ConfigMap. Returns an empty state
+// with no error if it doesn't exist.
+func getRollbackState(ctx context.Context, client crclient.Client) (*rollbackState, error) {
+	state := &rollbackState{}
+	cm := &corev1.ConfigMap{}
+	err := client.Get(ctx, types.NamespacedName{Namespace: names.APPLIED_NAMESPACE, Name: names.KNOWN_GOOD_CONFIGMAP}, cm)
+	if err != nil && apierrors.IsNotFound(err) {
+		return state, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for key, spec := range map[string]**operv1.NetworkSpec{
+		"known-good": &state.KnownGood,
+		"pending":    &state.Pending,
+		"failed":     &state.Failed,
+	} {
+		if cm.Data[key] == "" {
+			continue
+		}
+		*spec = &operv1.NetworkSpec{}
+		if err := json.Unmarshal([]byte(cm.Data[key]), *spec); err != nil {
+			return nil, fmt.Errorf("could not parse %q in %s: %w", key, names.KNOWN_GOOD_CONFIGMAP, err)
+		}
+	}
+	if cm.Data["pending-since"] != "" {
+		if state.PendingSince, err = time.Parse(time.RFC3339, cm.Data["pending-since"]); err != nil {
+			return nil, fmt.Errorf("could not parse \"pending-since\" in %s: %w", names.KNOWN_GOOD_CONFIGMAP, err)
+		}
+	}
+	if cm.Data["pending-objects"] != "" {
+		if err := json.Unmarshal([]byte(cm.Data["pending-objects"]), &state.PendingObjects); err != nil {
+			return nil, fmt.Errorf("could not parse \"pending-objects\" in %s: %w", names.KNOWN_GOOD_CONFIGMAP, err)
+		}
+	}
+	state.Reason = cm.Data["reason"]
+	return state, nil
+}
+
+// rollbackStateConfigMap renders the ConfigMap in which we store the rollback state.
+func rollbackStateConfigMap(state *rollbackState) (*corev1.ConfigMap, error) {
+	data := map[string]string{}
+	for key, spec := range map[string]*operv1.NetworkSpec{
+		"known-good": state.KnownGood,
+		"pending":    state.Pending,
+		"failed":     state.Failed,
+	} {
+		if spec == nil {
+			continue
+		}
+		buf, err := json.Marshal(spec)
+		if err != nil {
+			return nil, err
+		}
+		data[key] = string(buf)
+	}
+	if state.Pending != nil {
+		data["pending-since"] = state.PendingSince.UTC().Format(time.RFC3339)
+		if len(state.PendingObjects) > 0 {
+			buf, err := json.Marshal(state.PendingObjects)
+			if err != nil {
+				return nil, err
+			}
+			data["pending-objects"] = string(buf)
+		}
+	}
+	if state.Failed != nil {
+		data["reason"] = state.Reason
+	}
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: names.APPLIED_NAMESPACE,
+			Name:      names.KNOWN_GOOD_CONFIGMAP,
+		},
+		Data: data,
+	}, nil
+}
+
+func (r *ReconcileOperConfig) applyRollbackState(ctx context.Context, operConfig *operv1.Network, state *rollbackState) error {
+	cm, err := rollbackStateConfigMap(state)
+	if err != nil {
+		return err
+	}
+	if err := controllerutil.SetControllerReference(operConfig, cm, r.client.Default().Scheme()); err != nil {
+		return err
+	}
+	if err := apply.ApplyObject(ctx, r.client, cm, ControllerName); err != nil {
+		return fmt.Errorf("could not apply %s: %w", names.KNOWN_GOOD_CONFIGMAP, err)
+	}
+	return nil
+}
+
+// rolloutKey identifies a DaemonSet, Deployment or StatefulSet in the rollback state.
+func rolloutKey(kind string, name statusmanager.ClusteredName) string {
+	return kind + " " + name.String()
+}
+
+// isRollout returns true if obj is a DaemonSet, Deployment or StatefulSet,
+// whose rollout the status manager watches.
+func isRollout(obj *uns.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	return gvk.Group == "apps" && (gvk.Kind == "DaemonSet" || gvk.Kind == "Deployment" || gvk.Kind == "StatefulSet")
+}
+
+// rollbackSpec returns the last known-good configuration if it should be
+// rendered instead of the desired one, or nil otherwise.
+// That's the case when automatic rollbacks are enabled and the rollout of the
+// desired configuration, which was already applied (prev), is hung: one of
+// the hung rollouts is of an object that the desired configuration changed,
+// and it hung after the configuration was applied. The failed configuration
+// keeps being rolled back until it is changed.
+func (r *ReconcileOperConfig) rollbackSpec(ctx context.Context, operConfig *operv1.Network, prev *operv1.NetworkSpec, state *rollbackState, hung []statusmanager.HungRollout) (*operv1.NetworkSpec, error) {
+	desired := &operConfig.Spec
+	if !isRollbackEnabled(operConfig) || state.KnownGood == nil || reflect.DeepEqual(state.KnownGood, desired) {
+		return nil, nil
+	}
+
+	if state.Failed != nil && reflect.DeepEqual(state.Failed, desired) {
+		return state.KnownGood, nil
+	}
+
+	// Only roll back a configuration that was already applied
+	if prev == nil || !reflect.DeepEqual(prev, desired) || state.Pending == nil || !reflect.DeepEqual(state.Pending, desired) {
+		return nil, nil
+	}
+	// and whose rollout is the one that is hung, not an unrelated one or one
+	// that was already hung before.
+	reasons := []string{}
+	for _, h := range hung {
+		if slices.Contains(state.PendingObjects, rolloutKey(h.Kind, h.ClusteredName)) && !h.Since.Before(state.PendingSince) {
+			reasons = append(reasons, h.Reason)
+		}
+	}
+	if len(reasons) == 0 {
+		return nil, nil
+	}
+	reason := strings.Join(reasons, "\n")
+
+	log.Printf("Rollout of the operator configuration is hung, rolling back to the last known-good configuration: %s", reason)
+	state.Failed = desired.DeepCopy()
+	state.Reason = reason
+	if err := r.applyRollbackState(ctx, operConfig, state); err != nil {
+		return nil, err
+	}
+	return state.KnownGood, nil
+}
+
+// recordPending records the applied configuration as pending when it is
+// first applied (at since), along with the rollouts it changed (changed).
+func (r *ReconcileOperConfig) recordPending(ctx context.Context, operConfig *operv1.Network, state *rollbackState, changed []string, since time.Time) error {
+	applied := &operConfig.Spec
+	if reflect.DeepEqual(state.KnownGood, applied) {
+		return nil
+	}
+
+	update := false
+	if state.Pending == nil || !reflect.DeepEqual(state.Pending, applied) {
+		state.Pending = applied.DeepCopy()
+		state.PendingSince = since
+		state.PendingObjects = nil
+		update = true
+	}
+	for _, key := range changed {
+		if !slices.Contains(state.PendingObjects, key) {
+			state.PendingObjects = append(state.PendingObjects, key)
+			update = true
+		}
+	}
+	if !update {
+		return nil
+	}
+	return r.applyRollbackState(ctx, operConfig, state)
+}
+
+// recordKnownGood records the applied configuration as known-good once it has
+// completely rolled out, or as pending until then.
+func (r *ReconcileOperConfig) recordKnownGood(ctx context.Context, operConfig *operv1.Network, state *rollbackState) error {
+	applied := &operConfig.Spec
+	if reflect.DeepEqual(state.KnownGood, applied) && state.Pending == nil && state.Failed == nil {
+		return nil
+	}
+
+	switch {
+	case reflect.DeepEqual(state.KnownGood, applied):
+		// We're back to a known-good configuration
+		state.Pending = nil
+		state.PendingObjects = nil
+		state.Failed = nil
+	case state.Pending == nil || !reflect.DeepEqual(state.Pending, applied):
+		state.Pending = applied.DeepCopy()
+		state.PendingSince = time.Now()
+		state.PendingObjects = nil
+	case time.Since(state.PendingSince) > knownGoodSoakTime && r.status.IsRolloutComplete():
+		log.Printf("Operator configuration is completely rolled out, recording it as known-good")
+		state.KnownGood = applied.DeepCopy()
+		state.Pending = nil
+		state.PendingObjects = nil
+		state.Failed = nil
+	default:
+		return nil
+	}
+	return r.applyRollbackState(ctx, operConfig, state)
+}
+
+// setRollbackStatus reports whether the desired configuration was rolled back.
+func (r *ReconcileOperConfig) setRollbackStatus(state *rollbackState, rolledBack bool) {
+	if !rolledBack {
+		r.status.SetNotDegraded(statusmanager.RolledBack)
+		return
+	}
+	r.status.SetDegraded(statusmanager.RolledBack, "RolledBack",
+		fmt.Sprintf("The rollout of the operator configuration failed (%s), so the last known-good configuration was restored. "+
+			"Use 'oc edit network.operator.openshift.io cluster' to fix the configuration; the failed configuration is in ConfigMap %s/%s.",
+			state.Reason, names.APPLIED_NAMESPACE, names.KNOWN_GOOD_CONFIGMAP))
+}
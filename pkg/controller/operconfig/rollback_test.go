@@ -0,0 +1,258 @@
+package operconfig
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
+	cnofake "github.com/openshift/cluster-network-operator/pkg/client/fake"
+	"github.com/openshift/cluster-network-operator/pkg/controller/statusmanager"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRollbackStateRoundTrip(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	state := &rollbackState{
+		KnownGood:      &operv1.NetworkSpec{ServiceNetwork: []string{"172.30.0.0/16"}},
+		Pending:        &operv1.NetworkSpec{ServiceNetwork: []string{"172.31.0.0/16"}},
+		PendingSince:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		PendingObjects: []string{"DaemonSet /openshift-ovn-kubernetes/ovnkube-node"},
+		Failed:         &operv1.NetworkSpec{ServiceNetwork: []string{"172.32.0.0/16"}},
+		Reason:         "DaemonSet \"openshift-ovn-kubernetes/ovnkube-node\" rollout is not making progress",
+	}
+	cm, err := rollbackStateConfigMap(state)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cm.Namespace).To(Equal(names.APPLIED_NAMESPACE))
+	g.Expect(cm.Name).To(Equal(names.KNOWN_GOOD_CONFIGMAP))
+
+	client := fake.NewClientBuilder().WithObjects(cm).Build()
+	actual, err := getRollbackState(context.Background(), client)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(actual).To(Equal(state))
+
+	// A missing ConfigMap is an empty state
+	actual, err = getRollbackState(context.Background(), fake.NewClientBuilder().Build())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(actual).To(Equal(&rollbackState{}))
+}
+
+func TestRollbackSpec(t *testing.T) {
+	knownGood := &operv1.NetworkSpec{ServiceNetwork: []string{"172.30.0.0/16"}}
+	desired := &operv1.NetworkSpec{ServiceNetwork: []string{"172.30.0.0/16"}, UseMultiNetworkPolicy: new(bool)}
+
+	testCases := []struct {
+		name     string
+		enabled  bool
+		prev     *operv1.NetworkSpec
+		state    *rollbackState
+		expected *operv1.NetworkSpec
+	}{
+		{
+			name:    "rollback disabled",
+			enabled: false,
+			prev:    desired,
+			state:   &rollbackState{KnownGood: knownGood, Failed: desired},
+		},
+		{
+			name:    "no known-good configuration",
+			enabled: true,
+			prev:    desired,
+			state:   &rollbackState{Failed: desired},
+		},
+		{
+			name:    "desired configuration is known-good",
+			enabled: true,
+			prev:    desired,
+			state:   &rollbackState{KnownGood: desired, Failed: desired},
+		},
+		{
+			name:     "desired configuration already failed",
+			enabled:  true,
+			prev:     knownGood,
+			state:    &rollbackState{KnownGood: knownGood, Failed: desired},
+			expected: knownGood,
+		},
+		{
+			name:    "desired configuration not applied yet",
+			enabled: true,
+			prev:    knownGood,
+			state:   &rollbackState{KnownGood: knownGood},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			operConfig := &operv1.Network{
+				ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG},
+				Spec:       *desired.DeepCopy(),
+			}
+			if tc.enabled {
+				operConfig.Annotations = map[string]string{names.RollbackOnFailureAnnotation: "true"}
+			}
+			r := &ReconcileOperConfig{}
+			actual, err := r.rollbackSpec(context.Background(), operConfig, tc.prev, tc.state, nil)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestRollbackSpecHung(t *testing.T) {
+	knownGood := &operv1.NetworkSpec{ServiceNetwork: []string{"172.30.0.0/16"}}
+	desired := &operv1.NetworkSpec{ServiceNetwork: []string{"172.30.0.0/16"}, UseMultiNetworkPolicy: new(bool)}
+	pendingSince := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	ovnkubeNode := statusmanager.ClusteredName{Namespace: "openshift-ovn-kubernetes", Name: "ovnkube-node"}
+	egressRouter := statusmanager.ClusteredName{Namespace: "default", Name: "egress-router"}
+
+	testCases := []struct {
+		name       string
+		pending    *operv1.NetworkSpec
+		hung       []statusmanager.HungRollout
+		rolledBack bool
+	}{
+		{
+			name:    "no hung rollout",
+			pending: desired,
+		},
+		{
+			name:    "unrelated rollout is hung",
+			pending: desired,
+			hung: []statusmanager.HungRollout{
+				{Kind: "Deployment", ClusteredName: egressRouter, Since: pendingSince.Add(time.Minute), Reason: "egress-router is hung"},
+			},
+		},
+		{
+			name:    "rollout was hung before the change",
+			pending: desired,
+			hung: []statusmanager.HungRollout{
+				{Kind: "DaemonSet", ClusteredName: ovnkubeNode, Since: pendingSince.Add(-time.Minute), Reason: "ovnkube-node is hung"},
+			},
+		},
+		{
+			name:    "hung rollout of another configuration",
+			pending: knownGood,
+			hung: []statusmanager.HungRollout{
+				{Kind: "DaemonSet", ClusteredName: ovnkubeNode, Since: pendingSince.Add(time.Minute), Reason: "ovnkube-node is hung"},
+			},
+		},
+		{
+			name:    "rollout of the changed object is hung",
+			pending: desired,
+			hung: []statusmanager.HungRollout{
+				{Kind: "Deployment", ClusteredName: egressRouter, Since: pendingSince.Add(time.Minute), Reason: "egress-router is hung"},
+				{Kind: "DaemonSet", ClusteredName: ovnkubeNode, Since: pendingSince.Add(time.Minute), Reason: "ovnkube-node is hung"},
+			},
+			rolledBack: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			operConfig := &operv1.Network{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        names.OPERATOR_CONFIG,
+					Annotations: map[string]string{names.RollbackOnFailureAnnotation: "true"},
+				},
+				Spec: *desired.DeepCopy(),
+			}
+			state := &rollbackState{
+				KnownGood:      knownGood,
+				Pending:        tc.pending,
+				PendingSince:   pendingSince,
+				PendingObjects: []string{rolloutKey("DaemonSet", ovnkubeNode)},
+			}
+			client := cnofake.NewFakeClient()
+			var persisted *rollbackState
+			recordAppliedRollbackState(g, client, &persisted)
+			r := &ReconcileOperConfig{client: client}
+			actual, err := r.rollbackSpec(context.Background(), operConfig, desired, state, tc.hung)
+			g.Expect(err).NotTo(HaveOccurred())
+			if !tc.rolledBack {
+				g.Expect(actual).To(BeNil())
+				g.Expect(state.Failed).To(BeNil())
+				return
+			}
+
+			g.Expect(actual).To(Equal(knownGood))
+			g.Expect(state.Failed).To(Equal(desired))
+			g.Expect(state.Reason).To(Equal("ovnkube-node is hung"))
+
+			// the failed configuration is recorded
+			g.Expect(persisted.Failed).To(Equal(desired))
+			g.Expect(persisted.Reason).To(Equal("ovnkube-node is hung"))
+		})
+	}
+}
+
+func TestRecordPending(t *testing.T) {
+	g := NewGomegaWithT(t)
+	knownGood := &operv1.NetworkSpec{ServiceNetwork: []string{"172.30.0.0/16"}}
+	desired := &operv1.NetworkSpec{ServiceNetwork: []string{"172.30.0.0/16"}, UseMultiNetworkPolicy: new(bool)}
+	operConfig := &operv1.Network{
+		ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG},
+		Spec:       *desired.DeepCopy(),
+	}
+	client := cnofake.NewFakeClient()
+	var persisted *rollbackState
+	recordAppliedRollbackState(g, client, &persisted)
+	r := &ReconcileOperConfig{client: client}
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// the known-good configuration is never pending
+	state := &rollbackState{KnownGood: desired}
+	g.Expect(r.recordPending(context.Background(), operConfig, state, []string{"DaemonSet /a/b"}, start)).To(Succeed())
+	g.Expect(state.Pending).To(BeNil())
+
+	// a new configuration is pending since it was first applied
+	state = &rollbackState{KnownGood: knownGood}
+	g.Expect(r.recordPending(context.Background(), operConfig, state, []string{"DaemonSet /a/b"}, start)).To(Succeed())
+	g.Expect(state.Pending).To(Equal(desired))
+	g.Expect(state.PendingSince).To(Equal(start))
+	g.Expect(state.PendingObjects).To(Equal([]string{"DaemonSet /a/b"}))
+
+	// and accumulates the rollouts changed by the following applies
+	g.Expect(r.recordPending(context.Background(), operConfig, state, []string{"Deployment /a/c", "DaemonSet /a/b"}, start.Add(time.Minute))).To(Succeed())
+	g.Expect(state.PendingSince).To(Equal(start))
+	g.Expect(state.PendingObjects).To(Equal([]string{"DaemonSet /a/b", "Deployment /a/c"}))
+
+	g.Expect(persisted.PendingObjects).To(Equal(state.PendingObjects))
+
+	// until the configuration changes again
+	operConfig.Spec = *knownGood.DeepCopy()
+	operConfig.Spec.ServiceNetwork = []string{"172.31.0.0/16"}
+	g.Expect(r.recordPending(context.Background(), operConfig, state, nil, start.Add(2*time.Minute))).To(Succeed())
+	g.Expect(state.PendingSince).To(Equal(start.Add(2 * time.Minute)))
+	g.Expect(state.PendingObjects).To(BeEmpty())
+}
+
+// recordAppliedRollbackState makes the fake client record the rollback state
+// it applies in state, since it can't apply objects.
+func recordAppliedRollbackState(g *WithT, client cnoclient.Client, state **rollbackState) {
+	client.Default().Dynamic().(*fakedynamic.FakeDynamicClient).PrependReactor("patch", "configmaps",
+		func(action clienttesting.Action) (bool, runtime.Object, error) {
+			obj := &uns.Unstructured{}
+			g.Expect(obj.UnmarshalJSON(action.(clienttesting.PatchAction).GetPatch())).To(Succeed())
+			cm := &corev1.ConfigMap{}
+			g.Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, cm)).To(Succeed())
+			g.Expect(cm.Name).To(Equal(names.KNOWN_GOOD_CONFIGMAP))
+			var err error
+			*state, err = getRollbackState(context.Background(), fake.NewClientBuilder().WithObjects(cm).Build())
+			g.Expect(err).NotTo(HaveOccurred())
+			return true, obj, nil
+		})
+}
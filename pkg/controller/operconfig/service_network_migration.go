@@ -0,0 +1,760 @@
+package operconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"reflect"
+	"slices"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	mcfgv1 "github.com/openshift/api/machineconfiguration/v1"
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	ctrlcommon "github.com/openshift/machine-config-operator/pkg/controller/common"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilnet "k8s.io/utils/net"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+	"github.com/openshift/cluster-network-operator/pkg/network"
+)
+
+// maxListedServices is the number of Services listed in the message of a
+// service network migration condition.
+const maxListedServices = 10
+
+// multiCIDRServiceAllocatorFeatureGate is the kube-apiserver feature that
+// serves the ServiceCIDR API. It is not an OpenShift feature gate, so it is
+// only enabled when the cluster's FeatureGate lists it.
+const multiCIDRServiceAllocatorFeatureGate configv1.FeatureGateName = "MultiCIDRServiceAllocator"
+
+// The Services whose cluster IP the apiserver and the cluster DNS operator
+// derive from the service network, and the Services the operator keeps for
+// their cluster IP in the original service network during the migration.
+const (
+	kubernetesServiceNamespace = "default"
+	kubernetesServiceName      = "kubernetes"
+	dnsServiceNamespace        = "openshift-dns"
+	dnsServiceName             = "dns-default"
+	originalServiceSuffix      = "-original"
+)
+
+// defaultServiceCIDRName is the ServiceCIDR that the apiserver creates for
+// its --service-cluster-ip-range.
+const defaultServiceCIDRName = "kubernetes"
+
+// kubeletConfigPath is the path of the kubelet configuration in the rendered
+// MachineConfigs.
+const kubeletConfigPath = "/etc/kubernetes/kubelet.conf"
+
+// errServiceNetworkMigrationAbandoned is returned when the service network
+// migration is no longer requested after the service network was switched to
+// the target: the Services of the original service network would be left
+// unreachable, so the migration must be completed.
+var errServiceNetworkMigrationAbandoned = errors.New("the service network migration cannot be abandoned")
+
+// serviceNetworkMigration is a migration of the service network, requested with
+// the names.ServiceNetworkMigrationAnnotation annotation on the operator
+// configuration.
+type serviceNetworkMigration struct {
+	original []string
+	target   []string
+}
+
+// parseServiceNetworks parses a comma-separated list of CIDRs.
+func parseServiceNetworks(value string) []string {
+	cidrs := []string{}
+	for _, cidr := range strings.Split(value, ",") {
+		if cidr = strings.TrimSpace(cidr); cidr != "" {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	return cidrs
+}
+
+// getServiceNetworkMigration returns the service network migration requested on
+// operConfig, or nil if there is none. The first time, the current service
+// network is recorded as the original in the
+// names.ServiceNetworkMigrationOriginalAnnotation annotation of operConfig, so
+// that the migration can go on once spec.serviceNetwork is the target.
+// Once the migration is no longer requested, the annotation is removed, unless
+// the service network was already switched to the target and the migration is
+// still in progress: errServiceNetworkMigrationAbandoned is returned then.
+// A migration is only started if the apiserver serves the ServiceCIDR API.
+func getServiceNetworkMigration(operConfig *operv1.Network, infraStatus *bootstrap.InfraStatus, featureGates featuregates.FeatureGate) (*serviceNetworkMigration, error) {
+	value, ok := operConfig.Annotations[names.ServiceNetworkMigrationAnnotation]
+	if !ok {
+		original, ok := operConfig.Annotations[names.ServiceNetworkMigrationOriginalAnnotation]
+		inProgress := v1helpers.IsOperatorConditionTrue(operConfig.Status.Conditions, names.ServiceNetworkMigrationInProgress)
+		if ok && inProgress && !reflect.DeepEqual(operConfig.Spec.ServiceNetwork, parseServiceNetworks(original)) {
+			return nil, fmt.Errorf("%w: the service network was already switched from %s to %s, restore the %s annotation to complete it",
+				errServiceNetworkMigrationAbandoned, original, strings.Join(operConfig.Spec.ServiceNetwork, ","), names.ServiceNetworkMigrationAnnotation)
+		}
+		delete(operConfig.Annotations, names.ServiceNetworkMigrationOriginalAnnotation)
+		return nil, nil
+	}
+	if _, ok := operConfig.Annotations[names.ServiceNetworkMigrationOriginalAnnotation]; !ok {
+		if !slices.Contains(featureGates.KnownFeatures(), multiCIDRServiceAllocatorFeatureGate) || !featureGates.Enabled(multiCIDRServiceAllocatorFeatureGate) {
+			return nil, fmt.Errorf("the %s feature gate must be enabled to migrate the service network", multiCIDRServiceAllocatorFeatureGate)
+		}
+		operConfig.Annotations[names.ServiceNetworkMigrationOriginalAnnotation] = strings.Join(operConfig.Spec.ServiceNetwork, ",")
+	}
+
+	m := &serviceNetworkMigration{
+		original: parseServiceNetworks(operConfig.Annotations[names.ServiceNetworkMigrationOriginalAnnotation]),
+		target:   parseServiceNetworks(value),
+	}
+	if err := network.ValidateServiceNetworkMigration(&operConfig.Spec, m.original, m.target, infraStatus); err != nil {
+		return nil, err
+	}
+	if !reflect.DeepEqual(operConfig.Spec.ServiceNetwork, m.original) && !reflect.DeepEqual(operConfig.Spec.ServiceNetwork, m.target) {
+		return nil, fmt.Errorf("serviceNetwork %s is neither the original service network %s nor the target service network %s",
+			strings.Join(operConfig.Spec.ServiceNetwork, ","), strings.Join(m.original, ","), strings.Join(m.target, ","))
+	}
+	return m, nil
+}
+
+// allowsChange returns true if the change of the service network from prev to
+// next is the switch from the original to the target service network.
+func (m *serviceNetworkMigration) allowsChange(prev, next *operv1.NetworkSpec) bool {
+	return m != nil && reflect.DeepEqual(prev.ServiceNetwork, m.original) && reflect.DeepEqual(next.ServiceNetwork, m.target)
+}
+
+// syncServiceNetworkMigration drives a service network migration, sets the
+// service network migration result of bootstrapResult so that the additional
+// service networks are rendered, and reports the progress in the status
+// conditions of the operator configuration. The migration goes through these
+// phases:
+//  1. serviceNetwork is the original: ovnkube is rolled out with the target as
+//     an additional service network, then a ServiceCIDR is created for each
+//     target CIDR so that new Services get a cluster IP in the target.
+//  2. the administrator recreates the Services. Once only the Services of the
+//     apiserver and of the cluster DNS, whose cluster IPs are derived from the
+//     service network, have a cluster IP in the original, the MachineConfigPools
+//     are paused and serviceNetwork of the cluster configuration is changed to
+//     the target.
+//  3. serviceNetwork is the target: ovnkube keeps the original as an additional
+//     service network until no Service has a cluster IP in it anymore. See
+//     syncOriginalRemoved.
+func (r *ReconcileOperConfig) syncServiceNetworkMigration(ctx context.Context, m *serviceNetworkMigration, operConfig *operv1.Network,
+	bootstrapResult *bootstrap.BootstrapResult) error {
+	if m == nil {
+		r.serviceNetworkMigrationDeleted = nil
+		// The migration is abandoned before the service network was switched,
+		// see getServiceNetworkMigration
+		if cond := v1helpers.FindOperatorCondition(operConfig.Status.Conditions, names.ServiceNetworkMigrationInProgress); cond != nil && cond.Status == operv1.ConditionTrue {
+			// Don't leave the MachineConfigPools paused if the migration is abandoned
+			if !isDryRun(operConfig) {
+				if err := r.setMachineConfigPoolsPaused(ctx, false); err != nil {
+					return err
+				}
+			}
+			r.status.SetServiceNetworkMigrationConditions([]operv1.OperatorCondition{{
+				Type:    names.ServiceNetworkMigrationInProgress,
+				Status:  operv1.ConditionFalse,
+				Reason:  "ServiceNetworkMigrationNotRequested",
+				Message: fmt.Sprintf("The %s annotation was removed", names.ServiceNetworkMigrationAnnotation),
+			}})
+		}
+		return nil
+	}
+
+	original, target := strings.Join(m.original, ","), strings.Join(m.target, ",")
+	inProgress := operv1.OperatorCondition{
+		Type:    names.ServiceNetworkMigrationInProgress,
+		Status:  operv1.ConditionTrue,
+		Reason:  "ServiceNetworkMigrationStarted",
+		Message: fmt.Sprintf("Migrating the service network from %s to %s", original, target),
+	}
+	targetAdded := operv1.OperatorCondition{
+		Type:   names.ServiceNetworkMigrationTargetAdded,
+		Status: operv1.ConditionTrue,
+		Reason: "AsExpected",
+	}
+	consumersMigrated := operv1.OperatorCondition{
+		Type:   names.ServiceNetworkMigrationConsumersMigrated,
+		Status: operv1.ConditionTrue,
+		Reason: "AsExpected",
+	}
+	originalRemoved := operv1.OperatorCondition{
+		Type:    names.ServiceNetworkMigrationOriginalRemoved,
+		Status:  operv1.ConditionFalse,
+		Reason:  "ServiceNetworkNotSwitched",
+		Message: fmt.Sprintf("The service network is still %s", original),
+	}
+	setConditions := func() {
+		r.status.SetServiceNetworkMigrationConditions([]operv1.OperatorCondition{inProgress, targetAdded, consumersMigrated, originalRemoved})
+	}
+
+	if reflect.DeepEqual(operConfig.Spec.ServiceNetwork, m.target) {
+		// Once completed, the migration has nothing left to delete or recreate
+		removed := v1helpers.IsOperatorConditionTrue(operConfig.Status.Conditions, names.ServiceNetworkMigrationOriginalRemoved)
+		if !removed {
+			var err error
+			if removed, err = r.syncOriginalRemoved(ctx, m, operConfig, bootstrapResult, &originalRemoved); err != nil {
+				return err
+			}
+		}
+		if !removed {
+			bootstrapResult.ServiceNetworkMigration.AdditionalServiceNetworks = m.original
+		} else {
+			originalRemoved.Status = operv1.ConditionTrue
+			originalRemoved.Reason = "AsExpected"
+			originalRemoved.Message = ""
+			inProgress.Status = operv1.ConditionFalse
+			inProgress.Reason = "ServiceNetworkMigrationCompleted"
+			inProgress.Message = fmt.Sprintf("The service network was migrated to %s. Remove the %s annotation.", target, names.ServiceNetworkMigrationAnnotation)
+		}
+		// The target stays available to the allocator until the migration is
+		// no longer requested
+		bootstrapResult.ServiceNetworkMigration.ServiceCIDRs = m.target
+		setConditions()
+		return nil
+	}
+
+	// The service network is the original: roll out ovnkube with the target
+	// before the apiserver allocates cluster IPs from it
+	bootstrapResult.ServiceNetworkMigration.AdditionalServiceNetworks = m.target
+	if !ovnRolledOutWithServiceNetworks(bootstrapResult.OVN, strings.Join(append(append([]string{}, m.original...), m.target...), ",")) {
+		targetAdded.Status = operv1.ConditionFalse
+		targetAdded.Reason = "OVNKubernetesRollingOut"
+		targetAdded.Message = fmt.Sprintf("Waiting for ovn-kubernetes to be rolled out with the service network %s", target)
+	} else {
+		bootstrapResult.ServiceNetworkMigration.ServiceCIDRs = m.target
+		notReady, err := r.serviceCIDRsNotReady(ctx, m.target)
+		if err != nil {
+			return err
+		}
+		if len(notReady) > 0 {
+			targetAdded.Status = operv1.ConditionFalse
+			targetAdded.Reason = "ServiceCIDRNotReady"
+			targetAdded.Message = fmt.Sprintf("Waiting for the ServiceCIDRs %s to be ready", strings.Join(notReady, ", "))
+		}
+	}
+	if targetAdded.Status != operv1.ConditionTrue {
+		consumersMigrated.Status = operv1.ConditionFalse
+		consumersMigrated.Reason = "TargetNotAdded"
+		consumersMigrated.Message = ""
+		setConditions()
+		return nil
+	}
+
+	inOriginal, err := r.servicesInServiceNetwork(ctx, m.original, true)
+	if err != nil {
+		return err
+	}
+	if len(inOriginal) > 0 {
+		consumersMigrated.Status = operv1.ConditionFalse
+		consumersMigrated.Reason = "ServicesInOriginalNetwork"
+		consumersMigrated.Message = servicesMessage(inOriginal, "have a cluster IP in the original service network "+original+" and must be recreated")
+		setConditions()
+		return nil
+	}
+	setConditions()
+
+	if isDryRun(operConfig) {
+		return nil
+	}
+	// The kubelets must not be rolled out with the cluster DNS IP of the target
+	// before the cluster DNS Service is recreated in it
+	if err := r.setMachineConfigPoolsPaused(ctx, true); err != nil {
+		return err
+	}
+	log.Printf("Service network migration: switching the service network from %s to %s", original, target)
+	clusterConfig := &configv1.Network{}
+	if err := r.client.Default().CRClient().Get(ctx, client.ObjectKey{Name: names.CLUSTER_CONFIG}, clusterConfig); err != nil {
+		return fmt.Errorf("failed to get the cluster configuration: %w", err)
+	}
+	patch := client.MergeFrom(clusterConfig.DeepCopy())
+	clusterConfig.Spec.ServiceNetwork = m.target
+	if err := r.client.Default().CRClient().Patch(ctx, clusterConfig, patch); err != nil {
+		return fmt.Errorf("failed to switch the service network of the cluster configuration: %w", err)
+	}
+	return nil
+}
+
+// syncOriginalRemoved drives the last phase of a service network migration,
+// once serviceNetwork is the target, and returns true once no Service has a
+// cluster IP in the original service network anymore:
+//  1. once the kube-apiserver is rolled out with the target, the apiserver
+//     Service is deleted so that the apiserver recreates it in the target.
+//  2. once the cluster configuration reports the target, the cluster DNS
+//     Service is deleted so that the DNS operator recreates it in the target.
+//  3. until the kubelets are rolled out with the cluster DNS IP of the target,
+//     Services are rendered with the cluster IPs both Services had in the
+//     original, for the pods that were started before. The MachineConfigPools
+//     are unpaused, so that every node is drained and rebooted.
+//  4. once the kubelets are rolled out, the default ServiceCIDR is deleted so
+//     that the apiserver recreates it with the target.
+func (r *ReconcileOperConfig) syncOriginalRemoved(ctx context.Context, m *serviceNetworkMigration, operConfig *operv1.Network,
+	bootstrapResult *bootstrap.BootstrapResult, cond *operv1.OperatorCondition) (bool, error) {
+	original, target := strings.Join(m.original, ","), strings.Join(m.target, ",")
+	_, originalCIDR, err := net.ParseCIDR(m.original[0])
+	if err != nil {
+		return false, fmt.Errorf("invalid service network %s: %w", m.original[0], err)
+	}
+	_, targetCIDR, err := net.ParseCIDR(m.target[0])
+	if err != nil {
+		return false, fmt.Errorf("invalid service network %s: %w", m.target[0], err)
+	}
+	// The apiserver takes the first IP of the service network and the DNS
+	// operator the tenth one
+	originalKubernetesIP, _ := utilnet.GetIndexedIP(originalCIDR, 1)
+	originalDNSIP, _ := utilnet.GetIndexedIP(originalCIDR, 10)
+	targetDNSIP, _ := utilnet.GetIndexedIP(targetCIDR, 10)
+
+	rolledOut, err := r.kubeAPIServerRolledOutWithServiceNetwork(ctx, m.target)
+	if err != nil {
+		return false, err
+	}
+	if !rolledOut {
+		cond.Reason = "KubeAPIServerRollingOut"
+		cond.Message = fmt.Sprintf("Waiting for the kube-apiserver to be rolled out with the service network %s", target)
+		return false, nil
+	}
+
+	recreating := []string{}
+	recreatedInOriginal := []string{}
+	kubernetesMoved, err := r.recreateServiceInTarget(ctx, m, operConfig, kubernetesServiceNamespace, kubernetesServiceName, &recreatedInOriginal)
+	if err != nil {
+		return false, err
+	}
+	if !kubernetesMoved {
+		recreating = append(recreating, kubernetesServiceNamespace+"/"+kubernetesServiceName)
+	}
+	// The DNS operator takes the cluster IP from the status of the cluster
+	// configuration
+	clusterConfig := &configv1.Network{}
+	if err := r.client.Default().CRClient().Get(ctx, client.ObjectKey{Name: names.CLUSTER_CONFIG}, clusterConfig); err != nil {
+		return false, fmt.Errorf("failed to get the cluster configuration: %w", err)
+	}
+	dnsMoved := false
+	if reflect.DeepEqual(clusterConfig.Status.ServiceNetwork, m.target) {
+		dnsMoved, err = r.recreateServiceInTarget(ctx, m, operConfig, dnsServiceNamespace, dnsServiceName, &recreatedInOriginal)
+		if err != nil {
+			return false, err
+		}
+	}
+	if !dnsMoved {
+		recreating = append(recreating, dnsServiceNamespace+"/"+dnsServiceName)
+	}
+
+	// Keep the original cluster IPs of the Services that were recreated, the
+	// other ones still have them
+	if kubernetesMoved {
+		originalService, err := r.originalKubernetesService(ctx, originalKubernetesIP.String())
+		if err != nil {
+			return false, err
+		}
+		bootstrapResult.ServiceNetworkMigration.OriginalKubernetesService = originalService
+	}
+	if dnsMoved {
+		bootstrapResult.ServiceNetworkMigration.OriginalDNSServiceIP = originalDNSIP.String()
+	}
+	if len(recreatedInOriginal) > 0 {
+		cond.Reason = "ServicesRecreatedInOriginalNetwork"
+		cond.Message = fmt.Sprintf("%s were recreated in the original service network %s after being deleted. Delete them once their owner uses the service network %s.",
+			strings.Join(recreatedInOriginal, " and "), original, target)
+		return false, nil
+	}
+	if len(recreating) > 0 {
+		cond.Reason = "RecreatingServices"
+		cond.Message = fmt.Sprintf("Waiting for %s to be recreated in the service network %s", strings.Join(recreating, " and "), target)
+		return false, nil
+	}
+
+	if !isDryRun(operConfig) {
+		if err := r.setMachineConfigPoolsPaused(ctx, false); err != nil {
+			return false, err
+		}
+	}
+	notRolledOut, err := r.poolsNotRolledOutWithClusterDNS(ctx, targetDNSIP.String())
+	if err != nil {
+		return false, err
+	}
+	if len(notRolledOut) > 0 {
+		cond.Reason = "KubeletsRollingOut"
+		cond.Message = fmt.Sprintf("Waiting for the MachineConfigPools %s to be rolled out with the cluster DNS IP %s. Paused pools must be unpaused.",
+			strings.Join(notRolledOut, ", "), targetDNSIP)
+		return false, nil
+	}
+	// Every pod was restarted with the target cluster IPs: the original ones are
+	// no longer rendered, and so deleted
+	bootstrapResult.ServiceNetworkMigration.OriginalKubernetesService = nil
+	bootstrapResult.ServiceNetworkMigration.OriginalDNSServiceIP = ""
+
+	recreated, err := r.recreateDefaultServiceCIDR(ctx, m, operConfig)
+	if err != nil {
+		return false, err
+	}
+	if !recreated {
+		cond.Reason = "DefaultServiceCIDRNotRecreated"
+		cond.Message = fmt.Sprintf("Waiting for the apiserver to recreate the %s ServiceCIDR with the service network %s", defaultServiceCIDRName, target)
+		return false, nil
+	}
+
+	inOriginal, err := r.servicesInServiceNetwork(ctx, m.original, false)
+	if err != nil {
+		return false, err
+	}
+	if len(inOriginal) > 0 {
+		cond.Reason = "ServicesInOriginalNetwork"
+		cond.Message = servicesMessage(inOriginal, "still have a cluster IP in the original service network "+original)
+		return false, nil
+	}
+	return true, nil
+}
+
+// kubeAPIServerRolledOutWithServiceNetwork returns true if every kube-apiserver
+// runs the latest revision, and that revision serves the given service network.
+func (r *ReconcileOperConfig) kubeAPIServerRolledOutWithServiceNetwork(ctx context.Context, serviceNetwork []string) (bool, error) {
+	kubeAPIServer := &operv1.KubeAPIServer{}
+	if err := r.client.Default().CRClient().Get(ctx, client.ObjectKey{Name: "cluster"}, kubeAPIServer); err != nil {
+		return false, fmt.Errorf("failed to get the kube-apiserver configuration: %w", err)
+	}
+	revision := kubeAPIServer.Status.LatestAvailableRevision
+	for _, nodeStatus := range kubeAPIServer.Status.NodeStatuses {
+		if nodeStatus.CurrentRevision != revision {
+			return false, nil
+		}
+	}
+	name := fmt.Sprintf("config-%d", revision)
+	cm, err := r.client.Default().Kubernetes().CoreV1().ConfigMaps("openshift-kube-apiserver").Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get the kube-apiserver configuration %s: %w", name, err)
+	}
+	config := struct {
+		APIServerArguments map[string][]string `json:"apiServerArguments"`
+	}{}
+	if err := yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &config); err != nil {
+		return false, fmt.Errorf("failed to parse the kube-apiserver configuration %s: %w", name, err)
+	}
+	return strings.Join(config.APIServerArguments["service-cluster-ip-range"], ",") == strings.Join(serviceNetwork, ","), nil
+}
+
+// recreateServiceInTarget returns true if the given Service has a cluster IP
+// in the target service network. If it still has one in the original, it is
+// deleted once so that its owner recreates it: if the owner recreates it in the
+// original again, it is appended to recreatedInOriginal instead.
+func (r *ReconcileOperConfig) recreateServiceInTarget(ctx context.Context, m *serviceNetworkMigration, operConfig *operv1.Network, namespace, name string,
+	recreatedInOriginal *[]string) (bool, error) {
+	svc, err := r.client.Default().Kubernetes().CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get Service %s/%s: %w", namespace, name, err)
+	}
+	inOriginal, err := serviceInServiceNetwork(svc, m.original)
+	if err != nil {
+		return false, err
+	}
+	if !inOriginal {
+		return true, nil
+	}
+	key := namespace + "/" + name
+	if uid, deleted := r.serviceNetworkMigrationDeleted[key]; deleted {
+		if uid != svc.UID {
+			*recreatedInOriginal = append(*recreatedInOriginal, key)
+		}
+		return false, nil
+	}
+	if svc.DeletionTimestamp != nil || isDryRun(operConfig) {
+		return false, nil
+	}
+	log.Printf("Service network migration: deleting Service %s/%s to recreate it in the service network %s", namespace, name, strings.Join(m.target, ","))
+	err = r.client.Default().Kubernetes().CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &svc.UID},
+	})
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		return false, fmt.Errorf("failed to delete Service %s/%s: %w", namespace, name, err)
+	}
+	if r.serviceNetworkMigrationDeleted == nil {
+		r.serviceNetworkMigrationDeleted = map[string]types.UID{}
+	}
+	r.serviceNetworkMigrationDeleted[key] = svc.UID
+	return false, nil
+}
+
+// originalKubernetesService returns the apiserver Service to render with the
+// given cluster IP, with the current endpoints of the apiserver.
+func (r *ReconcileOperConfig) originalKubernetesService(ctx context.Context, clusterIP string) (*bootstrap.OriginalKubernetesService, error) {
+	endpointSlices, err := r.client.Default().Kubernetes().DiscoveryV1().EndpointSlices(kubernetesServiceNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + kubernetesServiceName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the EndpointSlices of Service %s/%s: %w", kubernetesServiceNamespace, kubernetesServiceName, err)
+	}
+	addressType := discoveryv1.AddressTypeIPv4
+	if utilnet.IsIPv6String(clusterIP) {
+		addressType = discoveryv1.AddressTypeIPv6
+	}
+	service := &bootstrap.OriginalKubernetesService{ClusterIP: clusterIP, AddressType: string(addressType)}
+	for _, slice := range endpointSlices.Items {
+		if slice.AddressType != addressType {
+			continue
+		}
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				service.Addresses = append(service.Addresses, endpoint.Addresses...)
+			}
+		}
+		for _, port := range slice.Ports {
+			if port.Port != nil {
+				service.Port = *port.Port
+			}
+		}
+	}
+	if len(service.Addresses) == 0 || service.Port == 0 {
+		return nil, fmt.Errorf("no %s endpoint of Service %s/%s", addressType, kubernetesServiceNamespace, kubernetesServiceName)
+	}
+	return service, nil
+}
+
+// setMachineConfigPoolsPaused pauses the MachineConfigPools that are not paused
+// yet, or unpauses the ones it paused.
+func (r *ReconcileOperConfig) setMachineConfigPoolsPaused(ctx context.Context, paused bool) error {
+	pools := &mcfgv1.MachineConfigPoolList{}
+	if err := r.client.Default().CRClient().List(ctx, pools); err != nil {
+		return fmt.Errorf("failed to list MachineConfigPools: %w", err)
+	}
+	for i := range pools.Items {
+		pool := &pools.Items[i]
+		_, pausedByMigration := pool.Annotations[names.ServiceNetworkMigrationPausedAnnotation]
+		if paused == pool.Spec.Paused || !paused && !pausedByMigration {
+			continue
+		}
+		patch := client.MergeFrom(pool.DeepCopy())
+		pool.Spec.Paused = paused
+		if paused {
+			if pool.Annotations == nil {
+				pool.Annotations = map[string]string{}
+			}
+			pool.Annotations[names.ServiceNetworkMigrationPausedAnnotation] = ""
+		} else {
+			delete(pool.Annotations, names.ServiceNetworkMigrationPausedAnnotation)
+		}
+		log.Printf("Service network migration: setting paused to %t on MachineConfigPool %s", paused, pool.Name)
+		if err := r.client.Default().CRClient().Patch(ctx, pool, patch); err != nil {
+			return fmt.Errorf("failed to set paused on MachineConfigPool %s: %w", pool.Name, err)
+		}
+	}
+	return nil
+}
+
+// poolsNotRolledOutWithClusterDNS returns the names of the MachineConfigPools
+// whose machines don't all run a kubelet with the given cluster DNS IP yet.
+func (r *ReconcileOperConfig) poolsNotRolledOutWithClusterDNS(ctx context.Context, clusterDNS string) ([]string, error) {
+	pools := &mcfgv1.MachineConfigPoolList{}
+	if err := r.client.Default().CRClient().List(ctx, pools); err != nil {
+		return nil, fmt.Errorf("failed to list MachineConfigPools: %w", err)
+	}
+	notRolledOut := []string{}
+	for _, pool := range pools.Items {
+		if pool.Spec.Paused || pool.Status.ObservedGeneration != pool.Generation ||
+			pool.Status.UpdatedMachineCount != pool.Status.MachineCount ||
+			pool.Spec.Configuration.Name != pool.Status.Configuration.Name {
+			notRolledOut = append(notRolledOut, pool.Name)
+			continue
+		}
+		mc := &mcfgv1.MachineConfig{}
+		if err := r.client.Default().CRClient().Get(ctx, client.ObjectKey{Name: pool.Status.Configuration.Name}, mc); err != nil {
+			return nil, fmt.Errorf("failed to get MachineConfig %s: %w", pool.Status.Configuration.Name, err)
+		}
+		if !kubeletClusterDNSMatch(mc.Spec.Config.Raw, clusterDNS) {
+			notRolledOut = append(notRolledOut, pool.Name)
+		}
+	}
+	return notRolledOut, nil
+}
+
+// kubeletClusterDNSMatch returns true if the kubelet configuration of a
+// rendered MachineConfig has the given cluster DNS IP.
+func kubeletClusterDNSMatch(rawConfig []byte, clusterDNS string) bool {
+	ign, err := ctrlcommon.ParseAndConvertConfig(rawConfig)
+	if err != nil {
+		log.Printf("Failed to parse ignition config: %v", err)
+		return false
+	}
+	for _, file := range ign.Storage.Files {
+		if file.Path != kubeletConfigPath {
+			continue
+		}
+		contents, err := ctrlcommon.DecodeIgnitionFileContents(file.Contents.Source, file.Contents.Compression)
+		if err != nil {
+			log.Printf("Failed to decode %s: %v", kubeletConfigPath, err)
+			return false
+		}
+		kubeletConfig := struct {
+			ClusterDNS []string `json:"clusterDNS"`
+		}{}
+		if err := yaml.Unmarshal(contents, &kubeletConfig); err != nil {
+			log.Printf("Failed to parse %s: %v", kubeletConfigPath, err)
+			return false
+		}
+		return slices.Contains(kubeletConfig.ClusterDNS, clusterDNS)
+	}
+	return false
+}
+
+// recreateDefaultServiceCIDR returns true once the default ServiceCIDR is the
+// target service network. Until then, it is deleted so that the apiserver
+// stops allocating from the original and recreates it from its
+// --service-cluster-ip-range once no Service is left in it.
+func (r *ReconcileOperConfig) recreateDefaultServiceCIDR(ctx context.Context, m *serviceNetworkMigration, operConfig *operv1.Network) (bool, error) {
+	serviceCIDR, err := r.client.Default().Kubernetes().NetworkingV1beta1().ServiceCIDRs().Get(ctx, defaultServiceCIDRName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get ServiceCIDR %s: %w", defaultServiceCIDRName, err)
+	}
+	if reflect.DeepEqual(serviceCIDR.Spec.CIDRs, m.target) {
+		return true, nil
+	}
+	if serviceCIDR.DeletionTimestamp != nil || isDryRun(operConfig) {
+		return false, nil
+	}
+	log.Printf("Service network migration: deleting ServiceCIDR %s to recreate it with the service network %s", defaultServiceCIDRName, strings.Join(m.target, ","))
+	err = r.client.Default().Kubernetes().NetworkingV1beta1().ServiceCIDRs().Delete(ctx, defaultServiceCIDRName, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &serviceCIDR.UID},
+	})
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		return false, fmt.Errorf("failed to delete ServiceCIDR %s: %w", defaultServiceCIDRName, err)
+	}
+	return false, nil
+}
+
+// ovnRolledOutWithServiceNetworks returns true if both ovnkube-node and
+// ovnkube-control-plane are rolled out with the given service network CIDRs.
+func ovnRolledOutWithServiceNetworks(ovn bootstrap.OVNBootstrapResult, serviceNetworks string) bool {
+	for _, status := range []*bootstrap.OVNUpdateStatus{ovn.NodeUpdateStatus, ovn.ControlPlaneUpdateStatus} {
+		if status == nil || status.Progressing || status.ServiceNetworkCIDRs != serviceNetworks {
+			return false
+		}
+	}
+	return true
+}
+
+// serviceCIDRsNotReady returns the names of the ServiceCIDRs of the given CIDRs
+// that don't exist or are not ready yet.
+func (r *ReconcileOperConfig) serviceCIDRsNotReady(ctx context.Context, cidrs []string) ([]string, error) {
+	notReady := []string{}
+	for _, cidr := range cidrs {
+		name := network.ServiceCIDRName(cidr)
+		serviceCIDR, err := r.client.Default().Kubernetes().NetworkingV1beta1().ServiceCIDRs().Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			notReady = append(notReady, name)
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get ServiceCIDR %s: %w", name, err)
+		}
+		ready := false
+		for _, cond := range serviceCIDR.Status.Conditions {
+			if cond.Type == networkingv1beta1.ServiceCIDRConditionReady && cond.Status == metav1.ConditionTrue {
+				ready = true
+			}
+		}
+		if !ready {
+			notReady = append(notReady, name)
+		}
+	}
+	return notReady, nil
+}
+
+// servicesInServiceNetwork returns the namespace/name of the Services with a
+// cluster IP in serviceNetworks. If skipDerived is true, the Services of the
+// apiserver and of the cluster DNS are skipped: their cluster IP is derived
+// from the service network, so they only move once it has been switched.
+func (r *ReconcileOperConfig) servicesInServiceNetwork(ctx context.Context, serviceNetworks []string, skipDerived bool) ([]string, error) {
+	cidrs, err := parseCIDRs(serviceNetworks)
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := r.client.Default().Kubernetes().CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Services: %w", err)
+	}
+	found := []string{}
+	for _, svc := range services.Items {
+		if skipDerived && (svc.Namespace == kubernetesServiceNamespace && svc.Name == kubernetesServiceName ||
+			svc.Namespace == dnsServiceNamespace && svc.Name == dnsServiceName) {
+			continue
+		}
+		if hasClusterIPIn(&svc, cidrs) {
+			found = append(found, svc.Namespace+"/"+svc.Name)
+		}
+	}
+	return found, nil
+}
+
+// serviceInServiceNetwork returns true if svc has a cluster IP in
+// serviceNetworks.
+func serviceInServiceNetwork(svc *corev1.Service, serviceNetworks []string) (bool, error) {
+	cidrs, err := parseCIDRs(serviceNetworks)
+	if err != nil {
+		return false, err
+	}
+	return hasClusterIPIn(svc, cidrs), nil
+}
+
+func parseCIDRs(serviceNetworks []string) ([]*net.IPNet, error) {
+	cidrs := []*net.IPNet{}
+	for _, snet := range serviceNetworks {
+		_, cidr, err := net.ParseCIDR(snet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service network %s: %w", snet, err)
+		}
+		cidrs = append(cidrs, cidr)
+	}
+	return cidrs, nil
+}
+
+func hasClusterIPIn(svc *corev1.Service, cidrs []*net.IPNet) bool {
+	for _, clusterIP := range svc.Spec.ClusterIPs {
+		ip := net.ParseIP(clusterIP)
+		if ip == nil {
+			// headless
+			continue
+		}
+		if containsIP(cidrs, ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsIP(cidrs []*net.IPNet, ip net.IP) bool {
+	for _, cidr := range cidrs {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// servicesMessage returns a condition message listing the first Services.
+func servicesMessage(services []string, what string) string {
+	listed := services
+	if len(listed) > maxListedServices {
+		listed = listed[:maxListedServices]
+	}
+	message := fmt.Sprintf("%d Services %s: %s", len(services), what, strings.Join(listed, ", "))
+	if len(services) > len(listed) {
+		message += ", ..."
+	}
+	return message
+}
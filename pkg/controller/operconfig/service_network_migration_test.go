@@ -0,0 +1,702 @@
+package operconfig
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	mcfgv1 "github.com/openshift/api/machineconfiguration/v1"
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+	"github.com/openshift/cluster-network-operator/pkg/client/fake"
+	"github.com/openshift/cluster-network-operator/pkg/controller/statusmanager"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+)
+
+func TestGetServiceNetworkMigration(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	operConfig := &operv1.Network{
+		ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG},
+		Spec: operv1.NetworkSpec{
+			ClusterNetwork: []operv1.ClusterNetworkEntry{{CIDR: "10.128.0.0/14", HostPrefix: 23}},
+			ServiceNetwork: []string{"172.30.0.0/16"},
+			DefaultNetwork: operv1.DefaultNetworkDefinition{Type: operv1.NetworkTypeOVNKubernetes},
+		},
+	}
+	infraStatus := &bootstrap.InfraStatus{}
+	featureGates := featuregates.NewFeatureGate([]configv1.FeatureGateName{multiCIDRServiceAllocatorFeatureGate}, nil)
+
+	// No migration
+	m, err := getServiceNetworkMigration(operConfig, infraStatus, featureGates)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(m).To(BeNil())
+
+	// The migration needs the ServiceCIDR API
+	operConfig.Annotations = map[string]string{names.ServiceNetworkMigrationAnnotation: "172.31.0.0/16"}
+	_, err = getServiceNetworkMigration(operConfig, infraStatus, featuregates.NewFeatureGate(nil, nil))
+	g.Expect(err).To(MatchError(ContainSubstring("the MultiCIDRServiceAllocator feature gate must be enabled")))
+	_, err = getServiceNetworkMigration(operConfig, infraStatus, featuregates.NewFeatureGate(nil, []configv1.FeatureGateName{multiCIDRServiceAllocatorFeatureGate}))
+	g.Expect(err).To(MatchError(ContainSubstring("the MultiCIDRServiceAllocator feature gate must be enabled")))
+	g.Expect(operConfig.Annotations).NotTo(HaveKey(names.ServiceNetworkMigrationOriginalAnnotation))
+
+	// The original service network is recorded when the migration starts
+	m, err = getServiceNetworkMigration(operConfig, infraStatus, featureGates)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(m).To(Equal(&serviceNetworkMigration{original: []string{"172.30.0.0/16"}, target: []string{"172.31.0.0/16"}}))
+	g.Expect(operConfig.Annotations).To(HaveKeyWithValue(names.ServiceNetworkMigrationOriginalAnnotation, "172.30.0.0/16"))
+	g.Expect(m.allowsChange(&operConfig.Spec, &operv1.NetworkSpec{ServiceNetwork: []string{"172.31.0.0/16"}})).To(BeTrue())
+	g.Expect(m.allowsChange(&operConfig.Spec, &operv1.NetworkSpec{ServiceNetwork: []string{"172.32.0.0/16"}})).To(BeFalse())
+
+	// ... and kept once the service network is the target
+	operConfig.Spec.ServiceNetwork = []string{"172.31.0.0/16"}
+	m, err = getServiceNetworkMigration(operConfig, infraStatus, featureGates)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(m.original).To(Equal([]string{"172.30.0.0/16"}))
+
+	// The service network must be the original or the target
+	operConfig.Spec.ServiceNetwork = []string{"172.32.0.0/16"}
+	_, err = getServiceNetworkMigration(operConfig, infraStatus, featureGates)
+	g.Expect(err).To(MatchError(ContainSubstring("is neither the original service network 172.30.0.0/16 nor the target service network 172.31.0.0/16")))
+
+	// The target must not overlap with the original
+	operConfig.Spec.ServiceNetwork = []string{"172.30.0.0/16"}
+	operConfig.Annotations[names.ServiceNetworkMigrationAnnotation] = "172.30.0.0/15"
+	_, err = getServiceNetworkMigration(operConfig, infraStatus, featureGates)
+	g.Expect(err).To(MatchError(ContainSubstring("the target service network overlaps with 172.30.0.0/16")))
+
+	// The migration cannot be abandoned once the service network was switched
+	delete(operConfig.Annotations, names.ServiceNetworkMigrationAnnotation)
+	operConfig.Spec.ServiceNetwork = []string{"172.31.0.0/16"}
+	operConfig.Status.Conditions = []operv1.OperatorCondition{{Type: names.ServiceNetworkMigrationInProgress, Status: operv1.ConditionTrue}}
+	_, err = getServiceNetworkMigration(operConfig, infraStatus, featureGates)
+	g.Expect(err).To(MatchError(errServiceNetworkMigrationAbandoned))
+	g.Expect(err).To(MatchError(ContainSubstring("the service network was already switched from 172.30.0.0/16 to 172.31.0.0/16")))
+	g.Expect(operConfig.Annotations).To(HaveKey(names.ServiceNetworkMigrationOriginalAnnotation))
+
+	// ... unless it is completed
+	operConfig.Status.Conditions[0].Status = operv1.ConditionFalse
+	m, err = getServiceNetworkMigration(operConfig, infraStatus, featureGates)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(m).To(BeNil())
+	g.Expect(operConfig.Annotations).NotTo(HaveKey(names.ServiceNetworkMigrationOriginalAnnotation))
+
+	// The original is forgotten once the migration is no longer requested
+	operConfig.Spec.ServiceNetwork = []string{"172.30.0.0/16"}
+	operConfig.Status.Conditions[0].Status = operv1.ConditionTrue
+	operConfig.Annotations[names.ServiceNetworkMigrationOriginalAnnotation] = "172.30.0.0/16"
+	m, err = getServiceNetworkMigration(operConfig, infraStatus, featureGates)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(m).To(BeNil())
+	g.Expect(operConfig.Annotations).NotTo(HaveKey(names.ServiceNetworkMigrationOriginalAnnotation))
+}
+
+func newTestService(namespace, name, clusterIP string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       corev1.ServiceSpec{ClusterIP: clusterIP, ClusterIPs: []string{clusterIP}},
+	}
+}
+
+func newTestKubeAPIServerConfig(revision int, serviceNetwork string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-kube-apiserver", Name: fmt.Sprintf("config-%d", revision)},
+		Data:       map[string]string{"config.yaml": `{"apiServerArguments":{"service-cluster-ip-range":["` + serviceNetwork + `"]}}`},
+	}
+}
+
+func newTestPool(name string, paused bool, renderedConfig string) *mcfgv1.MachineConfigPool {
+	return &mcfgv1.MachineConfigPool{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: mcfgv1.MachineConfigPoolSpec{
+			Paused:        paused,
+			Configuration: mcfgv1.MachineConfigPoolStatusConfiguration{ObjectReference: corev1.ObjectReference{Name: renderedConfig}},
+		},
+		Status: mcfgv1.MachineConfigPoolStatus{
+			MachineCount:        1,
+			UpdatedMachineCount: 1,
+			Configuration:       mcfgv1.MachineConfigPoolStatusConfiguration{ObjectReference: corev1.ObjectReference{Name: renderedConfig}},
+		},
+	}
+}
+
+func newTestRenderedConfig(name, clusterDNS string) *mcfgv1.MachineConfig {
+	return &mcfgv1.MachineConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: mcfgv1.MachineConfigSpec{Config: runtime.RawExtension{Raw: []byte(`{"ignition":{"version":"3.2.0"},"storage":{"files":[` +
+			`{"path":"/etc/kubernetes/kubelet.conf","contents":{"source":"data:,clusterDNS%3A%0A-%20` + clusterDNS + `%0A"}}]}}`)}},
+	}
+}
+
+func TestSyncServiceNetworkMigration(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+
+	clusterConfig := &configv1.Network{
+		ObjectMeta: metav1.ObjectMeta{Name: names.CLUSTER_CONFIG},
+		Spec:       configv1.NetworkSpec{ServiceNetwork: []string{"172.30.0.0/16"}},
+	}
+	kubeAPIServer := &operv1.KubeAPIServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Status: operv1.KubeAPIServerStatus{StaticPodOperatorStatus: operv1.StaticPodOperatorStatus{
+			OperatorStatus: operv1.OperatorStatus{LatestAvailableRevision: 1},
+			NodeStatuses:   []operv1.NodeStatus{{NodeName: "master-0", CurrentRevision: 1}},
+		}},
+	}
+	ready := true
+	port := int32(6443)
+	client := fake.NewFakeClient(clusterConfig,
+		&configv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{Name: "network"}},
+		kubeAPIServer,
+		newTestPool("master", true, "rendered-master-1"),
+		newTestPool("worker", false, "rendered-worker-1"),
+		newTestRenderedConfig("rendered-master-1", "172.30.0.10"),
+		newTestRenderedConfig("rendered-worker-1", "172.30.0.10"),
+		newTestRenderedConfig("rendered-master-2", "172.31.0.10"),
+		newTestRenderedConfig("rendered-worker-2", "172.31.0.10"),
+		newTestService("default", "kubernetes", "172.30.0.1"),
+		newTestService("openshift-dns", "dns-default", "172.30.0.10"),
+		newTestService("app", "frontend", "172.30.12.34"),
+		newTestService("app", "headless", "None"),
+		newTestKubeAPIServerConfig(1, "172.30.0.0/16"),
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kubernetes",
+				Labels: map[string]string{discoveryv1.LabelServiceName: "kubernetes"}},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+				{Addresses: []string{"10.0.0.2"}},
+			},
+			Ports: []discoveryv1.EndpointPort{{Port: &port}},
+		},
+		&networkingv1beta1.ServiceCIDR{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubernetes"},
+			Spec:       networkingv1beta1.ServiceCIDRSpec{CIDRs: []string{"172.30.0.0/16"}},
+		},
+	)
+	_, err := client.Default().OpenshiftOperatorClient().OperatorV1().Networks().Create(ctx,
+		&operv1.Network{ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG}}, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	r := &ReconcileOperConfig{client: client, status: statusmanager.New(client, "network", names.StandAloneClusterName)}
+
+	m := &serviceNetworkMigration{original: []string{"172.30.0.0/16"}, target: []string{"172.31.0.0/16"}}
+	operConfig := &operv1.Network{
+		ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG},
+		Spec:       operv1.NetworkSpec{ServiceNetwork: []string{"172.30.0.0/16"}},
+	}
+	sync := func(ovn bootstrap.OVNBootstrapResult) (*bootstrap.BootstrapResult, map[string]operv1.OperatorCondition) {
+		t.Helper()
+		bootstrapResult := &bootstrap.BootstrapResult{OVN: ovn}
+		g.Expect(r.syncServiceNetworkMigration(ctx, m, operConfig, bootstrapResult)).To(Succeed())
+		oc, err := client.Default().OpenshiftOperatorClient().OperatorV1().Networks().Get(ctx, names.OPERATOR_CONFIG, metav1.GetOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+		conditions := map[string]operv1.OperatorCondition{}
+		for _, conditionType := range []string{names.ServiceNetworkMigrationInProgress, names.ServiceNetworkMigrationTargetAdded,
+			names.ServiceNetworkMigrationConsumersMigrated, names.ServiceNetworkMigrationOriginalRemoved} {
+			if cond := v1helpers.FindOperatorCondition(oc.Status.Conditions, conditionType); cond != nil {
+				conditions[conditionType] = *cond
+			}
+		}
+		return bootstrapResult, conditions
+	}
+
+	// ovnkube is rolled out with the target first
+	bootstrapResult, conditions := sync(bootstrap.OVNBootstrapResult{})
+	g.Expect(bootstrapResult.ServiceNetworkMigration.AdditionalServiceNetworks).To(Equal(m.target))
+	g.Expect(bootstrapResult.ServiceNetworkMigration.ServiceCIDRs).To(BeEmpty())
+	g.Expect(conditions[names.ServiceNetworkMigrationInProgress].Status).To(Equal(operv1.ConditionTrue))
+	g.Expect(conditions[names.ServiceNetworkMigrationTargetAdded].Reason).To(Equal("OVNKubernetesRollingOut"))
+	g.Expect(conditions[names.ServiceNetworkMigrationConsumersMigrated].Status).To(Equal(operv1.ConditionFalse))
+
+	// then the ServiceCIDR is created
+	rolledOut := bootstrap.OVNBootstrapResult{
+		NodeUpdateStatus:         &bootstrap.OVNUpdateStatus{ServiceNetworkCIDRs: "172.30.0.0/16,172.31.0.0/16"},
+		ControlPlaneUpdateStatus: &bootstrap.OVNUpdateStatus{ServiceNetworkCIDRs: "172.30.0.0/16,172.31.0.0/16"},
+	}
+	bootstrapResult, conditions = sync(rolledOut)
+	g.Expect(bootstrapResult.ServiceNetworkMigration.ServiceCIDRs).To(Equal(m.target))
+	g.Expect(conditions[names.ServiceNetworkMigrationTargetAdded].Reason).To(Equal("ServiceCIDRNotReady"))
+
+	_, err = client.Default().Kubernetes().NetworkingV1beta1().ServiceCIDRs().Create(ctx, &networkingv1beta1.ServiceCIDR{
+		ObjectMeta: metav1.ObjectMeta{Name: "openshift-service-network-172-31-0-0-16"},
+		Spec:       networkingv1beta1.ServiceCIDRSpec{CIDRs: m.target},
+		Status: networkingv1beta1.ServiceCIDRStatus{Conditions: []metav1.Condition{
+			{Type: networkingv1beta1.ServiceCIDRConditionReady, Status: metav1.ConditionTrue},
+		}},
+	}, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The Services but the apiserver's and the DNS's must be recreated
+	_, conditions = sync(rolledOut)
+	g.Expect(conditions[names.ServiceNetworkMigrationTargetAdded].Status).To(Equal(operv1.ConditionTrue))
+	g.Expect(conditions[names.ServiceNetworkMigrationConsumersMigrated].Status).To(Equal(operv1.ConditionFalse))
+	g.Expect(conditions[names.ServiceNetworkMigrationConsumersMigrated].Message).To(Equal(
+		"1 Services have a cluster IP in the original service network 172.30.0.0/16 and must be recreated: app/frontend"))
+
+	_, err = client.Default().Kubernetes().CoreV1().Services("app").Update(ctx, newTestService("app", "frontend", "172.31.12.34"), metav1.UpdateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	_, conditions = sync(rolledOut)
+	g.Expect(conditions[names.ServiceNetworkMigrationConsumersMigrated].Status).To(Equal(operv1.ConditionTrue))
+	g.Expect(conditions[names.ServiceNetworkMigrationOriginalRemoved].Status).To(Equal(operv1.ConditionFalse))
+
+	// The service network of the cluster configuration is switched to the target,
+	// with the MachineConfigPools paused
+	g.Expect(client.Default().CRClient().Get(ctx, types.NamespacedName{Name: names.CLUSTER_CONFIG}, clusterConfig)).To(Succeed())
+	g.Expect(clusterConfig.Spec.ServiceNetwork).To(Equal(m.target))
+	getPool := func(name string) *mcfgv1.MachineConfigPool {
+		t.Helper()
+		pool := &mcfgv1.MachineConfigPool{}
+		g.Expect(client.Default().CRClient().Get(ctx, types.NamespacedName{Name: name}, pool)).To(Succeed())
+		return pool
+	}
+	g.Expect(getPool("worker").Spec.Paused).To(BeTrue())
+	g.Expect(getPool("worker").Annotations).To(HaveKey(names.ServiceNetworkMigrationPausedAnnotation))
+	g.Expect(getPool("master").Annotations).NotTo(HaveKey(names.ServiceNetworkMigrationPausedAnnotation))
+
+	// The original is kept until the kube-apiserver is rolled out with the target
+	operConfig.Spec.ServiceNetwork = m.target
+	bootstrapResult, conditions = sync(rolledOut)
+	g.Expect(bootstrapResult.ServiceNetworkMigration.AdditionalServiceNetworks).To(Equal(m.original))
+	g.Expect(conditions[names.ServiceNetworkMigrationOriginalRemoved].Reason).To(Equal("KubeAPIServerRollingOut"))
+	g.Expect(conditions[names.ServiceNetworkMigrationInProgress].Status).To(Equal(operv1.ConditionTrue))
+
+	_, err = client.Default().Kubernetes().CoreV1().ConfigMaps("openshift-kube-apiserver").Create(ctx, newTestKubeAPIServerConfig(2, "172.31.0.0/16"), metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	kubeAPIServer.Status.LatestAvailableRevision = 2
+	g.Expect(client.Default().CRClient().Update(ctx, kubeAPIServer)).To(Succeed())
+	_, conditions = sync(rolledOut)
+	g.Expect(conditions[names.ServiceNetworkMigrationOriginalRemoved].Reason).To(Equal("KubeAPIServerRollingOut"))
+
+	// then the apiserver Service is recreated in the target
+	kubeAPIServer.Status.NodeStatuses[0].CurrentRevision = 2
+	g.Expect(client.Default().CRClient().Update(ctx, kubeAPIServer)).To(Succeed())
+	bootstrapResult, conditions = sync(rolledOut)
+	g.Expect(conditions[names.ServiceNetworkMigrationOriginalRemoved].Reason).To(Equal("RecreatingServices"))
+	g.Expect(conditions[names.ServiceNetworkMigrationOriginalRemoved].Message).To(Equal(
+		"Waiting for default/kubernetes and openshift-dns/dns-default to be recreated in the service network 172.31.0.0/16"))
+	g.Expect(bootstrapResult.ServiceNetworkMigration.OriginalKubernetesService).To(BeNil())
+	_, err = client.Default().Kubernetes().CoreV1().Services("default").Get(ctx, "kubernetes", metav1.GetOptions{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	_, err = client.Default().Kubernetes().CoreV1().Services("openshift-dns").Get(ctx, "dns-default", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// and the cluster DNS Service once the cluster configuration reports the target
+	_, err = client.Default().Kubernetes().CoreV1().Services("default").Create(ctx, newTestService("default", "kubernetes", "172.31.0.1"), metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	clusterConfig.Status.ServiceNetwork = m.target
+	g.Expect(client.Default().CRClient().Update(ctx, clusterConfig)).To(Succeed())
+	bootstrapResult, conditions = sync(rolledOut)
+	g.Expect(conditions[names.ServiceNetworkMigrationOriginalRemoved].Message).To(Equal(
+		"Waiting for openshift-dns/dns-default to be recreated in the service network 172.31.0.0/16"))
+	g.Expect(bootstrapResult.ServiceNetworkMigration.OriginalKubernetesService).To(Equal(&bootstrap.OriginalKubernetesService{
+		ClusterIP: "172.30.0.1", AddressType: "IPv4", Addresses: []string{"10.0.0.1", "10.0.0.2"}, Port: 6443,
+	}))
+	_, err = client.Default().Kubernetes().CoreV1().Services("openshift-dns").Get(ctx, "dns-default", metav1.GetOptions{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(getPool("worker").Spec.Paused).To(BeTrue())
+
+	// The MachineConfigPools paused by the migration are unpaused once both
+	// Services are recreated, and the original cluster IPs are kept until the
+	// kubelets are rolled out with the cluster DNS IP of the target
+	_, err = client.Default().Kubernetes().CoreV1().Services("openshift-dns").Create(ctx, newTestService("openshift-dns", "dns-default", "172.31.0.10"), metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	bootstrapResult, conditions = sync(rolledOut)
+	g.Expect(conditions[names.ServiceNetworkMigrationOriginalRemoved].Reason).To(Equal("KubeletsRollingOut"))
+	g.Expect(conditions[names.ServiceNetworkMigrationOriginalRemoved].Message).To(Equal(
+		"Waiting for the MachineConfigPools master, worker to be rolled out with the cluster DNS IP 172.31.0.10. Paused pools must be unpaused."))
+	g.Expect(bootstrapResult.ServiceNetworkMigration.OriginalKubernetesService).NotTo(BeNil())
+	g.Expect(bootstrapResult.ServiceNetworkMigration.OriginalDNSServiceIP).To(Equal("172.30.0.10"))
+	g.Expect(getPool("worker").Spec.Paused).To(BeFalse())
+	g.Expect(getPool("worker").Annotations).NotTo(HaveKey(names.ServiceNetworkMigrationPausedAnnotation))
+	g.Expect(getPool("master").Spec.Paused).To(BeTrue())
+
+	for _, pool := range []*mcfgv1.MachineConfigPool{newTestPool("master", false, "rendered-master-2"), newTestPool("worker", false, "rendered-worker-2")} {
+		pool.ResourceVersion = getPool(pool.Name).ResourceVersion
+		g.Expect(client.Default().CRClient().Update(ctx, pool)).To(Succeed())
+	}
+	// The default ServiceCIDR is then deleted for the apiserver to recreate it
+	bootstrapResult, conditions = sync(rolledOut)
+	g.Expect(conditions[names.ServiceNetworkMigrationOriginalRemoved].Reason).To(Equal("DefaultServiceCIDRNotRecreated"))
+	g.Expect(bootstrapResult.ServiceNetworkMigration.OriginalKubernetesService).To(BeNil())
+	g.Expect(bootstrapResult.ServiceNetworkMigration.OriginalDNSServiceIP).To(BeEmpty())
+	_, err = client.Default().Kubernetes().NetworkingV1beta1().ServiceCIDRs().Get(ctx, "kubernetes", metav1.GetOptions{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	_, err = client.Default().Kubernetes().NetworkingV1beta1().ServiceCIDRs().Create(ctx, &networkingv1beta1.ServiceCIDR{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubernetes"},
+		Spec:       networkingv1beta1.ServiceCIDRSpec{CIDRs: m.target},
+	}, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	bootstrapResult, conditions = sync(rolledOut)
+	g.Expect(bootstrapResult.ServiceNetworkMigration.AdditionalServiceNetworks).To(BeEmpty())
+	g.Expect(conditions[names.ServiceNetworkMigrationOriginalRemoved].Status).To(Equal(operv1.ConditionTrue))
+	g.Expect(conditions[names.ServiceNetworkMigrationInProgress].Status).To(Equal(operv1.ConditionFalse))
+	g.Expect(conditions[names.ServiceNetworkMigrationInProgress].Reason).To(Equal("ServiceNetworkMigrationCompleted"))
+}
+
+// serviceNetworkMigrationState describes how far the cluster is in the
+// migration of the service network from 172.30.0.0/16 to 172.31.0.0/16.
+type serviceNetworkMigrationState struct {
+	targetServiceCIDRReady   bool
+	frontendInTarget         bool
+	poolsPaused              bool
+	switched                 bool
+	kubeAPIServerRolledOut   bool
+	statusSwitched           bool
+	kubernetesInTarget       bool
+	dnsInTarget              bool
+	kubeletsRolledOut        bool
+	defaultServiceCIDRTarget bool
+}
+
+func (s serviceNetworkMigrationState) objects() []crclient.Object {
+	pick := func(cond bool, ifTrue, ifFalse string) string {
+		if cond {
+			return ifTrue
+		}
+		return ifFalse
+	}
+	clusterConfig := &configv1.Network{
+		ObjectMeta: metav1.ObjectMeta{Name: names.CLUSTER_CONFIG},
+		Spec:       configv1.NetworkSpec{ServiceNetwork: []string{pick(s.switched, "172.31.0.0/16", "172.30.0.0/16")}},
+		Status:     configv1.NetworkStatus{ServiceNetwork: []string{pick(s.statusSwitched, "172.31.0.0/16", "172.30.0.0/16")}},
+	}
+	revision := int32(1)
+	if s.switched {
+		revision = 2
+	}
+	currentRevision := revision
+	if s.switched && !s.kubeAPIServerRolledOut {
+		currentRevision = 1
+	}
+	kubeAPIServer := &operv1.KubeAPIServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Status: operv1.KubeAPIServerStatus{StaticPodOperatorStatus: operv1.StaticPodOperatorStatus{
+			OperatorStatus: operv1.OperatorStatus{LatestAvailableRevision: revision},
+			NodeStatuses:   []operv1.NodeStatus{{NodeName: "master-0", CurrentRevision: currentRevision}},
+		}},
+	}
+	rendered := pick(s.kubeletsRolledOut, "2", "1")
+	worker := newTestPool("worker", (s.poolsPaused || s.switched) && !s.dnsInTarget, "rendered-worker-"+rendered)
+	if worker.Spec.Paused {
+		worker.Annotations = map[string]string{names.ServiceNetworkMigrationPausedAnnotation: ""}
+	}
+	withUID := func(svc *corev1.Service) *corev1.Service {
+		svc.UID = types.UID(svc.Namespace + "-" + svc.Name + "-" + svc.Spec.ClusterIP)
+		return svc
+	}
+	ready := true
+	port := int32(6443)
+	objs := []crclient.Object{
+		clusterConfig,
+		&configv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{Name: "network"}},
+		kubeAPIServer,
+		newTestKubeAPIServerConfig(1, "172.30.0.0/16"),
+		newTestKubeAPIServerConfig(2, "172.31.0.0/16"),
+		newTestPool("master", false, "rendered-master-"+rendered),
+		worker,
+		newTestRenderedConfig("rendered-master-1", "172.30.0.10"),
+		newTestRenderedConfig("rendered-worker-1", "172.30.0.10"),
+		newTestRenderedConfig("rendered-master-2", "172.31.0.10"),
+		newTestRenderedConfig("rendered-worker-2", "172.31.0.10"),
+		withUID(newTestService("default", "kubernetes", pick(s.kubernetesInTarget, "172.31.0.1", "172.30.0.1"))),
+		withUID(newTestService("openshift-dns", "dns-default", pick(s.dnsInTarget, "172.31.0.10", "172.30.0.10"))),
+		withUID(newTestService("app", "frontend", pick(s.frontendInTarget, "172.31.12.34", "172.30.12.34"))),
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kubernetes",
+				Labels: map[string]string{discoveryv1.LabelServiceName: "kubernetes"}},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}}},
+			Ports:       []discoveryv1.EndpointPort{{Port: &port}},
+		},
+		&networkingv1beta1.ServiceCIDR{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubernetes"},
+			Spec:       networkingv1beta1.ServiceCIDRSpec{CIDRs: []string{pick(s.defaultServiceCIDRTarget, "172.31.0.0/16", "172.30.0.0/16")}},
+		},
+	}
+	if s.targetServiceCIDRReady {
+		objs = append(objs, &networkingv1beta1.ServiceCIDR{
+			ObjectMeta: metav1.ObjectMeta{Name: "openshift-service-network-172-31-0-0-16"},
+			Spec:       networkingv1beta1.ServiceCIDRSpec{CIDRs: []string{"172.31.0.0/16"}},
+			Status: networkingv1beta1.ServiceCIDRStatus{Conditions: []metav1.Condition{
+				{Type: networkingv1beta1.ServiceCIDRConditionReady, Status: metav1.ConditionTrue},
+			}},
+		})
+	}
+	return objs
+}
+
+func TestSyncServiceNetworkMigrationPhases(t *testing.T) {
+	m := &serviceNetworkMigration{original: []string{"172.30.0.0/16"}, target: []string{"172.31.0.0/16"}}
+	notRolledOut := bootstrap.OVNBootstrapResult{}
+	rolledOut := bootstrap.OVNBootstrapResult{
+		NodeUpdateStatus:         &bootstrap.OVNUpdateStatus{ServiceNetworkCIDRs: "172.30.0.0/16,172.31.0.0/16"},
+		ControlPlaneUpdateStatus: &bootstrap.OVNUpdateStatus{ServiceNetworkCIDRs: "172.30.0.0/16,172.31.0.0/16"},
+	}
+	switched := serviceNetworkMigrationState{targetServiceCIDRReady: true, frontendInTarget: true, switched: true}
+	with := func(s serviceNetworkMigrationState, f func(*serviceNetworkMigrationState)) serviceNetworkMigrationState {
+		f(&s)
+		return s
+	}
+	inProgress := []operv1.OperatorCondition{{Type: names.ServiceNetworkMigrationInProgress, Status: operv1.ConditionTrue}}
+
+	testCases := []struct {
+		name       string
+		abandoned  bool
+		state      serviceNetworkMigrationState
+		ovn        bootstrap.OVNBootstrapResult
+		conditions []operv1.OperatorCondition
+		// the Services the migration already deleted, by namespace/name
+		deleted map[string]types.UID
+
+		// the expected reasons, by condition type
+		reasons               map[string]string
+		additional            []string
+		deletedServices       []string
+		deletedServiceCIDR    bool
+		clusterServiceNetwork string
+		workerPaused          bool
+	}{
+		{
+			name:  "ovnkube is rolled out with the target",
+			state: serviceNetworkMigrationState{},
+			ovn:   notRolledOut,
+			reasons: map[string]string{
+				names.ServiceNetworkMigrationTargetAdded:       "OVNKubernetesRollingOut",
+				names.ServiceNetworkMigrationConsumersMigrated: "TargetNotAdded",
+			},
+			additional:            m.target,
+			clusterServiceNetwork: "172.30.0.0/16",
+		},
+		{
+			name:  "the target ServiceCIDR is created",
+			state: serviceNetworkMigrationState{},
+			ovn:   rolledOut,
+			reasons: map[string]string{
+				names.ServiceNetworkMigrationTargetAdded: "ServiceCIDRNotReady",
+			},
+			additional:            m.target,
+			clusterServiceNetwork: "172.30.0.0/16",
+		},
+		{
+			name:  "the Services are recreated by the administrator",
+			state: serviceNetworkMigrationState{targetServiceCIDRReady: true},
+			ovn:   rolledOut,
+			reasons: map[string]string{
+				names.ServiceNetworkMigrationTargetAdded:       "AsExpected",
+				names.ServiceNetworkMigrationConsumersMigrated: "ServicesInOriginalNetwork",
+			},
+			additional:            m.target,
+			clusterServiceNetwork: "172.30.0.0/16",
+		},
+		{
+			name:  "the service network is switched",
+			state: serviceNetworkMigrationState{targetServiceCIDRReady: true, frontendInTarget: true},
+			ovn:   rolledOut,
+			reasons: map[string]string{
+				names.ServiceNetworkMigrationConsumersMigrated: "AsExpected",
+				names.ServiceNetworkMigrationOriginalRemoved:   "ServiceNetworkNotSwitched",
+			},
+			additional:            m.target,
+			clusterServiceNetwork: "172.31.0.0/16",
+			workerPaused:          true,
+		},
+		{
+			name:  "the kube-apiserver is rolled out with the target",
+			state: switched,
+			ovn:   rolledOut,
+			reasons: map[string]string{
+				names.ServiceNetworkMigrationOriginalRemoved: "KubeAPIServerRollingOut",
+			},
+			additional:            m.original,
+			clusterServiceNetwork: "172.31.0.0/16",
+			workerPaused:          true,
+		},
+		{
+			name:  "the apiserver Service is deleted",
+			state: with(switched, func(s *serviceNetworkMigrationState) { s.kubeAPIServerRolledOut = true }),
+			ovn:   rolledOut,
+			reasons: map[string]string{
+				names.ServiceNetworkMigrationOriginalRemoved: "RecreatingServices",
+			},
+			additional:            m.original,
+			deletedServices:       []string{"default/kubernetes"},
+			clusterServiceNetwork: "172.31.0.0/16",
+			workerPaused:          true,
+		},
+		{
+			name:    "the apiserver Service is not deleted again once deleted",
+			state:   with(switched, func(s *serviceNetworkMigrationState) { s.kubeAPIServerRolledOut = true }),
+			ovn:     rolledOut,
+			deleted: map[string]types.UID{"default/kubernetes": "default-kubernetes-172.30.0.1"},
+			reasons: map[string]string{
+				names.ServiceNetworkMigrationOriginalRemoved: "RecreatingServices",
+			},
+			additional:            m.original,
+			clusterServiceNetwork: "172.31.0.0/16",
+			workerPaused:          true,
+		},
+		{
+			name:    "the apiserver Service is recreated in the original",
+			state:   with(switched, func(s *serviceNetworkMigrationState) { s.kubeAPIServerRolledOut = true }),
+			ovn:     rolledOut,
+			deleted: map[string]types.UID{"default/kubernetes": "deleted-uid"},
+			reasons: map[string]string{
+				names.ServiceNetworkMigrationOriginalRemoved: "ServicesRecreatedInOriginalNetwork",
+			},
+			additional:            m.original,
+			clusterServiceNetwork: "172.31.0.0/16",
+			workerPaused:          true,
+		},
+		{
+			name: "the cluster DNS Service is deleted",
+			state: with(switched, func(s *serviceNetworkMigrationState) {
+				s.kubeAPIServerRolledOut, s.kubernetesInTarget, s.statusSwitched = true, true, true
+			}),
+			ovn: rolledOut,
+			reasons: map[string]string{
+				names.ServiceNetworkMigrationOriginalRemoved: "RecreatingServices",
+			},
+			additional:            m.original,
+			deletedServices:       []string{"openshift-dns/dns-default"},
+			clusterServiceNetwork: "172.31.0.0/16",
+			workerPaused:          true,
+		},
+		{
+			name: "the kubelets are rolled out with the target cluster DNS IP",
+			state: with(switched, func(s *serviceNetworkMigrationState) {
+				s.kubeAPIServerRolledOut, s.kubernetesInTarget, s.statusSwitched, s.dnsInTarget = true, true, true, true
+			}),
+			ovn: rolledOut,
+			reasons: map[string]string{
+				names.ServiceNetworkMigrationOriginalRemoved: "KubeletsRollingOut",
+			},
+			additional:            m.original,
+			clusterServiceNetwork: "172.31.0.0/16",
+		},
+		{
+			name: "the default ServiceCIDR is deleted",
+			state: with(switched, func(s *serviceNetworkMigrationState) {
+				s.kubeAPIServerRolledOut, s.kubernetesInTarget, s.statusSwitched, s.dnsInTarget, s.kubeletsRolledOut = true, true, true, true, true
+			}),
+			ovn: rolledOut,
+			reasons: map[string]string{
+				names.ServiceNetworkMigrationOriginalRemoved: "DefaultServiceCIDRNotRecreated",
+			},
+			additional:            m.original,
+			deletedServiceCIDR:    true,
+			clusterServiceNetwork: "172.31.0.0/16",
+		},
+		{
+			name: "the migration is completed",
+			state: with(switched, func(s *serviceNetworkMigrationState) {
+				s.kubeAPIServerRolledOut, s.kubernetesInTarget, s.statusSwitched, s.dnsInTarget, s.kubeletsRolledOut = true, true, true, true, true
+				s.defaultServiceCIDRTarget = true
+			}),
+			ovn: rolledOut,
+			reasons: map[string]string{
+				names.ServiceNetworkMigrationInProgress:      "ServiceNetworkMigrationCompleted",
+				names.ServiceNetworkMigrationOriginalRemoved: "AsExpected",
+			},
+			clusterServiceNetwork: "172.31.0.0/16",
+		},
+		{
+			name:  "a completed migration deletes nothing",
+			state: with(switched, func(s *serviceNetworkMigrationState) { s.kubeAPIServerRolledOut, s.statusSwitched = true, true }),
+			ovn:   rolledOut,
+			conditions: []operv1.OperatorCondition{
+				{Type: names.ServiceNetworkMigrationOriginalRemoved, Status: operv1.ConditionTrue},
+			},
+			reasons: map[string]string{
+				names.ServiceNetworkMigrationInProgress:      "ServiceNetworkMigrationCompleted",
+				names.ServiceNetworkMigrationOriginalRemoved: "AsExpected",
+			},
+			clusterServiceNetwork: "172.31.0.0/16",
+			workerPaused:          true,
+		},
+		{
+			name:       "the migration is abandoned before the switch",
+			abandoned:  true,
+			state:      with(switched, func(s *serviceNetworkMigrationState) { s.switched, s.poolsPaused = false, true }),
+			ovn:        rolledOut,
+			conditions: inProgress,
+			reasons: map[string]string{
+				names.ServiceNetworkMigrationInProgress: "ServiceNetworkMigrationNotRequested",
+			},
+			clusterServiceNetwork: "172.30.0.0/16",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			ctx := context.Background()
+
+			migration := m
+			if tc.abandoned {
+				migration = nil
+			}
+			client := fake.NewFakeClient(tc.state.objects()...)
+			_, err := client.Default().OpenshiftOperatorClient().OperatorV1().Networks().Create(ctx,
+				&operv1.Network{ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG}}, metav1.CreateOptions{})
+			g.Expect(err).NotTo(HaveOccurred())
+			r := &ReconcileOperConfig{client: client, status: statusmanager.New(client, "network", names.StandAloneClusterName),
+				serviceNetworkMigrationDeleted: tc.deleted}
+
+			serviceNetwork := "172.30.0.0/16"
+			if tc.state.switched {
+				serviceNetwork = "172.31.0.0/16"
+			}
+			operConfig := &operv1.Network{
+				ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG},
+				Spec:       operv1.NetworkSpec{ServiceNetwork: []string{serviceNetwork}},
+				Status:     operv1.NetworkStatus{OperatorStatus: operv1.OperatorStatus{Conditions: tc.conditions}},
+			}
+			bootstrapResult := &bootstrap.BootstrapResult{OVN: tc.ovn}
+			g.Expect(r.syncServiceNetworkMigration(ctx, migration, operConfig, bootstrapResult)).To(Succeed())
+
+			oc, err := client.Default().OpenshiftOperatorClient().OperatorV1().Networks().Get(ctx, names.OPERATOR_CONFIG, metav1.GetOptions{})
+			g.Expect(err).NotTo(HaveOccurred())
+			for conditionType, reason := range tc.reasons {
+				cond := v1helpers.FindOperatorCondition(oc.Status.Conditions, conditionType)
+				g.Expect(cond).NotTo(BeNil(), conditionType)
+				g.Expect(cond.Reason).To(Equal(reason), conditionType)
+			}
+			g.Expect(bootstrapResult.ServiceNetworkMigration.AdditionalServiceNetworks).To(Equal(tc.additional))
+
+			for _, svc := range []string{"default/kubernetes", "openshift-dns/dns-default", "app/frontend"} {
+				namespace, name, _ := strings.Cut(svc, "/")
+				_, err := client.Default().Kubernetes().CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+				if slices.Contains(tc.deletedServices, svc) {
+					g.Expect(apierrors.IsNotFound(err)).To(BeTrue(), svc)
+				} else {
+					g.Expect(err).NotTo(HaveOccurred(), svc)
+				}
+			}
+			_, err = client.Default().Kubernetes().NetworkingV1beta1().ServiceCIDRs().Get(ctx, "kubernetes", metav1.GetOptions{})
+			g.Expect(apierrors.IsNotFound(err)).To(Equal(tc.deletedServiceCIDR))
+
+			clusterConfig := &configv1.Network{}
+			g.Expect(client.Default().CRClient().Get(ctx, types.NamespacedName{Name: names.CLUSTER_CONFIG}, clusterConfig)).To(Succeed())
+			g.Expect(clusterConfig.Spec.ServiceNetwork).To(Equal([]string{tc.clusterServiceNetwork}))
+			pool := &mcfgv1.MachineConfigPool{}
+			g.Expect(client.Default().CRClient().Get(ctx, types.NamespacedName{Name: "worker"}, pool)).To(Succeed())
+			g.Expect(pool.Spec.Paused).To(Equal(tc.workerPaused))
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package pki
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"log"
+	"math/big"
+	"reflect"
+	"sort"
+	"time"
+
+	netopv1 "github.com/openshift/cluster-network-operator/pkg/apis/network/v1"
+
+	libgocrypto "github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+)
+
+// rsaKeyBits is the size of RSA keys, as generated by library-go
+const rsaKeyBits = 2048
+
+// newKeyPair generates a key pair with algorithm, and returns it with the hash
+// of its public key, for the subject key identifier
+func newKeyPair(algorithm netopv1.KeyAlgorithm) (crypto.PublicKey, crypto.PrivateKey, []byte, error) {
+	var publicKey crypto.PublicKey
+	var privateKey crypto.PrivateKey
+	switch algorithm {
+	case netopv1.KeyAlgorithmECDSA:
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		publicKey, privateKey = &key.PublicKey, key
+	case netopv1.KeyAlgorithmRSA, "":
+		key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		publicKey, privateKey = &key.PublicKey, key
+	default:
+		return nil, nil, nil, fmt.Errorf("unsupported key algorithm %q", algorithm)
+	}
+
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	hash := sha1.Sum(publicKeyBytes)
+	return publicKey, privateKey, hash[:], nil
+}
+
+// keyAlgorithm returns the algorithm of the public key of cert
+func keyAlgorithm(cert *x509.Certificate) netopv1.KeyAlgorithm {
+	switch cert.PublicKeyAlgorithm {
+	case x509.ECDSA:
+		return netopv1.KeyAlgorithmECDSA
+	case x509.RSA:
+		return netopv1.KeyAlgorithmRSA
+	}
+	return netopv1.KeyAlgorithm(cert.PublicKeyAlgorithm.String())
+}
+
+// keyUsage returns the key usage of a certificate with a key of algorithm
+func keyUsage(algorithm netopv1.KeyAlgorithm) x509.KeyUsage {
+	if algorithm == netopv1.KeyAlgorithmECDSA {
+		// ECDSA keys can't encipher other keys
+		return x509.KeyUsageDigitalSignature
+	}
+	return x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature
+}
+
+// randomSerialNumber returns a random positive serial number
+func randomSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+}
+
+// caManager creates and rotates the CA certificate of a PKI. library-go only
+// creates CAs with RSA keys, and only rotates them when they get old, so the
+// CA Secret is managed here instead: the CA is rotated when library-go would,
+// and also when its configuration changes. As it is never due when library-go
+// checks it, library-go only signs with it.
+type caManager struct {
+	namespace string
+	name      string
+	spec      netopv1.CASpec
+	validity  time.Duration
+	refresh   time.Duration
+
+	client corev1client.SecretsGetter
+	lister corev1listers.SecretLister
+}
+
+// needNewCA returns the reason why the CA in secret must be replaced, or ""
+func (m *caManager) needNewCA(secret *corev1.Secret) string {
+	ca, err := libgocrypto.GetCAFromBytes(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return fmt.Sprintf("the CA can't be read: %v", err)
+	}
+	cert := ca.Config.Certs[0]
+
+	now := time.Now()
+	validity := cert.NotAfter.Sub(cert.NotBefore)
+	switch {
+	case now.After(cert.NotAfter):
+		return "the CA expired"
+	case now.After(cert.NotAfter.Add(-validity / 5)):
+		return "the CA is past 80% of its validity"
+	case now.After(cert.NotBefore.Add(m.refresh)):
+		return "the CA is past its refresh time"
+	case keyAlgorithm(cert) != caKeyAlgorithm(m.spec):
+		return fmt.Sprintf("the CA has a %s key instead of %s", keyAlgorithm(cert), caKeyAlgorithm(m.spec))
+	case validity > m.validity+time.Minute:
+		return fmt.Sprintf("the CA is valid for %s instead of %s", validity, m.validity)
+	}
+	// library-go only checks the annotations
+	if _, err := time.Parse(time.RFC3339, secret.Annotations[certrotation.CertificateNotAfterAnnotation]); err != nil {
+		return "the CA has no expiry annotation"
+	}
+	if _, err := time.Parse(time.RFC3339, secret.Annotations[certrotation.CertificateNotBeforeAnnotation]); err != nil {
+		return "the CA has no issue time annotation"
+	}
+	return ""
+}
+
+// ensureCA creates or rotates the CA Secret if needed, and waits for the
+// lister library-go reads it from to see the change
+func (m *caManager) ensureCA(ctx context.Context) error {
+	secret, err := m.lister.Secrets(m.namespace).Get(m.name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	reason := "the CA does not exist"
+	if secret != nil {
+		reason = m.needNewCA(secret)
+		if reason == "" {
+			return nil
+		}
+		secret = secret.DeepCopy()
+	} else {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: m.namespace, Name: m.name},
+			Type:       corev1.SecretTypeTLS,
+		}
+	}
+	log.Printf("Issuing a new CA in Secret %s/%s: %s", m.namespace, m.name, reason)
+
+	if err := m.setCA(secret); err != nil {
+		return err
+	}
+	var updated *corev1.Secret
+	if secret.ResourceVersion == "" {
+		updated, err = m.client.Secrets(m.namespace).Create(ctx, secret, metav1.CreateOptions{})
+	} else {
+		updated, err = m.client.Secrets(m.namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	return wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 10*time.Second, true, func(context.Context) (bool, error) {
+		cached, err := m.lister.Secrets(m.namespace).Get(m.name)
+		if err != nil {
+			return false, nil
+		}
+		return cached.ResourceVersion == updated.ResourceVersion, nil
+	})
+}
+
+// setCA stores a new self-signed CA in secret, with the annotations of
+// library-go
+func (m *caManager) setCA(secret *corev1.Secret) error {
+	algorithm := caKeyAlgorithm(m.spec)
+	publicKey, privateKey, keyID, err := newKeyPair(algorithm)
+	if err != nil {
+		return err
+	}
+	serial, err := randomSerialNumber()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("%s_%s@%d", m.namespace, m.name, now.Unix())},
+		NotBefore:             now.Add(-1 * time.Second),
+		NotAfter:              now.Add(m.validity),
+		SerialNumber:          serial,
+		KeyUsage:              keyUsage(algorithm) | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		AuthorityKeyId:        keyID,
+		SubjectKeyId:          keyID,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, publicKey, privateKey)
+	if err != nil {
+		return err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return err
+	}
+
+	certBytes := &bytes.Buffer{}
+	keyBytes := &bytes.Buffer{}
+	ca := &libgocrypto.TLSCertificateConfig{Certs: []*x509.Certificate{cert}, Key: privateKey}
+	if err := ca.WriteCertConfig(certBytes, keyBytes); err != nil {
+		return err
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[corev1.TLSCertKey] = certBytes.Bytes()
+	secret.Data[corev1.TLSPrivateKeyKey] = keyBytes.Bytes()
+	secret.Annotations[certrotation.CertificateNotAfterAnnotation] = cert.NotAfter.Format(time.RFC3339)
+	secret.Annotations[certrotation.CertificateNotBeforeAnnotation] = cert.NotBefore.Format(time.RFC3339)
+	secret.Annotations[certrotation.CertificateIssuer] = cert.Issuer.CommonName
+	certrotation.LabelAsManagedSecret(secret, certrotation.CertificateTypeSigner)
+	return nil
+}
+
+// caKeyAlgorithm returns the key algorithm of the CA, defaulted
+func caKeyAlgorithm(spec netopv1.CASpec) netopv1.KeyAlgorithm {
+	if spec.KeyAlgorithm == "" {
+		return netopv1.KeyAlgorithmRSA
+	}
+	return spec.KeyAlgorithm
+}
+
+// targetCertCreator issues the certificate signed by the CA, with the key
+// algorithm, subject alternative names and extended key usages of the spec.
+// It also rotates it when they change.
+type targetCertCreator struct {
+	spec netopv1.CertSpec
+	// validity is the configured validity, the certificate is rotated if it
+	// is valid for longer
+	validity time.Duration
+	// serving checks the rotation and the hostnames as library-go does
+	serving certrotation.ServingRotation
+}
+
+var _ certrotation.TargetCertCreator = &targetCertCreator{}
+
+func newTargetCertCreator(spec netopv1.CertSpec, validity time.Duration) *targetCertCreator {
+	hostnames := append([]string{spec.CommonName}, spec.DNSNames...)
+	hostnames = append(hostnames, spec.IPAddresses...)
+	return &targetCertCreator{
+		spec:     spec,
+		validity: validity,
+		serving: certrotation.ServingRotation{
+			Hostnames: func() []string { return hostnames },
+		},
+	}
+}
+
+// keyAlgorithm returns the key algorithm of the certificate, defaulted
+func (c *targetCertCreator) keyAlgorithm() netopv1.KeyAlgorithm {
+	if c.spec.KeyAlgorithm == "" {
+		return netopv1.KeyAlgorithmRSA
+	}
+	return c.spec.KeyAlgorithm
+}
+
+// extKeyUsages returns the extended key usages of the certificate, defaulted
+func (c *targetCertCreator) extKeyUsages() []x509.ExtKeyUsage {
+	usages := c.spec.ExtendedKeyUsages
+	if len(usages) == 0 {
+		usages = []netopv1.ExtendedKeyUsage{netopv1.ExtendedKeyUsageServerAuth, netopv1.ExtendedKeyUsageClientAuth}
+	}
+	out := []x509.ExtKeyUsage{}
+	for _, usage := range usages {
+		switch usage {
+		case netopv1.ExtendedKeyUsageServerAuth:
+			out = append(out, x509.ExtKeyUsageServerAuth)
+		case netopv1.ExtendedKeyUsageClientAuth:
+			out = append(out, x509.ExtKeyUsageClientAuth)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// NewCertificate issues a certificate signed by signer. Unlike library-go,
+// it lets the signature algorithm follow the key of the signer.
+func (c *targetCertCreator) NewCertificate(signer *libgocrypto.CA, validity time.Duration) (*libgocrypto.TLSCertificateConfig, error) {
+	algorithm := c.keyAlgorithm()
+	publicKey, privateKey, keyID, err := newKeyPair(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	ips, dnsNames := libgocrypto.IPAddressesDNSNames(c.serving.Hostnames())
+	now := time.Now()
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: c.spec.CommonName},
+		NotBefore:             now.Add(-1 * time.Second),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              keyUsage(algorithm),
+		ExtKeyUsage:           c.extKeyUsages(),
+		BasicConstraintsValid: true,
+		DNSNames:              dnsNames,
+		IPAddresses:           ips,
+		AuthorityKeyId:        signer.Config.Certs[0].SubjectKeyId,
+		SubjectKeyId:          keyID,
+	}
+	cert, err := signer.SignCertificate(template, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &libgocrypto.TLSCertificateConfig{
+		Certs: append([]*x509.Certificate{cert}, signer.Config.Certs...),
+		Key:   privateKey,
+	}, nil
+}
+
+// NeedNewTargetCertKeyPair returns why the current certificate must be
+// replaced, or "". On top of the checks of library-go, it is replaced when its
+// key algorithm, extended key usages or validity don't match the spec.
+func (c *targetCertCreator) NeedNewTargetCertKeyPair(currentCertSecret *corev1.Secret, signer *libgocrypto.CA, caBundleCerts []*x509.Certificate, refresh time.Duration, refreshOnlyWhenExpired, creationRequired bool) string {
+	if reason := c.serving.NeedNewTargetCertKeyPair(currentCertSecret, signer, caBundleCerts, refresh, refreshOnlyWhenExpired, creationRequired); reason != "" {
+		return reason
+	}
+	certs, err := libgocrypto.CertsFromPEM(currentCertSecret.Data[corev1.TLSCertKey])
+	if err != nil || len(certs) == 0 {
+		return "the certificate can't be read"
+	}
+	cert := certs[0]
+	if keyAlgorithm(cert) != c.keyAlgorithm() {
+		return fmt.Sprintf("the certificate has a %s key instead of %s", keyAlgorithm(cert), c.keyAlgorithm())
+	}
+	usages := append([]x509.ExtKeyUsage{}, cert.ExtKeyUsage...)
+	sort.Slice(usages, func(i, j int) bool { return usages[i] < usages[j] })
+	if !reflect.DeepEqual(usages, c.extKeyUsages()) {
+		return "the extended key usages of the certificate changed"
+	}
+	if validity := cert.NotAfter.Sub(cert.NotBefore); validity > c.validity+time.Minute {
+		return fmt.Sprintf("the certificate is valid for %s instead of %s", validity, c.validity)
+	}
+	return ""
+}
+
+// SetAnnotations sets the hostnames annotation, as library-go does
+func (c *targetCertCreator) SetAnnotations(cert *libgocrypto.TLSCertificateConfig, annotations map[string]string) map[string]string {
+	return c.serving.SetAnnotations(cert, annotations)
+}
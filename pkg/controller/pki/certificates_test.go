@@ -0,0 +1,134 @@
+package pki
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	netopv1 "github.com/openshift/cluster-network-operator/pkg/apis/network/v1"
+	libgocrypto "github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/certrotation"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newCA issues a CA with m, and returns its Secret and the CA
+func newCA(t *testing.T, m *caManager) (*corev1.Secret, *libgocrypto.CA) {
+	t.Helper()
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: m.namespace, Name: m.name}}
+	if err := m.setCA(secret); err != nil {
+		t.Fatal(err)
+	}
+	ca, err := libgocrypto.GetCAFromBytes(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return secret, ca
+}
+
+// newTargetSecret issues a certificate with c, and returns its Secret as
+// library-go stores it
+func newTargetSecret(t *testing.T, c *targetCertCreator, signer *libgocrypto.CA) (*corev1.Secret, *x509.Certificate) {
+	t.Helper()
+	certKeyPair, err := c.NewCertificate(signer, c.validity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{Data: map[string][]byte{}}
+	secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey], err = certKeyPair.GetPEMBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := certKeyPair.Certs[0]
+	secret.Annotations = c.SetAnnotations(certKeyPair, map[string]string{
+		certrotation.CertificateNotAfterAnnotation:  cert.NotAfter.Format(time.RFC3339),
+		certrotation.CertificateNotBeforeAnnotation: cert.NotBefore.Format(time.RFC3339),
+		certrotation.CertificateIssuer:              cert.Issuer.CommonName,
+	})
+	return secret, cert
+}
+
+func TestCAManager(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	m := &caManager{
+		namespace: "openshift-ovn-kubernetes",
+		name:      "ovn-ca",
+		spec:      netopv1.CASpec{KeyAlgorithm: netopv1.KeyAlgorithmECDSA},
+		validity:  OneYear,
+		refresh:   OneYear * 9 / 10,
+	}
+	secret, ca := newCA(t, m)
+	cert := ca.Config.Certs[0]
+	g.Expect(cert.IsCA).To(BeTrue())
+	g.Expect(cert.PublicKeyAlgorithm).To(Equal(x509.ECDSA))
+	g.Expect(cert.NotAfter.Sub(cert.NotBefore)).To(BeNumerically("~", OneYear, time.Minute))
+	g.Expect(cert.CheckSignatureFrom(cert)).To(Succeed())
+	g.Expect(secret.Annotations[certrotation.CertificateIssuer]).To(Equal(cert.Subject.CommonName))
+	g.Expect(m.needNewCA(secret)).To(BeEmpty())
+
+	// a change of the configuration rotates the CA
+	m.spec.KeyAlgorithm = netopv1.KeyAlgorithmRSA
+	g.Expect(m.needNewCA(secret)).To(Equal("the CA has a ECDSA key instead of RSA"))
+	m.spec.KeyAlgorithm = netopv1.KeyAlgorithmECDSA
+	m.validity = OneYear / 2
+	g.Expect(m.needNewCA(secret)).To(HavePrefix("the CA is valid for"))
+
+	// and so does its age
+	m.validity = OneYear
+	m.refresh = 0
+	g.Expect(m.needNewCA(secret)).To(Equal("the CA is past its refresh time"))
+}
+
+func TestTargetCertCreator(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	_, signer := newCA(t, &caManager{namespace: "openshift-ovn-kubernetes", name: "ovn-ca", validity: OneYear, refresh: OneYear})
+	spec := netopv1.CertSpec{
+		CommonName:        "ovn",
+		KeyAlgorithm:      netopv1.KeyAlgorithmECDSA,
+		DNSNames:          []string{"ovn.openshift-ovn-kubernetes.svc"},
+		IPAddresses:       []string{"192.0.2.10"},
+		ExtendedKeyUsages: []netopv1.ExtendedKeyUsage{netopv1.ExtendedKeyUsageServerAuth},
+	}
+	c := newTargetCertCreator(spec, OneYear/2)
+
+	// an ECDSA certificate signed by an RSA CA
+	secret, cert := newTargetSecret(t, c, signer)
+	g.Expect(cert.Subject.CommonName).To(Equal("ovn"))
+	g.Expect(cert.PublicKeyAlgorithm).To(Equal(x509.ECDSA))
+	// library-go also adds the IP addresses as DNS names, for older clients
+	g.Expect(cert.DNSNames).To(Equal([]string{"ovn", "ovn.openshift-ovn-kubernetes.svc", "192.0.2.10"}))
+	g.Expect(cert.IPAddresses).To(HaveLen(1))
+	g.Expect(cert.IPAddresses[0].String()).To(Equal("192.0.2.10"))
+	g.Expect(cert.ExtKeyUsage).To(Equal([]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}))
+	roots := x509.NewCertPool()
+	roots.AddCert(signer.Config.Certs[0])
+	_, err := cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: "ovn.openshift-ovn-kubernetes.svc"})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	caBundle := signer.Config.Certs
+	g.Expect(c.NeedNewTargetCertKeyPair(secret, signer, caBundle, OneYear/4, false, false)).To(BeEmpty())
+
+	// changes of the spec rotate the certificate
+	for reason, change := range map[string]func(*netopv1.CertSpec){
+		"the certificate has a ECDSA key instead of RSA":     func(s *netopv1.CertSpec) { s.KeyAlgorithm = "" },
+		"the extended key usages of the certificate changed": func(s *netopv1.CertSpec) { s.ExtendedKeyUsages = nil },
+		`"" are existing and not required, "192.0.2.11" are required and not existing`: func(s *netopv1.CertSpec) {
+			s.IPAddresses = append(s.IPAddresses, "192.0.2.11")
+		},
+	} {
+		changed := *spec.DeepCopy()
+		change(&changed)
+		g.Expect(newTargetCertCreator(changed, OneYear/2).NeedNewTargetCertKeyPair(secret, signer, caBundle, OneYear/4, false, false)).To(Equal(reason))
+	}
+	g.Expect(newTargetCertCreator(spec, OneYear/4).NeedNewTargetCertKeyPair(secret, signer, caBundle, OneYear/8, false, false)).To(
+		HavePrefix("the certificate is valid for"))
+
+	// by default, the certificate is both for servers and clients
+	_, cert = newTargetSecret(t, newTargetCertCreator(netopv1.CertSpec{CommonName: "ovn"}, OneYear/2), signer)
+	g.Expect(cert.PublicKeyAlgorithm).To(Equal(x509.RSA))
+	g.Expect(cert.ExtKeyUsage).To(Equal([]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}))
+}
@@ -0,0 +1,77 @@
+package pki
+
+import (
+	netopv1 "github.com/openshift/cluster-network-operator/pkg/apis/network/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// the certificate label values
+	caCertificate     = "ca"
+	targetCertificate = "target"
+)
+
+var (
+	pkiLabels = []string{"namespace", "name", "certificate"}
+
+	metricCertificateNotBefore = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "openshift_network_operator",
+		Name:      "pki_certificate_not_before_timestamp_seconds",
+		Help:      "The time the current certificate of an OperatorPKI is valid from, in seconds since the epoch.",
+	}, pkiLabels)
+	metricCertificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "openshift_network_operator",
+		Name:      "pki_certificate_expiry_timestamp_seconds",
+		Help:      "The time the current certificate of an OperatorPKI expires, in seconds since the epoch.",
+	}, pkiLabels)
+	metricCertificateNextRotation = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "openshift_network_operator",
+		Name:      "pki_certificate_next_rotation_timestamp_seconds",
+		Help:      "The time after which the operator rotates the current certificate of an OperatorPKI, in seconds since the epoch.",
+	}, pkiLabels)
+	metricCertificateRotations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "openshift_network_operator",
+		Name:      "pki_certificate_rotations_total",
+		Help:      "The number of times the operator rotated a certificate of an OperatorPKI.",
+	}, pkiLabels)
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		metricCertificateNotBefore,
+		metricCertificateExpiry,
+		metricCertificateNextRotation,
+		metricCertificateRotations,
+	)
+}
+
+// setCertificateMetrics reports the validity and the next rotation of the
+// certificates of a PKI. The series of a certificate that can't be read are
+// removed.
+func setCertificateMetrics(namespace, name string, status *netopv1.OperatorPKIStatus) {
+	for certificate, cert := range map[string]*netopv1.CertificateStatus{
+		caCertificate:     status.CA,
+		targetCertificate: status.TargetCert,
+	} {
+		labels := prometheus.Labels{"namespace": namespace, "name": name, "certificate": certificate}
+		if cert == nil {
+			metricCertificateNotBefore.Delete(labels)
+			metricCertificateExpiry.Delete(labels)
+			metricCertificateNextRotation.Delete(labels)
+			continue
+		}
+		metricCertificateNotBefore.With(labels).Set(float64(cert.NotBefore.Unix()))
+		metricCertificateExpiry.With(labels).Set(float64(cert.NotAfter.Unix()))
+		metricCertificateNextRotation.With(labels).Set(float64(cert.NextRotationTime.Unix()))
+	}
+}
+
+// deleteCertificateMetrics removes every series of a deleted PKI
+func deleteCertificateMetrics(namespace, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "name": name}
+	metricCertificateNotBefore.DeletePartialMatch(labels)
+	metricCertificateExpiry.DeletePartialMatch(labels)
+	metricCertificateNextRotation.DeletePartialMatch(labels)
+	metricCertificateRotations.DeletePartialMatch(labels)
+}
@@ -8,7 +8,6 @@ package pki
 
 import (
 	"context"
-	"crypto/x509"
 	"fmt"
 	"log"
 	"reflect"
@@ -22,27 +21,39 @@ import (
 	"github.com/openshift/cluster-network-operator/pkg/names"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
-	"github.com/openshift/library-go/pkg/crypto"
 	"github.com/openshift/library-go/pkg/operator/certrotation"
 	"github.com/pkg/errors"
 
 	features "github.com/openshift/api/features"
 	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
 	OneYear = 365 * 24 * time.Hour
+
+	// defaultCAValidity is the validity of the CA if the spec does not set it
+	defaultCAValidity = 10 * OneYear
+	// defaultCertValidity is the validity of the certificate if the spec does
+	// not set it
+	defaultCertValidity = OneYear / 2
+	// shortCertValidity is the validity of the certificate with the
+	// ShortCertRotation feature gate
+	shortCertValidity = 2 * time.Hour
 )
 
 // Add attaches our control loop to the manager and watches for PKI objects
@@ -59,7 +70,8 @@ func Add(mgr manager.Manager, status *statusmanager.StatusManager, _ cnoclient.C
 	}
 
 	// Watch for changes to primary resource PKI.network.operator.openshift.io/v1
-	err = c.Watch(source.Kind[crclient.Object](mgr.GetCache(), &netopv1.OperatorPKI{}, &handler.EnqueueRequestForObject{}))
+	// Status updates do not change the generation.
+	err = c.Watch(source.Kind[crclient.Object](mgr.GetCache(), &netopv1.OperatorPKI{}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{}))
 	if err != nil {
 		return err
 	}
@@ -74,13 +86,16 @@ type PKIReconciler struct {
 	mgr       manager.Manager
 	clientset *kubernetes.Clientset
 	status    *statusmanager.StatusManager
+	recorder  record.EventRecorder
 
 	// one PKI per CA
 	pkis map[types.NamespacedName]*pki
 	// For computing status
 	pkiErrs map[types.NamespacedName]error
 
-	certDuration time.Duration
+	// shortCertRotation forces the short validity of the ShortCertRotation
+	// feature gate on every certificate
+	shortCertRotation bool
 }
 
 // The periodic resync interval.
@@ -96,22 +111,37 @@ func newPKIReconciler(mgr manager.Manager, status *statusmanager.StatusManager,
 		return nil, err
 	}
 
-	certDuration := 365 * 24 * time.Hour / 2
-	if featureGates.Enabled(features.FeatureShortCertRotation) {
-		certDuration = 2 * time.Hour
-	}
-
 	return &PKIReconciler{
 		mgr:       mgr,
 		status:    status,
 		clientset: clientset,
+		recorder:  mgr.GetEventRecorderFor(eventrecorder.Component),
 
-		pkis:         map[types.NamespacedName]*pki{},
-		pkiErrs:      map[types.NamespacedName]error{},
-		certDuration: certDuration,
+		pkis:              map[types.NamespacedName]*pki{},
+		pkiErrs:           map[types.NamespacedName]error{},
+		shortCertRotation: featureGates.Enabled(features.FeatureShortCertRotation),
 	}, nil
 }
 
+// certValidity returns the validity of the certificate of spec
+func (r *PKIReconciler) certValidity(spec *netopv1.OperatorPKISpec) time.Duration {
+	switch {
+	case r.shortCertRotation:
+		return shortCertValidity
+	case spec.TargetCert.Validity != nil:
+		return spec.TargetCert.Validity.Duration
+	}
+	return defaultCertValidity
+}
+
+// caValidity returns the validity of the CA of spec
+func caValidity(spec netopv1.CASpec) time.Duration {
+	if spec.Validity != nil {
+		return spec.Validity.Duration
+	}
+	return defaultCAValidity
+}
+
 // Reconcile configures a CertRotationController from a PKI object
 func (r *PKIReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
 	defer utilruntime.HandleCrash(r.status.SetDegradedOnPanicAndCrash)
@@ -122,24 +152,42 @@ func (r *PKIReconciler) Reconcile(ctx context.Context, request reconcile.Request
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Printf("PKI %s seems to have been deleted\n", request.NamespacedName)
+			if existing := r.pkis[request.NamespacedName]; existing != nil {
+				existing.stop()
+				delete(r.pkis, request.NamespacedName)
+			}
+			deleteCertificateMetrics(request.Namespace, request.Name)
 			return reconcile.Result{}, nil
 		}
 		log.Println(err)
 		return reconcile.Result{}, err
 	}
 
-	// Check to see if we already know this object
+	// Check to see if we already know this object. If the spec has changed,
+	// the existing PKI keeps being synced until the new spec is valid.
 	existing := r.pkis[request.NamespacedName]
-	if existing != nil {
-		// If the spec has changed, refresh
-		if !reflect.DeepEqual(obj.Spec, existing.spec) {
+	if existing == nil || !reflect.DeepEqual(obj.Spec, existing.spec) {
+		if errs := validatePKISpec(&obj.Spec); len(errs) > 0 {
+			err := errs.ToAggregate()
+			log.Printf("Invalid PKI %s: %v", request.NamespacedName, err)
+			r.pkiErrs[request.NamespacedName] = errors.Wrapf(err, "invalid PKI %s", request.NamespacedName)
+			r.setStatus()
+			if existing == nil {
+				r.setPKIStatus(ctx, obj, metav1.ConditionTrue, "InvalidSpec", err.Error(), nil)
+				// Nothing to retry until the spec changes
+				return reconcile.Result{}, nil
+			}
+			if syncErr := existing.sync(); syncErr != nil {
+				log.Printf("Failed to reconcile the previous spec of PKI %s: %v", request.NamespacedName, syncErr)
+			}
+			r.setPKIStatus(ctx, obj, metav1.ConditionTrue, "InvalidSpec", err.Error(), existing)
+			return reconcile.Result{RequeueAfter: ResyncPeriod}, nil
+		}
+
+		if existing != nil {
 			log.Printf("PKI %s has changed, refreshing\n", request.NamespacedName)
-			delete(r.pkis, request.NamespacedName)
-			existing = nil
 		}
-	}
-	if existing == nil {
-		existing, err = newPKI(obj, r.clientset, r.mgr, r.certDuration)
+		updated, err := newPKI(obj, r.clientset, r.mgr, r.certValidity(&obj.Spec), eventrecorder.NewObjectRecorder(r.recorder, obj))
 		if err != nil {
 			log.Println(err)
 			r.pkiErrs[request.NamespacedName] =
@@ -147,6 +195,10 @@ func (r *PKIReconciler) Reconcile(ctx context.Context, request reconcile.Request
 			r.setStatus()
 			return reconcile.Result{}, err
 		}
+		if existing != nil {
+			existing.stop()
+		}
+		existing = updated
 		r.pkis[request.NamespacedName] = existing
 	}
 
@@ -156,15 +208,43 @@ func (r *PKIReconciler) Reconcile(ctx context.Context, request reconcile.Request
 		r.pkiErrs[request.NamespacedName] =
 			errors.Wrapf(err, "could not reconcile PKI %s", request.NamespacedName)
 		r.setStatus()
+		r.setPKIStatus(ctx, obj, metav1.ConditionTrue, "SyncFailed", err.Error(), existing)
 		return reconcile.Result{}, err
 	}
 
 	log.Println("successful reconciliation")
 	delete(r.pkiErrs, request.NamespacedName)
 	r.setStatus()
+	r.setPKIStatus(ctx, obj, metav1.ConditionFalse, "AsExpected", "", existing)
 	return reconcile.Result{RequeueAfter: ResyncPeriod}, nil
 }
 
+// setPKIStatus sets the Degraded condition of obj and, if p is not nil, the
+// status and metrics of its certificates. Failures are only logged, they are
+// retried with the next reconcile.
+func (r *PKIReconciler) setPKIStatus(ctx context.Context, obj *netopv1.OperatorPKI, degraded metav1.ConditionStatus, reason, message string, p *pki) {
+	status := obj.Status.DeepCopy()
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               "Degraded",
+		Status:             degraded,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: obj.Generation,
+	})
+	if p != nil {
+		status.CA, status.TargetCert = p.certificateStatus(&obj.Status)
+		status.CABundleConfigMapName = p.name + "-ca"
+		setCertificateMetrics(obj.Namespace, obj.Name, status)
+	}
+	if reflect.DeepEqual(*status, obj.Status) {
+		return
+	}
+	obj.Status = *status
+	if err := r.mgr.GetClient().Status().Update(ctx, obj); err != nil {
+		log.Printf("Failed to update the status of PKI %s/%s: %v", obj.Namespace, obj.Name, err)
+	}
+}
+
 // setStatus summarizes the status of all PKI objects and updates the statusmanager
 // as appropriate.
 func (r *PKIReconciler) setStatus() {
@@ -184,10 +264,22 @@ func (r *PKIReconciler) setStatus() {
 type pki struct {
 	spec       netopv1.OperatorPKISpec
 	controller factory.Controller
+
+	clientset *kubernetes.Clientset
+	namespace string
+	name      string
+	// recorder emits the events on the PKI object
+	recorder *eventrecorder.ObjectRecorder
+	// ca manages the CA Secret
+	ca *caManager
+	// certRefresh is the refresh time of the certificate
+	certRefresh time.Duration
+	// stopCh stops the informers of the PKI when closed
+	stopCh chan struct{}
 }
 
 // newPKI creates a CertRotationController for the supplied configuration
-func newPKI(config *netopv1.OperatorPKI, clientset *kubernetes.Clientset, mgr manager.Manager, certDuration time.Duration) (*pki, error) {
+func newPKI(config *netopv1.OperatorPKI, clientset *kubernetes.Clientset, mgr manager.Manager, certValidity time.Duration, recorder *eventrecorder.ObjectRecorder) (*pki, error) {
 	spec := config.Spec
 
 	// Ugly: the existing cache + informers used as part of the controller-manager
@@ -201,6 +293,18 @@ func newPKI(config *netopv1.OperatorPKI, clientset *kubernetes.Clientset, mgr ma
 		24*time.Hour,
 		informers.WithNamespace(config.Namespace))
 
+	validity := caValidity(spec.CA)
+	certRefresh := certValidity / 2
+	ca := &caManager{
+		namespace: config.Namespace,
+		name:      config.Name + "-ca",
+		validity:  validity,
+		refresh:   validity * 9 / 10,
+		client:    clientset.CoreV1(),
+		lister:    inf.Core().V1().Secrets().Lister(),
+	}
+	spec.CA.DeepCopyInto(&ca.spec)
+
 	cont := certrotation.NewCertRotationController(
 		fmt.Sprintf("%s/%s", config.Namespace, config.Name), // name, not really used
 		certrotation.RotatedSigningCASecret{
@@ -209,12 +313,13 @@ func newPKI(config *netopv1.OperatorPKI, clientset *kubernetes.Clientset, mgr ma
 			AdditionalAnnotations: certrotation.AdditionalAnnotations{
 				JiraComponent: names.ClusterNetworkOperatorJiraComponent,
 			},
-			Validity:      10 * OneYear,
-			Refresh:       9 * OneYear,
+			// The CA is issued by the caManager
+			Validity:      ca.validity,
+			Refresh:       ca.refresh,
 			Informer:      inf.Core().V1().Secrets(),
 			Lister:        inf.Core().V1().Secrets().Lister(),
 			Client:        clientset.CoreV1(),
-			EventRecorder: &eventrecorder.LoggingRecorder{},
+			EventRecorder: recorder,
 		},
 		certrotation.CABundleConfigMap{
 			Namespace: config.Namespace,
@@ -225,7 +330,7 @@ func newPKI(config *netopv1.OperatorPKI, clientset *kubernetes.Clientset, mgr ma
 			Lister:        inf.Core().V1().ConfigMaps().Lister(),
 			Informer:      inf.Core().V1().ConfigMaps(),
 			Client:        clientset.CoreV1(),
-			EventRecorder: &eventrecorder.LoggingRecorder{},
+			EventRecorder: recorder,
 		},
 		certrotation.RotatedSelfSignedCertKeySecret{
 			Namespace: config.Namespace,
@@ -233,61 +338,98 @@ func newPKI(config *netopv1.OperatorPKI, clientset *kubernetes.Clientset, mgr ma
 			AdditionalAnnotations: certrotation.AdditionalAnnotations{
 				JiraComponent: names.ClusterNetworkOperatorJiraComponent,
 			},
-			Validity: certDuration,
-			Refresh:  certDuration / 2,
-			CertCreator: &certrotation.ServingRotation{
-				Hostnames: func() []string { return []string{spec.TargetCert.CommonName} },
-
-				// Force the certificate to also be client
-				CertificateExtensionFn: []crypto.CertificateExtensionFunc{
-					toClientCert,
-				},
-			},
+			Validity:      certValidity,
+			Refresh:       certRefresh,
+			CertCreator:   newTargetCertCreator(spec.TargetCert, certValidity),
 			Lister:        inf.Core().V1().Secrets().Lister(),
 			Informer:      inf.Core().V1().Secrets(),
 			Client:        clientset.CoreV1(),
-			EventRecorder: &eventrecorder.LoggingRecorder{},
+			EventRecorder: recorder,
 		},
-		&eventrecorder.LoggingRecorder{},
+		recorder,
 		nil,
 	)
 
 	out := &pki{
-		controller: cont,
+		controller:  cont,
+		clientset:   clientset,
+		namespace:   config.Namespace,
+		name:        config.Name,
+		recorder:    recorder,
+		ca:          ca,
+		certRefresh: certRefresh,
+		stopCh:      make(chan struct{}),
 	}
 	config.Spec.DeepCopyInto(&out.spec)
 
-	ch := make(chan struct{})
-	inf.Start(ch)
-	inf.WaitForCacheSync(ch)
+	inf.Start(out.stopCh)
+	inf.WaitForCacheSync(out.stopCh)
 
 	return out, nil
 }
 
-// sync causes the underlying cert controller to try and reconcile
+// stop stops the informers of the PKI, once it is replaced or deleted
+func (p *pki) stop() {
+	close(p.stopCh)
+}
+
+// sync causes the underlying cert controller to try and reconcile, and emits
+// an event for every certificate it issued or rotated
 func (p *pki) sync() error {
+	secrets := []string{p.name + "-ca", p.name + "-cert"}
+	certificates := []string{caCertificate, targetCertificate}
+	before := p.certificateExpiries(secrets)
+
 	runOnceCtx := context.WithValue(context.Background(), certrotation.RunOnceContextKey, true) //nolint:staticcheck
-	return p.controller.Sync(runOnceCtx, nil)
-}
+	err := p.ca.ensureCA(runOnceCtx)
+	if err == nil {
+		err = p.controller.Sync(runOnceCtx, nil)
+	}
 
-// toClientCert is a certificate "decorator" that adds ClientAuth to the
-// list of ExtendedKeyUsages. This allows the generated certificate to be
-// used for both client and server auth.
-func toClientCert(cert *x509.Certificate) error {
-	if len(cert.ExtKeyUsage) == 0 {
-		return nil
+	after := p.certificateExpiries(secrets)
+	for i, name := range secrets {
+		switch {
+		case after[name] == "" || after[name] == before[name]:
+		case before[name] == "":
+			p.recorder.Eventf("CertificateIssued", "Issued the certificate of Secret %s/%s, valid until %s", p.namespace, name, after[name])
+		default:
+			p.recorder.Eventf("CertificateRotated", "Rotated the certificate of Secret %s/%s, now valid until %s", p.namespace, name, after[name])
+			metricCertificateRotations.WithLabelValues(p.namespace, p.name, certificates[i]).Inc()
+		}
 	}
+	return err
+}
 
-	found := false
-	for _, u := range cert.ExtKeyUsage {
-		if u == x509.ExtKeyUsageClientAuth {
-			found = true
-			break
+// certificateExpiries returns the expiry of the certificate stored in each of
+// the given Secrets, or "" if it can't be retrieved
+func (p *pki) certificateExpiries(secrets []string) map[string]string {
+	out := map[string]string{}
+	for _, name := range secrets {
+		secret, err := p.clientset.CoreV1().Secrets(p.namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				log.Printf("Failed to get Secret %s/%s: %v", p.namespace, name, err)
+			}
+			continue
 		}
+		out[name] = secret.Annotations[certrotation.CertificateNotAfterAnnotation]
 	}
+	return out
+}
 
-	if !found {
-		cert.ExtKeyUsage = append(cert.ExtKeyUsage, x509.ExtKeyUsageClientAuth)
+// certificateStatus returns the status of the CA and of the certificate, read
+// from their Secrets. previous is the last reported status of the PKI.
+func (p *pki) certificateStatus(previous *netopv1.OperatorPKIStatus) (ca, cert *netopv1.CertificateStatus) {
+	now := time.Now()
+	get := func(name string, refresh time.Duration, previous *netopv1.CertificateStatus) *netopv1.CertificateStatus {
+		secret, err := p.clientset.CoreV1().Secrets(p.namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				log.Printf("Failed to get Secret %s/%s: %v", p.namespace, name, err)
+			}
+			return nil
+		}
+		return certificateStatus(secret, refresh, previous, now)
 	}
-	return nil
+	return get(p.name+"-ca", p.ca.refresh, previous.CA), get(p.name+"-cert", p.certRefresh, previous.TargetCert)
 }
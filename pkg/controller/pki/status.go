@@ -0,0 +1,61 @@
+package pki
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"time"
+
+	netopv1 "github.com/openshift/cluster-network-operator/pkg/apis/network/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+// certificateStatus returns the status of the certificate stored in secret,
+// or nil if it can't be read. refresh is the refresh time of the certificate,
+// and previous its last reported status, if any.
+func certificateStatus(secret *corev1.Secret, refresh time.Duration, previous *netopv1.CertificateStatus, now time.Time) *netopv1.CertificateStatus {
+	certs, err := certutil.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return nil
+	}
+	cert := certs[0]
+
+	status := &netopv1.CertificateStatus{
+		SecretName:       secret.Name,
+		NotBefore:        metav1.NewTime(cert.NotBefore),
+		NotAfter:         metav1.NewTime(cert.NotAfter),
+		Fingerprint:      fingerprint(cert.Raw),
+		LastRotationTime: metav1.NewTime(cert.NotBefore),
+		NextRotationTime: metav1.NewTime(nextRotationTime(cert.NotBefore, cert.NotAfter, refresh)),
+	}
+	switch {
+	case previous == nil || previous.Fingerprint == "":
+	case previous.Fingerprint == status.Fingerprint:
+		status.LastRotationTime = previous.LastRotationTime
+	default:
+		status.LastRotationTime = metav1.NewTime(now)
+	}
+	return status
+}
+
+// nextRotationTime returns the time a certificate is rotated: after its
+// refresh time, or after 80% of its validity, whichever comes first
+func nextRotationTime(notBefore, notAfter time.Time, refresh time.Duration) time.Time {
+	next := notAfter.Add(-notAfter.Sub(notBefore) / 5)
+	if refreshTime := notBefore.Add(refresh); refreshTime.Before(next) {
+		return refreshTime
+	}
+	return next
+}
+
+// fingerprint returns the SHA-256 fingerprint of a DER certificate
+func fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	bytes := make([]string, 0, len(sum))
+	for _, b := range sum {
+		bytes = append(bytes, fmt.Sprintf("%02X", b))
+	}
+	return strings.Join(bytes, ":")
+}
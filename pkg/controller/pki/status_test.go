@@ -0,0 +1,102 @@
+package pki
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	netopv1 "github.com/openshift/cluster-network-operator/pkg/apis/network/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCertificateStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	m := &caManager{
+		namespace: "openshift-ovn-kubernetes",
+		name:      "ovn-ca",
+		validity:  OneYear,
+		refresh:   OneYear * 9 / 10,
+	}
+	secret, ca := newCA(t, m)
+	cert := ca.Config.Certs[0]
+	now := time.Now()
+
+	status := certificateStatus(secret, m.refresh, nil, now)
+	g.Expect(status).NotTo(BeNil())
+	g.Expect(status.SecretName).To(Equal("ovn-ca"))
+	g.Expect(status.NotBefore.Time).To(BeTemporally("==", cert.NotBefore))
+	g.Expect(status.NotAfter.Time).To(BeTemporally("==", cert.NotAfter))
+	sum := sha256.Sum256(cert.Raw)
+	g.Expect(strings.ReplaceAll(status.Fingerprint, ":", "")).To(Equal(strings.ToUpper(hex.EncodeToString(sum[:]))))
+	// the CA is rotated at 80% of its validity, before its refresh time
+	g.Expect(status.NextRotationTime.Time).To(BeTemporally("==", cert.NotAfter.Add(-cert.NotAfter.Sub(cert.NotBefore)/5)))
+	// a certificate reported for the first time was last rotated when issued
+	g.Expect(status.LastRotationTime.Time).To(BeTemporally("==", cert.NotBefore))
+
+	// the rotation time is kept while the certificate does not change
+	previous := status.DeepCopy()
+	previous.LastRotationTime = metav1.NewTime(cert.NotBefore.Add(-time.Hour))
+	status = certificateStatus(secret, m.refresh, previous, now)
+	g.Expect(status.LastRotationTime).To(Equal(previous.LastRotationTime))
+
+	// and updated when it does
+	rotated, _ := newCA(t, m)
+	status = certificateStatus(rotated, m.refresh, previous, now)
+	g.Expect(status.Fingerprint).NotTo(Equal(previous.Fingerprint))
+	g.Expect(status.LastRotationTime.Time).To(BeTemporally("==", now))
+
+	// or when it was reported before fingerprints were
+	status = certificateStatus(rotated, m.refresh, &netopv1.CertificateStatus{NotAfter: previous.NotAfter}, now)
+	g.Expect(status.LastRotationTime).To(Equal(status.NotBefore))
+
+	g.Expect(certificateStatus(&corev1.Secret{}, m.refresh, nil, now)).To(BeNil())
+}
+
+func TestNextRotationTime(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	notBefore := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := notBefore.Add(10 * time.Hour)
+	g.Expect(nextRotationTime(notBefore, notAfter, 5*time.Hour)).To(Equal(notBefore.Add(5 * time.Hour)))
+	g.Expect(nextRotationTime(notBefore, notAfter, 9*time.Hour)).To(Equal(notBefore.Add(8 * time.Hour)))
+}
+
+func TestCertificateMetrics(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	notBefore := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	status := &netopv1.OperatorPKIStatus{
+		CA: &netopv1.CertificateStatus{
+			NotBefore:        metav1.NewTime(notBefore),
+			NotAfter:         metav1.NewTime(notBefore.Add(10 * OneYear)),
+			NextRotationTime: metav1.NewTime(notBefore.Add(8 * OneYear)),
+		},
+		TargetCert: &netopv1.CertificateStatus{
+			NotBefore:        metav1.NewTime(notBefore),
+			NotAfter:         metav1.NewTime(notBefore.Add(OneYear / 2)),
+			NextRotationTime: metav1.NewTime(notBefore.Add(OneYear / 4)),
+		},
+	}
+	setCertificateMetrics("openshift-ovn-kubernetes", "ovn", status)
+	g.Expect(testutil.ToFloat64(metricCertificateExpiry.WithLabelValues("openshift-ovn-kubernetes", "ovn", targetCertificate))).To(
+		Equal(float64(notBefore.Add(OneYear / 2).Unix())))
+	g.Expect(testutil.ToFloat64(metricCertificateNextRotation.WithLabelValues("openshift-ovn-kubernetes", "ovn", caCertificate))).To(
+		Equal(float64(notBefore.Add(8 * OneYear).Unix())))
+
+	// the series of a certificate that can't be read are removed
+	status.TargetCert = nil
+	setCertificateMetrics("openshift-ovn-kubernetes", "ovn", status)
+	g.Expect(testutil.CollectAndCount(metricCertificateExpiry)).To(Equal(1))
+
+	deleteCertificateMetrics("openshift-ovn-kubernetes", "ovn")
+	g.Expect(testutil.CollectAndCount(metricCertificateNotBefore)).To(Equal(0))
+	g.Expect(testutil.CollectAndCount(metricCertificateExpiry)).To(Equal(0))
+	g.Expect(testutil.CollectAndCount(metricCertificateNextRotation)).To(Equal(0))
+}
@@ -0,0 +1,77 @@
+package pki
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	netopv1 "github.com/openshift/cluster-network-operator/pkg/apis/network/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// minValidity is the shortest validity of a certificate
+const minValidity = time.Hour
+
+// validatePKISpec validates what the CRD schema does not
+func validatePKISpec(spec *netopv1.OperatorPKISpec) field.ErrorList {
+	specPath := field.NewPath("spec")
+	errs := field.ErrorList{}
+
+	caPath := specPath.Child("ca")
+	errs = append(errs, validateValidity(caPath.Child("validity"), spec.CA.Validity)...)
+	errs = append(errs, validateKeyAlgorithm(caPath.Child("keyAlgorithm"), spec.CA.KeyAlgorithm)...)
+
+	certPath := specPath.Child("targetCert")
+	cert := &spec.TargetCert
+	errs = append(errs, validateValidity(certPath.Child("validity"), cert.Validity)...)
+	if cert.Validity != nil && cert.Validity.Duration >= caValidity(spec.CA) {
+		errs = append(errs, field.Invalid(certPath.Child("validity"), cert.Validity.Duration.String(),
+			fmt.Sprintf("must be shorter than the validity of the CA (%s)", caValidity(spec.CA))))
+	}
+	errs = append(errs, validateKeyAlgorithm(certPath.Child("keyAlgorithm"), cert.KeyAlgorithm)...)
+
+	for i, name := range cert.DNSNames {
+		var msgs []string
+		if strings.HasPrefix(name, "*.") {
+			msgs = validation.IsWildcardDNS1123Subdomain(name)
+		} else {
+			msgs = validation.IsDNS1123Subdomain(name)
+		}
+		for _, msg := range msgs {
+			errs = append(errs, field.Invalid(certPath.Child("dnsNames").Index(i), name, msg))
+		}
+	}
+	for i, ip := range cert.IPAddresses {
+		if net.ParseIP(ip) == nil {
+			errs = append(errs, field.Invalid(certPath.Child("ipAddresses").Index(i), ip, "must be an IP address"))
+		}
+	}
+	for i, usage := range cert.ExtendedKeyUsages {
+		if usage != netopv1.ExtendedKeyUsageServerAuth && usage != netopv1.ExtendedKeyUsageClientAuth {
+			errs = append(errs, field.NotSupported(certPath.Child("extendedKeyUsages").Index(i), usage,
+				[]string{string(netopv1.ExtendedKeyUsageServerAuth), string(netopv1.ExtendedKeyUsageClientAuth)}))
+		}
+	}
+	return errs
+}
+
+// validateValidity validates an optional validity
+func validateValidity(path *field.Path, validity *metav1.Duration) field.ErrorList {
+	if validity == nil || validity.Duration >= minValidity {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(path, validity.Duration.String(), fmt.Sprintf("must be at least %s", minValidity))}
+}
+
+// validateKeyAlgorithm validates an optional key algorithm
+func validateKeyAlgorithm(path *field.Path, algorithm netopv1.KeyAlgorithm) field.ErrorList {
+	switch algorithm {
+	case "", netopv1.KeyAlgorithmRSA, netopv1.KeyAlgorithmECDSA:
+		return nil
+	}
+	return field.ErrorList{field.NotSupported(path, algorithm,
+		[]string{string(netopv1.KeyAlgorithmRSA), string(netopv1.KeyAlgorithmECDSA)})}
+}
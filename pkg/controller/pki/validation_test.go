@@ -0,0 +1,60 @@
+package pki
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	netopv1 "github.com/openshift/cluster-network-operator/pkg/apis/network/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidatePKISpec(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	spec := &netopv1.OperatorPKISpec{
+		CA: netopv1.CASpec{
+			Validity:     &metav1.Duration{Duration: 3 * OneYear},
+			KeyAlgorithm: netopv1.KeyAlgorithmECDSA,
+		},
+		TargetCert: netopv1.CertSpec{
+			CommonName:        "ovn",
+			Validity:          &metav1.Duration{Duration: OneYear / 4},
+			KeyAlgorithm:      netopv1.KeyAlgorithmECDSA,
+			DNSNames:          []string{"ovn.openshift-ovn-kubernetes.svc", "*.ovn.example.com"},
+			IPAddresses:       []string{"192.0.2.10", "2001:db8::10"},
+			ExtendedKeyUsages: []netopv1.ExtendedKeyUsage{netopv1.ExtendedKeyUsageClientAuth},
+		},
+	}
+	g.Expect(validatePKISpec(spec)).To(BeEmpty())
+	g.Expect(validatePKISpec(&netopv1.OperatorPKISpec{TargetCert: netopv1.CertSpec{CommonName: "ovn"}})).To(BeEmpty())
+
+	spec.CA.Validity.Duration = OneYear / 8
+	spec.CA.KeyAlgorithm = "DSA"
+	spec.TargetCert.Validity.Duration = 0
+	spec.TargetCert.DNSNames = []string{"Not_A_Name"}
+	spec.TargetCert.IPAddresses = []string{"192.0.2.256"}
+	spec.TargetCert.ExtendedKeyUsages = []netopv1.ExtendedKeyUsage{"CodeSigning"}
+	messages := []string{}
+	for _, err := range validatePKISpec(spec) {
+		messages = append(messages, err.Error())
+	}
+	g.Expect(messages).To(ConsistOf(
+		`spec.ca.keyAlgorithm: Unsupported value: "DSA": supported values: "RSA", "ECDSA"`,
+		`spec.targetCert.validity: Invalid value: "0s": must be at least 1h0m0s`,
+		`spec.targetCert.dnsNames[0]: Invalid value: "Not_A_Name": a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`,
+		`spec.targetCert.ipAddresses[0]: Invalid value: "192.0.2.256": must be an IP address`,
+		`spec.targetCert.extendedKeyUsages[0]: Unsupported value: "CodeSigning": supported values: "ServerAuth", "ClientAuth"`,
+	))
+
+	// the certificate must expire before the CA
+	spec = &netopv1.OperatorPKISpec{
+		CA:         netopv1.CASpec{Validity: &metav1.Duration{Duration: OneYear / 4}},
+		TargetCert: netopv1.CertSpec{CommonName: "ovn", Validity: &metav1.Duration{Duration: OneYear / 2}},
+	}
+	messages = []string{}
+	for _, err := range validatePKISpec(spec) {
+		messages = append(messages, err.Error())
+	}
+	g.Expect(messages).To(ConsistOf(`spec.targetCert.validity: Invalid value: "4380h0m0s": must be shorter than the validity of the CA (2190h0m0s)`))
+}
@@ -0,0 +1,113 @@
+package statusmanager
+
+import (
+	"strings"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// migrationConditionTypes are the conditions of the operator configuration
+// whose changes are reported as migration phase changes.
+var migrationConditionTypes = []string{
+	names.ServiceNetworkMigrationInProgress,
+	names.ServiceNetworkMigrationTargetAdded,
+	names.ServiceNetworkMigrationConsumersMigrated,
+	names.ServiceNetworkMigrationOriginalRemoved,
+}
+
+// eventf emits an event on the operator configuration. It does nothing until
+// SetEventRecorder is called.
+func (status *StatusManager) eventf(eventtype, reason, messageFmt string, args ...interface{}) {
+	if status.recorder == nil {
+		return
+	}
+	oc := &operv1.Network{
+		TypeMeta:   metav1.TypeMeta{APIVersion: operv1.GroupVersion.String(), Kind: "Network"},
+		ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG, UID: status.operConfigUID},
+	}
+	status.recorder.Eventf(oc, eventtype, reason, messageFmt, args...)
+}
+
+// recordConditionEvents emits events for the transitions between the old and
+// new conditions of the operator configuration that on-call cares about.
+func (status *StatusManager) recordConditionEvents(oldConditions, newConditions []operv1.OperatorCondition) {
+	oldDegraded := v1helpers.FindOperatorCondition(oldConditions, operv1.OperatorStatusTypeDegraded)
+	newDegraded := v1helpers.FindOperatorCondition(newConditions, operv1.OperatorStatusTypeDegraded)
+	wasDegraded := oldDegraded != nil && oldDegraded.Status == operv1.ConditionTrue
+	if newDegraded != nil && newDegraded.Status == operv1.ConditionTrue {
+		if !wasDegraded || oldDegraded.Reason != newDegraded.Reason {
+			status.eventf(corev1.EventTypeWarning, "OperatorDegraded", "%s: %s", newDegraded.Reason, newDegraded.Message)
+		}
+	} else if wasDegraded && newDegraded != nil {
+		status.eventf(corev1.EventTypeNormal, "OperatorRecovered", "The operator is no longer degraded")
+	}
+
+	wasAvailable := v1helpers.IsOperatorConditionTrue(oldConditions, operv1.OperatorStatusTypeAvailable)
+	newAvailable := v1helpers.FindOperatorCondition(newConditions, operv1.OperatorStatusTypeAvailable)
+	if newAvailable != nil && newAvailable.Status == operv1.ConditionTrue && !wasAvailable {
+		status.eventf(corev1.EventTypeNormal, "OperatorAvailable", "The network is available")
+	} else if newAvailable != nil && newAvailable.Status != operv1.ConditionTrue && wasAvailable {
+		status.eventf(corev1.EventTypeWarning, "OperatorUnavailable", "%s: %s", newAvailable.Reason, newAvailable.Message)
+	}
+
+	RecordMigrationPhaseChanges(status.eventf, migrationConditionTypes, asConditions(oldConditions), asConditions(newConditions))
+}
+
+// RecordMigrationPhaseChanges emits a MigrationPhaseChanged event with eventf
+// for every condition of conditionTypes whose status changes from
+// oldConditions to newConditions.
+func RecordMigrationPhaseChanges(eventf func(eventtype, reason, messageFmt string, args ...interface{}),
+	conditionTypes []string, oldConditions, newConditions []metav1.Condition) {
+	for _, conditionType := range conditionTypes {
+		oldCondition := meta.FindStatusCondition(oldConditions, conditionType)
+		newCondition := meta.FindStatusCondition(newConditions, conditionType)
+		if newCondition == nil || (oldCondition != nil && oldCondition.Status == newCondition.Status) {
+			continue
+		}
+		eventf(corev1.EventTypeNormal, "MigrationPhaseChanged", "%s is %s: %s", conditionType, newCondition.Status,
+			strings.TrimSpace(newCondition.Reason+" "+newCondition.Message))
+	}
+}
+
+// asConditions converts operator conditions to conditions, for the helpers
+// shared with the cluster configuration.
+func asConditions(operatorConditions []operv1.OperatorCondition) []metav1.Condition {
+	conditions := make([]metav1.Condition, 0, len(operatorConditions))
+	for _, c := range operatorConditions {
+		conditions = append(conditions, metav1.Condition{
+			Type:    c.Type,
+			Status:  metav1.ConditionStatus(c.Status),
+			Reason:  c.Reason,
+			Message: c.Message,
+		})
+	}
+	return conditions
+}
+
+// recordRolloutEvents emits events when a rollout of the DaemonSets,
+// Deployments and StatefulSets starts, finishes, or gets hung.
+func (status *StatusManager) recordRolloutEvents(wasProgressing, wasHung bool, progressing, hung []string) {
+	if len(progressing) > 0 && !wasProgressing {
+		status.eventf(corev1.EventTypeNormal, "RolloutStarted", "Rolling out: %s", strings.Join(progressing, "; "))
+	} else if len(progressing) == 0 && wasProgressing {
+		status.eventf(corev1.EventTypeNormal, "RolloutFinished", "Every DaemonSet, Deployment and StatefulSet is rolled out")
+	}
+	if len(hung) > 0 && !wasHung {
+		status.eventf(corev1.EventTypeWarning, "RolloutHung", "%s", strings.Join(hung, "; "))
+	}
+}
+
+// SetEventRecorder sets the recorder of the events emitted on the operator
+// configuration when the operator status changes.
+func (status *StatusManager) SetEventRecorder(recorder record.EventRecorder) {
+	status.Lock()
+	defer status.Unlock()
+	status.recorder = recorder
+}
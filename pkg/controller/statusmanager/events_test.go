@@ -0,0 +1,85 @@
+package statusmanager
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-network-operator/pkg/client/fake"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// recordedEvents drains the events recorded so far
+func recordedEvents(recorder *record.FakeRecorder) []string {
+	events := []string{}
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestStatusManagerEvents(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	client := fake.NewFakeClient()
+	status := New(client, "testing", names.StandAloneClusterName)
+	setFakeListers(status)
+	setOC(t, client, &operv1.Network{ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG}})
+	recorder := record.NewFakeRecorder(100)
+	status.SetEventRecorder(recorder)
+
+	// a rollout starts
+	depA := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "one", Name: "alpha", Labels: sl}}
+	set(t, client, depA)
+	status.SetFromPods()
+	g.Expect(recordedEvents(recorder)).To(ContainElement(
+		`Normal RolloutStarted Rolling out: Deployment "/one/alpha" is not yet scheduled on any nodes`))
+
+	// nothing new is emitted while it goes on
+	status.SetFromPods()
+	g.Expect(recordedEvents(recorder)).To(BeEmpty())
+
+	// and it finishes
+	depA.Status.Replicas = 1
+	depA.Status.UpdatedReplicas = 1
+	depA.Status.AvailableReplicas = 1
+	setStatus(t, client, depA)
+	status.SetFromPods()
+	g.Expect(recordedEvents(recorder)).To(ConsistOf(
+		"Normal RolloutFinished Every DaemonSet, Deployment and StatefulSet is rolled out",
+		"Normal OperatorAvailable The network is available",
+	))
+
+	// the operator degrades, and recovers
+	status.SetDegraded(OperatorConfig, "InvalidOperatorConfig", "bad")
+	status.SetDegraded(OperatorConfig, "InvalidOperatorConfig", "still bad")
+	status.SetNotDegraded(OperatorConfig)
+	g.Expect(recordedEvents(recorder)).To(Equal([]string{
+		"Warning OperatorDegraded InvalidOperatorConfig: bad",
+		"Normal OperatorRecovered The operator is no longer degraded",
+	}))
+
+	// a migration moves to its next phase
+	status.SetServiceNetworkMigrationConditions([]operv1.OperatorCondition{
+		{Type: names.ServiceNetworkMigrationInProgress, Status: operv1.ConditionTrue, Reason: "MigrationStarted"},
+		{Type: names.ServiceNetworkMigrationTargetAdded, Status: operv1.ConditionFalse, Reason: "AddingTarget"},
+	})
+	status.SetServiceNetworkMigrationConditions([]operv1.OperatorCondition{
+		{Type: names.ServiceNetworkMigrationInProgress, Status: operv1.ConditionTrue, Reason: "MigrationStarted"},
+		{Type: names.ServiceNetworkMigrationTargetAdded, Status: operv1.ConditionTrue, Reason: "TargetAdded"},
+	})
+	g.Expect(recordedEvents(recorder)).To(Equal([]string{
+		"Normal MigrationPhaseChanged ServiceNetworkMigrationInProgress is True: MigrationStarted",
+		"Normal MigrationPhaseChanged ServiceNetworkMigrationTargetAdded is False: AddingTarget",
+		"Normal MigrationPhaseChanged ServiceNetworkMigrationTargetAdded is True: TargetAdded",
+	}))
+}
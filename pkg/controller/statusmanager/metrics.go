@@ -0,0 +1,48 @@
+package statusmanager
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var metricStatusCondition = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "openshift_network_operator",
+	Name:      "status_condition",
+	Help: "A metric with a constant '1' value for each reason the operator is currently Degraded or Progressing, " +
+		"labeled with the condition type and reason. Nothing is reported for a condition that is not true.",
+}, []string{"condition", "reason"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(metricStatusCondition)
+}
+
+// DisableMetrics stops the StatusManager from reporting the status_condition
+// metric, which describes a single cluster. It is used for the extra clusters,
+// so that they do not overwrite the metric of the cluster the operator runs in.
+func (status *StatusManager) DisableMetrics() {
+	status.Lock()
+	defer status.Unlock()
+	status.metricsDisabled = true
+}
+
+// MetricsDisabled returns true if the metrics that describe a single cluster
+// must not be reported for the cluster of this StatusManager.
+func (status *StatusManager) MetricsDisabled() bool {
+	status.Lock()
+	defer status.Unlock()
+	return status.metricsDisabled
+}
+
+// syncConditionMetric updates the status_condition metric from the
+// Degraded and Progressing reasons of every status level.
+func (status *StatusManager) syncConditionMetric() {
+	if status.metricsDisabled {
+		return
+	}
+	metricStatusCondition.Reset()
+	for _, c := range status.failing {
+		if c != nil {
+			metricStatusCondition.WithLabelValues(c.Type, c.Reason).Set(1)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package statusmanager
+
+import (
+	"testing"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-network-operator/pkg/client/fake"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// conditionMetric returns the value of the status_condition metric with the given labels
+func conditionMetric(t *testing.T, condition, reason string) float64 {
+	m := &dto.Metric{}
+	if err := metricStatusCondition.WithLabelValues(condition, reason).Write(m); err != nil {
+		t.Fatalf("error reading metric: %v", err)
+	}
+	return m.GetGauge().GetValue()
+}
+
+func TestStatusManagerConditionMetric(t *testing.T) {
+	client := fake.NewFakeClient()
+	status := New(client, "testing", names.StandAloneClusterName)
+	setOC(t, client, &operv1.Network{ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG}})
+
+	status.SetDegraded(OperatorConfig, "Operator", "")
+	status.SetDegraded(PodDeployment, "Pods", "")
+	status.SetProgressing(PKIConfig, "Rolling", "")
+	if v := conditionMetric(t, operv1.OperatorStatusTypeDegraded, "Operator"); v != 1 {
+		t.Fatalf("expected Degraded/Operator to be 1, got %v", v)
+	}
+	if v := conditionMetric(t, operv1.OperatorStatusTypeDegraded, "Pods"); v != 1 {
+		t.Fatalf("expected Degraded/Pods to be 1, got %v", v)
+	}
+	if v := conditionMetric(t, operv1.OperatorStatusTypeProgressing, "Rolling"); v != 1 {
+		t.Fatalf("expected Progressing/Rolling to be 1, got %v", v)
+	}
+
+	status.SetNotDegraded(OperatorConfig)
+	status.UnsetProgressing(PKIConfig)
+	if v := conditionMetric(t, operv1.OperatorStatusTypeDegraded, "Operator"); v != 0 {
+		t.Fatalf("expected Degraded/Operator to be unset, got %v", v)
+	}
+	if v := conditionMetric(t, operv1.OperatorStatusTypeProgressing, "Rolling"); v != 0 {
+		t.Fatalf("expected Progressing/Rolling to be unset, got %v", v)
+	}
+	if v := conditionMetric(t, operv1.OperatorStatusTypeDegraded, "Pods"); v != 1 {
+		t.Fatalf("expected Degraded/Pods to be 1, got %v", v)
+	}
+}
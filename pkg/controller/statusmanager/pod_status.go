@@ -65,6 +65,16 @@ type statefulsetState struct {
 	LastChangeTime time.Time
 }
 
+// HungRollout is a DaemonSet, Deployment or StatefulSet whose rollout is hung.
+type HungRollout struct {
+	Kind string
+	ClusteredName
+	// Since is when the rollout last made progress
+	Since time.Time
+	// Reason is why the rollout is considered hung
+	Reason string
+}
+
 // SetFromPods sets the operator Degraded/Progressing/Available status, based on
 // the current status of the manager's DaemonSets, Deployments and StatefulSets.
 func (status *StatusManager) SetFromPods() {
@@ -72,12 +82,15 @@ func (status *StatusManager) SetFromPods() {
 	defer status.Unlock()
 
 	daemonSets, deployments, statefulSets := status.listAllStatusObjects()
+	wasProgressing := status.failing[PodDeployment] != nil
+	wasHung := status.failing[RolloutHung] != nil
 
 	targetLevel := os.Getenv("RELEASE_VERSION")
 	reachedAvailableLevel := (len(daemonSets) + len(deployments) + len(statefulSets)) > 0
 
 	progressing := []string{}
 	hung := []string{}
+	hungRollouts := []HungRollout{}
 
 	daemonsetStates, deploymentStates, statefulsetStates := status.getLastPodState()
 
@@ -89,6 +102,7 @@ func (status *StatusManager) SetFromPods() {
 		dsName := NewClusteredName(ds)
 
 		dsProgressing := false
+		var dsCrashLooping []string
 
 		if isNonCritical(ds) && ds.Status.NumberReady == 0 && !status.installComplete {
 			progressing = append(progressing, fmt.Sprintf("DaemonSet %q is waiting for other operators to become ready", dsName.String()))
@@ -101,7 +115,8 @@ func (status *StatusManager) SetFromPods() {
 			dsProgressing = true
 			// Check for any pods in CrashLoopBackOff state and mark the operator as degraded if so.
 			if !isNonCritical(ds) {
-				hung = append(hung, status.CheckCrashLoopBackOffPods(dsName, ds.Spec.Selector.MatchLabels, "DaemonSet")...)
+				dsCrashLooping = status.CheckCrashLoopBackOffPods(dsName, ds.Spec.Selector.MatchLabels, "DaemonSet")
+				hung = append(hung, dsCrashLooping...)
 			}
 		} else if ds.Status.NumberAvailable == 0 && ds.Status.DesiredNumberScheduled > 0 {
 			progressing = append(progressing, fmt.Sprintf("DaemonSet %q is not yet scheduled on any nodes", dsName.String()))
@@ -129,9 +144,13 @@ func (status *StatusManager) SetFromPods() {
 
 			// Catch hung rollouts
 			if exists && (time.Since(dsState.LastChangeTime)) > ProgressTimeout {
-				hung = append(hung, fmt.Sprintf("DaemonSet %q rollout is not making progress - last change %s", dsName.String(), dsState.LastChangeTime.Format(time.RFC3339)))
+				reason := fmt.Sprintf("DaemonSet %q rollout is not making progress - last change %s", dsName.String(), dsState.LastChangeTime.Format(time.RFC3339))
+				hung = append(hung, reason)
+				hungRollouts = append(hungRollouts, HungRollout{Kind: "DaemonSet", ClusteredName: dsName, Since: dsState.LastChangeTime, Reason: reason})
 				empty := ""
 				dsHung = &empty
+			} else if len(dsCrashLooping) > 0 {
+				hungRollouts = append(hungRollouts, HungRollout{Kind: "DaemonSet", ClusteredName: dsName, Since: dsState.LastChangeTime, Reason: strings.Join(dsCrashLooping, "\n")})
 			}
 		} else {
 			delete(daemonsetStates, dsName)
@@ -145,6 +164,7 @@ func (status *StatusManager) SetFromPods() {
 		ssName := NewClusteredName(ss)
 
 		ssProgressing := false
+		var ssCrashLooping []string
 
 		if isNonCritical(ss) && ss.Status.ReadyReplicas == 0 && !status.installComplete {
 			progressing = append(progressing, fmt.Sprintf("StatefulSet %q is waiting for other operators to become ready", ssName.String()))
@@ -157,7 +177,8 @@ func (status *StatusManager) SetFromPods() {
 			ssProgressing = true
 			// Check for any pods in CrashLoopBackOff state and mark the operator as degraded if so.
 			if !isNonCritical(ss) {
-				hung = append(hung, status.CheckCrashLoopBackOffPods(ssName, ss.Spec.Selector.MatchLabels, "StatefulSet")...)
+				ssCrashLooping = status.CheckCrashLoopBackOffPods(ssName, ss.Spec.Selector.MatchLabels, "StatefulSet")
+				hung = append(hung, ssCrashLooping...)
 			}
 		} else if ss.Status.AvailableReplicas == 0 {
 			progressing = append(progressing, fmt.Sprintf("StatefulSet %q is not yet scheduled on any nodes", ssName.String()))
@@ -185,9 +206,13 @@ func (status *StatusManager) SetFromPods() {
 
 			// Catch hung rollouts
 			if exists && (time.Since(ssState.LastChangeTime)) > ProgressTimeout {
-				hung = append(hung, fmt.Sprintf("StatefulSet %q rollout is not making progress - last change %s", ssName.String(), ssState.LastChangeTime.Format(time.RFC3339)))
+				reason := fmt.Sprintf("StatefulSet %q rollout is not making progress - last change %s", ssName.String(), ssState.LastChangeTime.Format(time.RFC3339))
+				hung = append(hung, reason)
+				hungRollouts = append(hungRollouts, HungRollout{Kind: "StatefulSet", ClusteredName: ssName, Since: ssState.LastChangeTime, Reason: reason})
 				empty := ""
 				ssHung = &empty
+			} else if len(ssCrashLooping) > 0 {
+				hungRollouts = append(hungRollouts, HungRollout{Kind: "StatefulSet", ClusteredName: ssName, Since: ssState.LastChangeTime, Reason: strings.Join(ssCrashLooping, "\n")})
 			}
 		} else {
 			delete(statefulsetStates, ssName)
@@ -200,6 +225,7 @@ func (status *StatusManager) SetFromPods() {
 	for _, dep := range deployments {
 		depName := NewClusteredName(dep)
 		depProgressing := false
+		var depCrashLooping []string
 
 		if isNonCritical(dep) && dep.Status.UnavailableReplicas > 0 && !status.installComplete {
 			progressing = append(progressing, fmt.Sprintf("Deployment %q is waiting for other operators to become ready", depName.String()))
@@ -212,7 +238,8 @@ func (status *StatusManager) SetFromPods() {
 			depProgressing = true
 			// Check for any pods in CrashLoopBackOff state and mark the operator as degraded if so.
 			if !isNonCritical(dep) {
-				hung = append(hung, status.CheckCrashLoopBackOffPods(depName, dep.Spec.Selector.MatchLabels, "Deployment")...)
+				depCrashLooping = status.CheckCrashLoopBackOffPods(depName, dep.Spec.Selector.MatchLabels, "Deployment")
+				hung = append(hung, depCrashLooping...)
 			}
 		} else if dep.Status.AvailableReplicas == 0 {
 			progressing = append(progressing, fmt.Sprintf("Deployment %q is not yet scheduled on any nodes", depName.String()))
@@ -240,9 +267,13 @@ func (status *StatusManager) SetFromPods() {
 
 			// Catch hung rollouts
 			if exists && (time.Since(depState.LastChangeTime)) > ProgressTimeout {
-				hung = append(hung, fmt.Sprintf("Deployment %q rollout is not making progress - last change %s", depName.String(), depState.LastChangeTime.Format(time.RFC3339)))
+				reason := fmt.Sprintf("Deployment %q rollout is not making progress - last change %s", depName.String(), depState.LastChangeTime.Format(time.RFC3339))
+				hung = append(hung, reason)
+				hungRollouts = append(hungRollouts, HungRollout{Kind: "Deployment", ClusteredName: depName, Since: depState.LastChangeTime, Reason: reason})
 				empty := ""
 				depHung = &empty
+			} else if len(depCrashLooping) > 0 {
+				hungRollouts = append(hungRollouts, HungRollout{Kind: "Deployment", ClusteredName: depName, Since: depState.LastChangeTime, Reason: strings.Join(depCrashLooping, "\n")})
 			}
 		} else {
 			delete(deploymentStates, depName)
@@ -273,11 +304,14 @@ func (status *StatusManager) SetFromPods() {
 		status.installComplete = true
 	}
 
+	status.hungRollouts = hungRollouts
 	if len(hung) > 0 {
 		status.setDegraded(RolloutHung, "RolloutHung", strings.Join(hung, "\n"))
 	} else {
 		status.setNotDegraded(RolloutHung)
 	}
+
+	status.recordRolloutEvents(wasProgressing, wasHung, progressing, hung)
 }
 
 // getLastPodState reads the last-seen daemonset + deployment + statefulset
@@ -30,7 +30,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/clock"
@@ -51,10 +53,13 @@ const (
 	PodDeployment
 	PKIConfig
 	EgressRouterConfig
+	RolledBack
 	RolloutHung
 	CertificateSigner
 	InfrastructureConfig
 	DashboardConfig
+	ExtraClustersConfig
+	OperandOverridesConfig
 	maxStatusLevel
 )
 
@@ -63,19 +68,6 @@ const (
 	fieldManager           = "cluster-network-operator/status-manager"
 )
 
-// keepCRDs is a list of CRD names that won't be removed from the system even if
-// the conditions that triggered their install are no longer met. The general
-// purpose of this is to prevent data loss from configured instances of such
-// CRDs. User should explicitly delete such instances.
-// TODO: perhaps degrade CNO state if CRDs are attempted to be removed
-var keepCRDs = sets.New(
-	// Aside from the data loss, MetalLB operator relies on FRR and reconciles
-	// the CNO flag to deploy it. This reconciliation would be meaningless it we
-	// were to destroy frrconfigurations.
-	"frrconfigurations.frrk8s.metallb.io",
-	"routeadvertisements.k8s.ovn.org",
-)
-
 type ClusteredName struct {
 	ClusterName string
 	Namespace   string
@@ -104,6 +96,8 @@ type StatusManager struct {
 
 	failing         [maxStatusLevel]*operv1.OperatorCondition
 	installComplete bool
+	// the rollouts that are currently hung
+	hungRollouts []HungRollout
 
 	// All our informers and listers
 	dsInformers map[string]cache.SharedIndexInformer
@@ -124,6 +118,14 @@ type StatusManager struct {
 	// local cache to store network operator machine configs being deleted.
 	machineConfigsBeingRemoved map[string]sets.Set[string]
 
+	// if the status_condition metric is not reported
+	metricsDisabled bool
+
+	// recorder emits the events on the operator configuration, whose UID is
+	// operConfigUID
+	recorder      record.EventRecorder
+	operConfigUID types.UID
+
 	// used only for upgrades from <=4.13 to 4.14 with ovn-kubernetes
 	// TODO: remove in 4.15
 	isOVNKubernetes *bool
@@ -249,7 +251,7 @@ func (status *StatusManager) deleteRelatedObjectsNotRendered(co *configv1.Cluste
 
 			// Do not remove selected CRDs to prevent data loss that would
 			// happen when its instances are automaticaly removed as a result.
-			if gvk.Kind == "CustomResourceDefinition" && gvk.Group == "apiextensions.k8s.io" && keepCRDs.Has(currentObj.Name) {
+			if gvk.Kind == "CustomResourceDefinition" && gvk.Group == "apiextensions.k8s.io" && apply.KeepCRDs.Has(currentObj.Name) {
 				klog.Infof("Won't remove CRD %q to prevent data loss, skip", currentObj.Name)
 				continue
 			}
@@ -356,6 +358,7 @@ func (status *StatusManager) set(reachedAvailableLevel bool, conditions ...operv
 			// Should never happen outside of unit tests
 			return err
 		}
+		status.operConfigUID = oc.UID
 
 		oldStatus := oc.Status.DeepCopy()
 
@@ -448,6 +451,7 @@ func (status *StatusManager) set(reachedAvailableLevel bool, conditions ...operv
 			return err
 		}
 		log.Printf("Network operator config updated with conditions:\n%s", buf)
+		status.recordConditionEvents(oldStatus.Conditions, oc.Status.Conditions)
 
 		return nil
 	}()
@@ -493,7 +497,12 @@ func (status *StatusManager) set(reachedAvailableLevel bool, conditions ...operv
 				}
 			}
 
+			// Only the standard conditions are reported on the ClusterOperator;
+			// the others describe the operator configuration, and stay on it.
 			for _, cond := range operStatus.Conditions {
+				if !isClusterOperatorCondition(cond.Type) {
+					continue
+				}
 				cohelpers.SetStatusCondition(&co.Status.Conditions, operstatus.OperatorConditionToClusterOperatorCondition(cond), clock.RealClock{})
 			}
 		}
@@ -529,6 +538,7 @@ func (status *StatusManager) set(reachedAvailableLevel bool, conditions ...operv
 
 // syncDegraded syncs the current Degraded status
 func (status *StatusManager) syncDegraded() {
+	status.syncConditionMetric()
 	for _, c := range status.failing {
 		if c != nil && c.Type == operv1.OperatorStatusTypeDegraded {
 			status.set(false, *c)
@@ -582,6 +592,7 @@ func (status *StatusManager) SetNotDegraded(statusLevel StatusLevel) {
 
 // syncProgressing syncs the current Progressing status
 func (status *StatusManager) syncProgressing() {
+	status.syncConditionMetric()
 	for _, c := range status.failing {
 		if c != nil && c.Type == operv1.OperatorStatusTypeProgressing {
 			status.set(false, *c)
@@ -614,6 +625,22 @@ func (status *StatusManager) unsetProgressing(statusLevel StatusLevel) {
 	status.syncProgressing()
 }
 
+// HungRollouts returns the DaemonSets, Deployments and StatefulSets whose
+// rollout is currently hung.
+func (status *StatusManager) HungRollouts() []HungRollout {
+	status.Lock()
+	defer status.Unlock()
+	return append([]HungRollout{}, status.hungRollouts...)
+}
+
+// IsRolloutComplete returns true if every DaemonSet, Deployment and StatefulSet
+// has finished rolling out.
+func (status *StatusManager) IsRolloutComplete() bool {
+	status.Lock()
+	defer status.Unlock()
+	return status.installComplete && status.failing[PodDeployment] == nil && status.failing[RolloutHung] == nil
+}
+
 func (status *StatusManager) SetProgressing(statusLevel StatusLevel, reason, message string) {
 	status.Lock()
 	defer status.Unlock()
@@ -626,6 +653,80 @@ func (status *StatusManager) UnsetProgressing(statusLevel StatusLevel) {
 	status.unsetProgressing(statusLevel)
 }
 
+// isClusterOperatorCondition returns true if conditions of type condType are
+// copied from the operator configuration to the ClusterOperator.
+func isClusterOperatorCondition(condType string) bool {
+	switch condType {
+	case operv1.OperatorStatusTypeAvailable, operv1.OperatorStatusTypeProgressing,
+		operv1.OperatorStatusTypeDegraded, operv1.OperatorStatusTypeUpgradeable:
+		return true
+	}
+	return false
+}
+
+// SetConfigValidation sets the OperatorConfigValid condition of the operator
+// configuration, which lists each invalid field of its spec.
+func (status *StatusManager) SetConfigValidation(errs field.ErrorList) {
+	status.Lock()
+	defer status.Unlock()
+
+	condition := operv1.OperatorCondition{
+		Type:   names.OperatorConfigValidCondition,
+		Status: operv1.ConditionTrue,
+		Reason: "AsExpected",
+	}
+	if len(errs) > 0 {
+		messages := make([]string, 0, len(errs))
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		condition.Status = operv1.ConditionFalse
+		condition.Reason = "InvalidFields"
+		condition.Message = strings.Join(messages, "\n")
+	}
+	status.set(false, condition)
+}
+
+// maxListedNodes is the maximum number of nodes named in a condition message.
+const maxListedNodes = 10
+
+// SetStaleNodeSubnets sets the NodeSubnetsUpToDate condition of the operator
+// configuration, which lists the nodes whose pod subnet was allocated with a
+// previous clusterNetwork hostPrefix.
+func (status *StatusManager) SetStaleNodeSubnets(nodes []string) {
+	status.Lock()
+	defer status.Unlock()
+
+	condition := operv1.OperatorCondition{
+		Type:   names.NodeSubnetsUpToDateCondition,
+		Status: operv1.ConditionTrue,
+		Reason: "AsExpected",
+	}
+	if len(nodes) > 0 {
+		listed := nodes
+		if len(listed) > maxListedNodes {
+			listed = listed[:maxListedNodes]
+		}
+		condition.Status = operv1.ConditionFalse
+		condition.Reason = "PreviousHostPrefix"
+		condition.Message = fmt.Sprintf("%d nodes have a pod subnet allocated with a previous hostPrefix: %s",
+			len(nodes), strings.Join(listed, ", "))
+		if len(nodes) > len(listed) {
+			condition.Message += ", ..."
+		}
+	}
+	status.set(false, condition)
+}
+
+// SetServiceNetworkMigrationConditions sets the conditions of the operator
+// configuration that report the progress of a service network migration.
+func (status *StatusManager) SetServiceNetworkMigrationConditions(conditions []operv1.OperatorCondition) {
+	status.Lock()
+	defer status.Unlock()
+
+	status.set(false, conditions...)
+}
+
 func (status *StatusManager) SetRelatedObjects(relatedObjects []configv1.ObjectReference) {
 	status.Lock()
 	defer status.Unlock()
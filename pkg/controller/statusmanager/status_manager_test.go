@@ -3,6 +3,7 @@ package statusmanager
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"reflect"
 	"testing"
@@ -24,6 +25,7 @@ import (
 	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/kubernetes/scheme"
 
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -424,6 +426,88 @@ func TestStatusManagerSetDegraded(t *testing.T) {
 	}
 }
 
+func TestStatusManagerSetConfigValidation(t *testing.T) {
+	client := fake.NewFakeClient()
+	status := New(client, "testing", names.StandAloneClusterName)
+	setOC(t, client, &operv1.Network{ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG}})
+
+	status.SetConfigValidation(field.ErrorList{
+		field.Invalid(field.NewPath("spec", "serviceNetwork").Index(0), "172.30.0.0/33", "could not parse CIDR"),
+		field.Required(field.NewPath("spec", "clusterNetwork"), "must have at least 1 entry"),
+	})
+	oc, err := getOC(client)
+	if err != nil {
+		t.Fatalf("error getting network config: %v", err)
+	}
+	condition := v1helpers.FindOperatorCondition(oc.Status.Conditions, names.OperatorConfigValidCondition)
+	expected := operv1.OperatorCondition{
+		Type:   names.OperatorConfigValidCondition,
+		Status: operv1.ConditionFalse,
+		Reason: "InvalidFields",
+		Message: "spec.serviceNetwork[0]: Invalid value: \"172.30.0.0/33\": could not parse CIDR\n" +
+			"spec.clusterNetwork: Required value: must have at least 1 entry",
+	}
+	if condition == nil || !conditionsEqual([]operv1.OperatorCondition{*condition}, []operv1.OperatorCondition{expected}) {
+		t.Fatalf("unexpected Status.Conditions: %#v", oc.Status.Conditions)
+	}
+
+	// The condition is only reported on the operator configuration
+	co, err := getCO(client, "testing")
+	if err != nil {
+		t.Fatalf("error getting ClusterOperator: %v", err)
+	}
+	for _, cond := range co.Status.Conditions {
+		if string(cond.Type) == names.OperatorConfigValidCondition {
+			t.Fatalf("unexpected ClusterOperator condition: %#v", cond)
+		}
+	}
+
+	status.SetConfigValidation(nil)
+	oc, err = getOC(client)
+	if err != nil {
+		t.Fatalf("error getting network config: %v", err)
+	}
+	if !v1helpers.IsOperatorConditionTrue(oc.Status.Conditions, names.OperatorConfigValidCondition) {
+		t.Fatalf("unexpected Status.Conditions: %#v", oc.Status.Conditions)
+	}
+}
+
+func TestStatusManagerSetStaleNodeSubnets(t *testing.T) {
+	client := fake.NewFakeClient()
+	status := New(client, "testing", names.StandAloneClusterName)
+	setOC(t, client, &operv1.Network{ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG}})
+
+	nodes := []string{}
+	for i := 0; i < 12; i++ {
+		nodes = append(nodes, fmt.Sprintf("node-%02d", i))
+	}
+	status.SetStaleNodeSubnets(nodes)
+	oc, err := getOC(client)
+	if err != nil {
+		t.Fatalf("error getting network config: %v", err)
+	}
+	condition := v1helpers.FindOperatorCondition(oc.Status.Conditions, names.NodeSubnetsUpToDateCondition)
+	expected := operv1.OperatorCondition{
+		Type:   names.NodeSubnetsUpToDateCondition,
+		Status: operv1.ConditionFalse,
+		Reason: "PreviousHostPrefix",
+		Message: "12 nodes have a pod subnet allocated with a previous hostPrefix: " +
+			"node-00, node-01, node-02, node-03, node-04, node-05, node-06, node-07, node-08, node-09, ...",
+	}
+	if condition == nil || !conditionsEqual([]operv1.OperatorCondition{*condition}, []operv1.OperatorCondition{expected}) {
+		t.Fatalf("unexpected Status.Conditions: %#v", oc.Status.Conditions)
+	}
+
+	status.SetStaleNodeSubnets(nil)
+	oc, err = getOC(client)
+	if err != nil {
+		t.Fatalf("error getting network config: %v", err)
+	}
+	if !v1helpers.IsOperatorConditionTrue(oc.Status.Conditions, names.NodeSubnetsUpToDateCondition) {
+		t.Fatalf("unexpected Status.Conditions: %#v", oc.Status.Conditions)
+	}
+}
+
 func TestStatusManagerSetFromIPsecConfigs(t *testing.T) {
 	client := fake.NewFakeClient()
 	status := New(client, "testing", names.StandAloneClusterName)
@@ -1934,6 +2018,12 @@ func TestStatusManagerCheckCrashLoopBackOffPods(t *testing.T) {
 	}) {
 		t.Fatalf("unexpected Status.Conditions: %#v", oc.Status.Conditions)
 	}
+
+	hungRollouts := status.HungRollouts()
+	if len(hungRollouts) != 1 || hungRollouts[0].Kind != "Deployment" || hungRollouts[0].ClusteredName != (ClusteredName{Namespace: "three", Name: "gamma"}) ||
+		hungRollouts[0].Since.IsZero() {
+		t.Fatalf("unexpected hung rollouts: %#v", hungRollouts)
+	}
 }
 
 // In HyperShift environment there is more than one CNO running in the management cluster.
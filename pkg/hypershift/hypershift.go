@@ -13,9 +13,11 @@ import (
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	sigsyaml "sigs.k8s.io/yaml"
 )
 
 const HostedClusterLocalProxy = "socks5://127.0.0.1:8090"
@@ -63,6 +65,32 @@ type HostedControlPlane struct {
 	AdvertiseAddress             string
 	AdvertisePort                int
 	PriorityClass                string
+	// Annotations are added to the pods of the control plane components
+	Annotations map[string]string
+	// TopologySpreadConstraints are added to the pods of the control plane
+	// components. A constraint without a label selector selects the pods of
+	// the component.
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint
+	// PodAffinity and PodAntiAffinity terms are added to the ones of the
+	// control plane components
+	PodAffinity     *corev1.PodAffinity
+	PodAntiAffinity *corev1.PodAntiAffinity
+	// ResourceRequestOverrides are the resource requests of the containers of
+	// the control plane components, by "<deployment>.<container>"
+	ResourceRequestOverrides map[string]corev1.ResourceList
+}
+
+// ResourceRequestOverrideAnnotationPrefix is the prefix of the HostedControlPlane
+// annotations that override the resource requests of a container of a control
+// plane Deployment, e.g.
+// resource-request-override.hypershift.openshift.io/ovnkube-control-plane.ovnkube-control-plane: cpu=100m,memory=300Mi
+const ResourceRequestOverrideAnnotationPrefix = "resource-request-override.hypershift.openshift.io"
+
+// AffinityTerms are the yaml lines of pod (anti-)affinity terms, as rendered
+// in the control plane manifests.
+type AffinityTerms struct {
+	Required  []string
+	Preferred []string
 }
 
 // AvailabilityPolicy specifies a high level availability policy for components.
@@ -219,6 +247,30 @@ func ParseHostedControlPlane(hcp *unstructured.Unstructured) (*HostedControlPlan
 		}
 	}
 
+	annotations, _, err := unstructured.NestedStringMap(hcp.UnstructuredContent(), "spec", "annotations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract annotations: %v", err)
+	}
+
+	var topologySpreadConstraints []corev1.TopologySpreadConstraint
+	if err := nestedFieldInto(hcp, &topologySpreadConstraints, "spec", "topologySpreadConstraints"); err != nil {
+		return nil, fmt.Errorf("failed to extract topologySpreadConstraints: %v", err)
+	}
+
+	var podAffinity *corev1.PodAffinity
+	if err := nestedFieldInto(hcp, &podAffinity, "spec", "affinity", "podAffinity"); err != nil {
+		return nil, fmt.Errorf("failed to extract podAffinity: %v", err)
+	}
+	var podAntiAffinity *corev1.PodAntiAffinity
+	if err := nestedFieldInto(hcp, &podAntiAffinity, "spec", "affinity", "podAntiAffinity"); err != nil {
+		return nil, fmt.Errorf("failed to extract podAntiAffinity: %v", err)
+	}
+
+	resourceRequestOverrides, err := parseResourceRequestOverrides(hcp.GetAnnotations())
+	if err != nil {
+		return nil, err
+	}
+
 	advertiseAddress, valueFound, err := unstructured.NestedString(hcp.UnstructuredContent(), "spec", "networking", "apiServer", "advertiseAddress")
 	if err != nil {
 		return nil, fmt.Errorf("failed extract advertiseAddress: %v", err)
@@ -264,9 +316,120 @@ func ParseHostedControlPlane(hcp *unstructured.Unstructured) (*HostedControlPlan
 		AdvertiseAddress:             advertiseAddress,
 		AdvertisePort:                int(advertisePort),
 		PriorityClass:                controlPlanePriorityClassAnnotation,
+		Annotations:                  annotations,
+		TopologySpreadConstraints:    topologySpreadConstraints,
+		PodAffinity:                  podAffinity,
+		PodAntiAffinity:              podAntiAffinity,
+		ResourceRequestOverrides:     resourceRequestOverrides,
 	}, nil
 }
 
+// nestedFieldInto converts the field of the HostedControlPlane at the given
+// path into out, which is left unchanged if the field is not set.
+func nestedFieldInto(hcp *unstructured.Unstructured, out interface{}, fields ...string) error {
+	value, found, err := unstructured.NestedFieldNoCopy(hcp.UnstructuredContent(), fields...)
+	if err != nil || !found {
+		return err
+	}
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(jsonData, out)
+}
+
+// parseResourceRequestOverrides parses the resource-request-override
+// annotations of a HostedControlPlane, whose values are comma-separated
+// resource=quantity pairs.
+func parseResourceRequestOverrides(annotations map[string]string) (map[string]corev1.ResourceList, error) {
+	var overrides map[string]corev1.ResourceList
+	for key, value := range annotations {
+		target, found := strings.CutPrefix(key, ResourceRequestOverrideAnnotationPrefix+"/")
+		if !found {
+			continue
+		}
+		requests := corev1.ResourceList{}
+		for _, request := range strings.Split(value, ",") {
+			name, quantity, found := strings.Cut(strings.TrimSpace(request), "=")
+			if !found {
+				return nil, fmt.Errorf("invalid resource request %q in annotation %s", request, key)
+			}
+			q, err := resource.ParseQuantity(quantity)
+			if err != nil {
+				return nil, fmt.Errorf("invalid resource request %q in annotation %s: %v", request, key, err)
+			}
+			requests[corev1.ResourceName(name)] = q
+		}
+		if overrides == nil {
+			overrides = map[string]corev1.ResourceList{}
+		}
+		overrides[target] = requests
+	}
+	return overrides, nil
+}
+
+// ResourceRequests returns the overridden resource requests of the containers
+// of a control plane Deployment, by container name and resource name, e.g.
+// {"ovnkube-control-plane": {"cpu": "100m"}}.
+func (hcp *HostedControlPlane) ResourceRequests(deployment string) map[string]map[string]string {
+	requests := map[string]map[string]string{}
+	for target, resources := range hcp.ResourceRequestOverrides {
+		container, found := strings.CutPrefix(target, deployment+".")
+		if !found {
+			continue
+		}
+		requests[container] = map[string]string{}
+		for name, quantity := range resources {
+			requests[container][string(name)] = quantity.String()
+		}
+	}
+	return requests
+}
+
+// TopologySpreadConstraintsYaml returns the yaml lines of the topology spread
+// constraints of the HostedControlPlane for a control plane component. The
+// constraints without a label selector select the pods with podLabels.
+func (hcp *HostedControlPlane) TopologySpreadConstraintsYaml(podLabels map[string]string) ([]string, error) {
+	constraints := make([]corev1.TopologySpreadConstraint, 0, len(hcp.TopologySpreadConstraints))
+	for _, constraint := range hcp.TopologySpreadConstraints {
+		if constraint.LabelSelector == nil {
+			constraint.LabelSelector = &metav1.LabelSelector{MatchLabels: podLabels}
+		}
+		constraints = append(constraints, constraint)
+	}
+	return toStringSliceYaml(constraints)
+}
+
+// PodAffinityYaml returns the yaml lines of the pod affinity terms of the
+// HostedControlPlane.
+func (hcp *HostedControlPlane) PodAffinityYaml() (AffinityTerms, error) {
+	if hcp.PodAffinity == nil {
+		return AffinityTerms{}, nil
+	}
+	return affinityTermsYaml(hcp.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+		hcp.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+}
+
+// PodAntiAffinityYaml returns the yaml lines of the pod anti-affinity terms of
+// the HostedControlPlane.
+func (hcp *HostedControlPlane) PodAntiAffinityYaml() (AffinityTerms, error) {
+	if hcp.PodAntiAffinity == nil {
+		return AffinityTerms{}, nil
+	}
+	return affinityTermsYaml(hcp.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+		hcp.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+}
+
+func affinityTermsYaml(required []corev1.PodAffinityTerm, preferred []corev1.WeightedPodAffinityTerm) (AffinityTerms, error) {
+	var terms AffinityTerms
+	var err error
+	if terms.Required, err = toStringSliceYaml(required); err != nil {
+		return terms, err
+	}
+	terms.Preferred, err = toStringSliceYaml(preferred)
+	return terms, err
+}
+
 // SetHostedControlPlaneConditions updates the hcp status.conditions based on the provided operStatus
 // Returns an updated list of conditions and an error. If there are no changes, the returned list is empty.
 func SetHostedControlPlaneConditions(hcp *unstructured.Unstructured, operStatus *operv1.NetworkStatus) ([]metav1.Condition, error) {
@@ -322,6 +485,22 @@ func SetHostedControlPlaneConditions(hcp *unstructured.Unstructured, operStatus
 	return conditions, nil
 }
 
+// toStringSliceYaml converts a slice of API objects into a slice of strings
+// that represent the objects in yaml syntax where each string is a line of
+// yaml. Unlike tolerationsToStringSliceYaml, the json names of the fields are
+// used.
+func toStringSliceYaml[T any](objs []T) ([]string, error) {
+	if len(objs) == 0 {
+		return nil, nil
+	}
+
+	yamlBytes, err := sigsyaml.Marshal(objs)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimRight(string(yamlBytes), "\n"), "\n"), nil
+}
+
 // tolerationsToStringSliceYaml converts a slice of tolerations into a slice of
 // strings that represent the toleration in yaml syntax where each string
 // is a line of yaml. The resulting string slice can be easily used in
@@ -2,6 +2,9 @@ package hypershift
 
 import (
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/yaml"
@@ -87,6 +90,56 @@ spec:
     - cidr: "2001::/16"
     apiServer:
       port: 2040
+`,
+		},
+		{
+			name: "Picks up scheduling options",
+			expectedOutput: &HostedControlPlane{
+				ClusterID:                    "31df7fa9-b1a7-4a66-98ef-c6920bf213d8",
+				ControllerAvailabilityPolicy: HighlyAvailable,
+				AdvertiseAddress:             HostedClusterDefaultAdvertiseAddressIPV4,
+				AdvertisePort:                int(HostedClusterDefaultAdvertisePort),
+				Annotations:                  map[string]string{"example.com/team": "network"},
+				TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+					MaxSkew:           1,
+					TopologyKey:       "topology.kubernetes.io/zone",
+					WhenUnsatisfiable: corev1.DoNotSchedule,
+				}},
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+						LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "noisy"}},
+						TopologyKey:   "kubernetes.io/hostname",
+					}},
+				},
+				ResourceRequestOverrides: map[string]corev1.ResourceList{
+					"ovnkube-control-plane.ovnkube-control-plane": {
+						corev1.ResourceCPU:    resource.MustParse("100m"),
+						corev1.ResourceMemory: resource.MustParse("300Mi"),
+					},
+				},
+			},
+			inputUnstructuredContent: `
+apiVersion: hypershift.openshift.io/v1beta1
+kind: HostedControlPlane
+metadata:
+  annotations:
+    resource-request-override.hypershift.openshift.io/ovnkube-control-plane.ovnkube-control-plane: cpu=100m,memory=300Mi
+spec:
+  clusterID: 31df7fa9-b1a7-4a66-98ef-c6920bf213d8
+  controllerAvailabilityPolicy: HighlyAvailable
+  annotations:
+    example.com/team: network
+  topologySpreadConstraints:
+  - maxSkew: 1
+    topologyKey: topology.kubernetes.io/zone
+    whenUnsatisfiable: DoNotSchedule
+  affinity:
+    podAntiAffinity:
+      requiredDuringSchedulingIgnoredDuringExecution:
+      - labelSelector:
+          matchLabels:
+            app: noisy
+        topologyKey: kubernetes.io/hostname
 `,
 		},
 	}
@@ -103,3 +156,11 @@ spec:
 		g.Expect(actualOutput).To(Equal(tc.expectedOutput))
 	}
 }
+
+func TestParseResourceRequestOverridesInvalid(t *testing.T) {
+	g := NewGomegaWithT(t)
+	_, err := parseResourceRequestOverrides(map[string]string{
+		ResourceRequestOverrideAnnotationPrefix + "/ovnkube-control-plane.ovnkube-control-plane": "cpu",
+	})
+	g.Expect(err).To(MatchError(ContainSubstring(`invalid resource request "cpu"`)))
+}
@@ -48,6 +48,66 @@ const CreateOnlyAnnotation = "networkoperator.openshift.io/create-only"
 // tells the CNO reconciliation engine to ignore creating this object until conditions are met.
 const CreateWaitAnnotation = "networkoperator.openshift.io/create-wait"
 
+// ApplyPhaseAnnotation is an annotation on rendered objects that sets the
+// phase, a non-negative integer, in which they are applied. Objects without it
+// are in phase 0. All objects of a phase are applied, and become ready, before
+// any object of a later phase is applied.
+const ApplyPhaseAnnotation = "networkoperator.openshift.io/apply-phase"
+
+// WaitForAnnotation is an annotation on rendered objects that tells the CNO
+// reconciliation engine to wait until the object is ready before applying the
+// objects of later phases. The value is the condition to wait for, e.g.
+// "Established" for a CRD or "Available" for a Deployment or DaemonSet.
+const WaitForAnnotation = "networkoperator.openshift.io/wait-for"
+
+// DryRunAnnotation is an annotation on the networks.operator.openshift.io CR that
+// tells the operconfig controller to only dry-run the rendered objects, and to
+// report the changes they would make in the DRY_RUN_REPORT_CONFIGMAP ConfigMap,
+// instead of applying them.
+const DryRunAnnotation = "networkoperator.openshift.io/dry-run"
+
+// DRY_RUN_REPORT_CONFIGMAP is the name of the ConfigMap, in APPLIED_NAMESPACE,
+// where the result of a dry-run reconcile is stored.
+const DRY_RUN_REPORT_CONFIGMAP = "dry-run-report"
+
+// RollbackOnFailureAnnotation is an annotation on the operator configuration that,
+// when set to "true", makes the operator go back to the last known-good configuration
+// if the rollout of a new configuration hangs.
+const RollbackOnFailureAnnotation = "networkoperator.openshift.io/rollback-on-failure"
+
+// KNOWN_GOOD_CONFIGMAP is the name of the ConfigMap, in APPLIED_NAMESPACE, where
+// the last known-good configuration and the configuration that failed to roll out are stored.
+const KNOWN_GOOD_CONFIGMAP = "last-known-good"
+
+// RECONCILE_HISTORY_CONFIGMAP is the name of the ConfigMap, in APPLIED_NAMESPACE, where
+// a bounded history of the reconciles of the operator configuration is stored.
+const RECONCILE_HISTORY_CONFIGMAP = "reconcile-history"
+
+// EXTRA_CLUSTERS_CONFIGMAP is the name of the ConfigMap, in APPLIED_NAMESPACE, that
+// lists the extra clusters the operator reconciles, as cluster names mapped to
+// the path of their kubeconfig.
+const EXTRA_CLUSTERS_CONFIGMAP = "extra-clusters"
+
+// OPERAND_OVERRIDES_CONFIGMAP is the name of the ConfigMap, in APPLIED_NAMESPACE, that
+// holds the overrides of the rendered DaemonSets and Deployments, by name.
+const OPERAND_OVERRIDES_CONFIGMAP = "operand-overrides"
+
+// PruneProtectAnnotation is an annotation that can be set on objects applied by the
+// operator to prevent the reconciler from deleting them once they are no longer rendered.
+const PruneProtectAnnotation = "networkoperator.openshift.io/protect-from-prune"
+
+// INVENTORY_CONFIGMAP is the name of the ConfigMap, in APPLIED_NAMESPACE, where
+// the list of objects applied by the operconfig controller is stored.
+const INVENTORY_CONFIGMAP = "applied-inventory"
+
+// AppliedByLabel is a label on the objects applied by the operconfig controller.
+// Only objects with this label, set to AppliedByLabelValue, are pruned once they
+// are no longer rendered.
+const AppliedByLabel = "networkoperator.openshift.io/applied-by"
+
+// AppliedByLabelValue is the value of AppliedByLabel.
+const AppliedByLabelValue = "cluster-network-operator"
+
 // NonCriticalAnnotation is an annotation on Deployments/DaemonSets to indicate
 // that they are not critical to the functioning of the pod network
 const NonCriticalAnnotation = "networkoperator.openshift.io/non-critical"
@@ -76,6 +136,29 @@ const NetworkIPFamilyModeAnnotation = "networkoperator.openshift.io/ip-family-mo
 // to indicate the current list of clusterNetwork CIDRs available to the cluster.
 const ClusterNetworkCIDRsAnnotation = "networkoperator.openshift.io/cluster-network-cidr"
 
+// ServiceNetworkCIDRsAnnotation is an annotation on the OVN networks.operator.openshift.io daemonsets
+// to indicate the service network CIDRs they were rendered with, including the additional
+// ones of a service network migration.
+const ServiceNetworkCIDRsAnnotation = "networkoperator.openshift.io/service-network-cidr"
+
+// OVNKubeNodeTemplateHashAnnotation is an annotation on the ovnkube-node daemonsets
+// with the hash of the pod template they were rendered with. It is used to
+// detect pending changes during canary rollouts.
+const OVNKubeNodeTemplateHashAnnotation = "networkoperator.openshift.io/ovnkube-node-template-hash"
+
+// OperandOverridesHashAnnotation is an annotation on the DaemonSets and
+// Deployments with the hash of the operand overrides applied to them.
+const OperandOverridesHashAnnotation = "networkoperator.openshift.io/operand-overrides-hash"
+
+// OVNKubeNodeCanaryLabelAnnotation is an annotation on the ovnkube-node daemonset
+// with the canary node label it was rendered to stay off of.
+const OVNKubeNodeCanaryLabelAnnotation = "networkoperator.openshift.io/ovnkube-node-canary-label"
+
+// EgressRouterObservedGenerationAnnotation is an annotation on EgressRouters
+// with the generation that their status conditions were computed for. The
+// EgressRouter status has no observedGeneration field.
+const EgressRouterObservedGenerationAnnotation = "networkoperator.openshift.io/observed-generation"
+
 // MasqueradeCIDRsAnnotation is an annotation on the OVN networks.operator.openshift.io resources
 // to indicate the list of default masquerade CIDRs. The default masquerade network CIDRs can differ
 // from the actual masquerade network CIDRs if it was specified through the
@@ -153,6 +236,35 @@ const MachineConfigPoolsUpdating string = "MachineConfigPoolsUpdating"
 // conditions to indicate if MCP is degraded
 const MachineConfigPoolDegraded string = "MachineConfigPoolDegraded"
 
+// ServiceNetworkMigrationAnnotation is an annotation on the networks.operator.openshift.io CR
+// that requests a migration of the service network to the given comma-separated CIDRs
+const ServiceNetworkMigrationAnnotation = "networkoperator.openshift.io/service-network-migration"
+
+// ServiceNetworkMigrationOriginalAnnotation is an annotation on the networks.operator.openshift.io CR,
+// set by the operator, with the comma-separated CIDRs the service network migration started from
+const ServiceNetworkMigrationOriginalAnnotation = "networkoperator.openshift.io/service-network-migration-original"
+
+// ServiceNetworkMigrationPausedAnnotation is an annotation on the MachineConfigPools that the operator
+// paused while switching the service network, so that the kubelets are only rolled out with the cluster
+// DNS IP of the target once the apiserver and cluster DNS Services are in it
+const ServiceNetworkMigrationPausedAnnotation = "networkoperator.openshift.io/service-network-migration-paused"
+
+// Status condition types of networks.operator.openshift.io for service network migration
+const (
+	// ServiceNetworkMigrationInProgress is the condition type for service network migration to indicate if the
+	// migration is in progress
+	ServiceNetworkMigrationInProgress string = "ServiceNetworkMigrationInProgress"
+	// ServiceNetworkMigrationTargetAdded is the condition type for service network migration to indicate if
+	// ovn-kubernetes is configured with the target service network and the apiserver allocates from it
+	ServiceNetworkMigrationTargetAdded string = "ServiceNetworkMigrationTargetAdded"
+	// ServiceNetworkMigrationConsumersMigrated is the condition type for service network migration to indicate
+	// if the Services have been recreated with a cluster IP in the target service network
+	ServiceNetworkMigrationConsumersMigrated string = "ServiceNetworkMigrationConsumersMigrated"
+	// ServiceNetworkMigrationOriginalRemoved is the condition type for service network migration to indicate
+	// if the cluster uses the target service network only
+	ServiceNetworkMigrationOriginalRemoved string = "ServiceNetworkMigrationOriginalRemoved"
+)
+
 // Status condition types of network.config for live migration
 const (
 	// NetworkTypeMigrationInProgress is the condition type for network type live migration to indicate if the migration
@@ -270,3 +382,15 @@ const DefaultFieldManagerPrefix = "network-operator"
 
 // NetworkDiagnosticsAvailableCondition is the condition type for network diagnostics availability
 const NetworkDiagnosticsAvailableCondition string = "NetworkDiagnosticsAvailable"
+
+// OperatorConfigValidCondition is the condition type on the operator configuration
+// that lists the invalid fields of its spec
+const OperatorConfigValidCondition string = "OperatorConfigValid"
+
+// NodeSubnetsUpToDateCondition is the condition type on the operator configuration
+// that lists the nodes whose pod subnet was allocated with a previous hostPrefix
+const NodeSubnetsUpToDateCondition string = "NodeSubnetsUpToDate"
+
+// OVNNodeSubnetsAnnotation is the node annotation in which ovn-kubernetes
+// stores the pod subnets allocated to the node
+const OVNNodeSubnetsAnnotation = "k8s.ovn.org/node-subnets"
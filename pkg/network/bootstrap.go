@@ -26,12 +26,10 @@ func Bootstrap(conf *operv1.Network, client cnoclient.Client) (*bootstrap.Bootst
 	}
 	out.Infra = *infraStatus
 
-	if conf.Spec.DefaultNetwork.Type == operv1.NetworkTypeOVNKubernetes || (conf.Spec.Migration != nil && conf.Spec.Migration.Mode == operv1.LiveNetworkMigrationMode) {
-		o, err := bootstrapOVN(conf, client, infraStatus)
-		if err != nil {
+	for _, networkType := range activeNetworkTypes(&conf.Spec) {
+		if err := GetNetworkPlugin(networkType).Bootstrap(conf, client, out); err != nil {
 			return nil, err
 		}
-		out.OVN = *o
 	}
 
 	out.IPTablesAlerter = iptablesAlerterBootstrap(client.ClientFor("").CRClient())
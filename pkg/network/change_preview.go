@@ -0,0 +1,115 @@
+package network
+
+import (
+	"fmt"
+	"reflect"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+)
+
+// ChangeVerdict classifies a proposed change of the operator configuration.
+type ChangeVerdict string
+
+const (
+	// ChangeSafe changes are rolled out by the operator without disruption.
+	ChangeSafe ChangeVerdict = "Safe"
+	// ChangeRequiresReboot changes are rolled out with new MachineConfigs, so
+	// every node is rebooted.
+	ChangeRequiresReboot ChangeVerdict = "RequiresReboot"
+	// ChangeRequiresMigration changes switch the default network type, which
+	// is only done through the network migration procedure.
+	ChangeRequiresMigration ChangeVerdict = "RequiresMigration"
+	// ChangeForbidden changes are invalid or unsafe, and would not be applied
+	// by the operator.
+	ChangeForbidden ChangeVerdict = "Forbidden"
+)
+
+// ChangePreview is the verdict on a proposed change of the operator
+// configuration, with the reasons for it.
+type ChangePreview struct {
+	Verdict ChangeVerdict `json:"verdict"`
+	Reasons []string      `json:"reasons,omitempty"`
+}
+
+// PreviewChange tells whether the operator would apply next, given the
+// previously applied configuration prev (nil if none) and the infrastructure
+// status, and how disruptive the change would be. It runs the same checks as
+// the operconfig controller: canonicalization, validation, defaulting and
+// IsChangeSafe. Neither prev nor next are modified.
+func PreviewChange(prev, next *operv1.NetworkSpec, infraStatus *bootstrap.InfraStatus, hostMTU int) *ChangePreview {
+	next = next.DeepCopy()
+	DeprecatedCanonicalize(next)
+
+	if errs := ValidateFields(next); len(errs) > 0 {
+		preview := &ChangePreview{Verdict: ChangeForbidden}
+		for _, err := range errs {
+			preview.Reasons = append(preview.Reasons, err.Error())
+		}
+		return preview
+	}
+
+	if prev == nil {
+		return &ChangePreview{Verdict: ChangeSafe, Reasons: []string{"no configuration has been applied yet"}}
+	}
+	prev = prev.DeepCopy()
+	FillDefaults(prev, prev, hostMTU)
+	FillDefaults(next, prev, hostMTU)
+
+	if errs := changeSafetyErrors(prev, next, infraStatus); len(errs) > 0 {
+		preview := &ChangePreview{Verdict: ChangeForbidden}
+		for _, err := range errs {
+			preview.Reasons = append(preview.Reasons, err.Error())
+		}
+		return preview
+	}
+
+	preview := &ChangePreview{Verdict: ChangeSafe}
+	if reason := networkTypeMigrationReason(prev, next); reason != "" {
+		preview.Verdict = ChangeRequiresMigration
+		preview.Reasons = append(preview.Reasons, reason)
+	}
+	for _, reason := range rebootReasons(prev, next, infraStatus) {
+		if preview.Verdict == ChangeSafe {
+			preview.Verdict = ChangeRequiresReboot
+		}
+		preview.Reasons = append(preview.Reasons, reason)
+	}
+	return preview
+}
+
+// networkTypeMigrationReason returns why the change between prev and next is
+// part of a network type migration, if it is.
+func networkTypeMigrationReason(prev, next *operv1.NetworkSpec) string {
+	if prev.DefaultNetwork.Type != next.DefaultNetwork.Type {
+		return fmt.Sprintf("the default network type changes from %s to %s", prev.DefaultNetwork.Type, next.DefaultNetwork.Type)
+	}
+	if next.Migration != nil && next.Migration.NetworkType != "" &&
+		(prev.Migration == nil || prev.Migration.NetworkType != next.Migration.NetworkType) {
+		return fmt.Sprintf("a migration to %s is requested", next.Migration.NetworkType)
+	}
+	return ""
+}
+
+// rebootReasons returns the parts of the change between prev and next that are
+// rolled out by the machine-config-operator, which reboots every node.
+func rebootReasons(prev, next *operv1.NetworkSpec, infraStatus *bootstrap.InfraStatus) []string {
+	reasons := []string{}
+	if next.Migration != nil && next.Migration.MTU != nil &&
+		(prev.Migration == nil || !reflect.DeepEqual(prev.Migration.MTU, next.Migration.MTU)) {
+		reasons = append(reasons, "the MTU migration is rolled out with new MachineConfigs")
+	}
+
+	// The IPsec MachineConfigs are not used by hosted clusters
+	pn, nn := prev.DefaultNetwork.OVNKubernetesConfig, next.DefaultNetwork.OVNKubernetesConfig
+	if pn != nil && nn != nil && infraStatus.HostedControlPlane == nil {
+		prevEnabled := GetIPsecMode(pn) != operv1.IPsecModeDisabled
+		nextEnabled := GetIPsecMode(nn) != operv1.IPsecModeDisabled
+		if !prevEnabled && nextEnabled {
+			reasons = append(reasons, "enabling IPsec rolls out new MachineConfigs")
+		} else if prevEnabled && !nextEnabled {
+			reasons = append(reasons, "disabling IPsec removes MachineConfigs")
+		}
+	}
+	return reasons
+}
@@ -0,0 +1,106 @@
+package network
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	operv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestPreviewChange(t *testing.T) {
+	infra := &fakeBootstrapResult().Infra
+
+	testCases := []struct {
+		name     string
+		prev     *operv1.NetworkSpec
+		next     func(*operv1.NetworkSpec)
+		expected *ChangePreview
+	}{
+		{
+			name:     "first configuration",
+			next:     func(next *operv1.NetworkSpec) {},
+			expected: &ChangePreview{Verdict: ChangeSafe, Reasons: []string{"no configuration has been applied yet"}},
+		},
+		{
+			name:     "no change",
+			prev:     OVNKubernetesConfig.Spec.DeepCopy(),
+			next:     func(next *operv1.NetworkSpec) {},
+			expected: &ChangePreview{Verdict: ChangeSafe},
+		},
+		{
+			name: "invalid configuration",
+			prev: OVNKubernetesConfig.Spec.DeepCopy(),
+			next: func(next *operv1.NetworkSpec) {
+				next.ClusterNetwork[0].HostPrefix = 12
+			},
+			expected: &ChangePreview{Verdict: ChangeForbidden, Reasons: []string{
+				"spec.clusterNetwork[0].hostPrefix: Invalid value: 12: is larger than its cidr 10.128.0.0/15",
+			}},
+		},
+		{
+			name: "unsafe changes",
+			prev: OVNKubernetesConfig.Spec.DeepCopy(),
+			next: func(next *operv1.NetworkSpec) {
+				next.ServiceNetwork = []string{"172.31.0.0/16"}
+				next.DefaultNetwork.OVNKubernetesConfig.MTU = ptrToUint32(1300)
+			},
+			expected: &ChangePreview{Verdict: ChangeForbidden, Reasons: []string{
+				"unsupported change to ServiceNetwork",
+				"cannot change ovn-kubernetes MTU without migration",
+			}},
+		},
+		{
+			name: "network type migration",
+			prev: func() *operv1.NetworkSpec {
+				prev := OpenShiftSDNConfig.Spec.DeepCopy()
+				prev.Migration = &operv1.NetworkMigration{NetworkType: string(operv1.NetworkTypeOVNKubernetes)}
+				return prev
+			}(),
+			next: func(next *operv1.NetworkSpec) {
+				next.DefaultNetwork = *OVNKubernetesConfig.Spec.DefaultNetwork.DeepCopy()
+			},
+			expected: &ChangePreview{Verdict: ChangeRequiresMigration, Reasons: []string{
+				"the default network type changes from OpenShiftSDN to OVNKubernetes",
+			}},
+		},
+		{
+			name: "MTU migration",
+			prev: OVNKubernetesConfig.Spec.DeepCopy(),
+			next: func(next *operv1.NetworkSpec) {
+				next.Migration = &operv1.NetworkMigration{MTU: &operv1.MTUMigration{
+					Network: &operv1.MTUMigrationValues{From: ptrToUint32(1400), To: ptrToUint32(1300)},
+					Machine: &operv1.MTUMigrationValues{To: ptrToUint32(1500)},
+				}}
+			},
+			expected: &ChangePreview{Verdict: ChangeRequiresReboot, Reasons: []string{
+				"the MTU migration is rolled out with new MachineConfigs",
+			}},
+		},
+		{
+			name: "enable IPsec",
+			prev: OVNKubernetesConfig.Spec.DeepCopy(),
+			next: func(next *operv1.NetworkSpec) {
+				next.DefaultNetwork.OVNKubernetesConfig.IPsecConfig = &operv1.IPsecConfig{Mode: operv1.IPsecModeFull}
+			},
+			expected: &ChangePreview{Verdict: ChangeRequiresReboot, Reasons: []string{
+				"enabling IPsec rolls out new MachineConfigs",
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			next := OVNKubernetesConfig.Spec.DeepCopy()
+			if tc.prev != nil {
+				next = tc.prev.DeepCopy()
+			}
+			tc.next(next)
+			orig := next.DeepCopy()
+
+			g.Expect(PreviewChange(tc.prev, next, infra, 1500)).To(Equal(tc.expected))
+			g.Expect(next).To(Equal(orig))
+		})
+	}
+}
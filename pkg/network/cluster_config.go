@@ -308,20 +308,19 @@ func MergeClusterConfig(operConf *operv1.NetworkSpec, clusterConf configv1.Netwo
 
 // StatusFromOperatorConfig generates the cluster NetworkStatus from the
 // currently applied operator configuration.
-func StatusFromOperatorConfig(operConf *operv1.NetworkSpec, oldStatus *configv1.NetworkStatus) *configv1.NetworkStatus {
-	knownNetworkType := true
+func StatusFromOperatorConfig(operConf *operv1.NetworkSpec, bootstrapResult *bootstrap.BootstrapResult, oldStatus *configv1.NetworkStatus) *configv1.NetworkStatus {
 	status := configv1.NetworkStatus{}
 
-	switch operConf.DefaultNetwork.Type {
-	case operv1.NetworkTypeOpenShiftSDN:
-		// continue
-	case operv1.NetworkTypeOVNKubernetes:
-		// continue
-	default:
-		knownNetworkType = false
+	// Let the plugin fill in its fields, such as the MTU
+	knownNetworkType := GetNetworkPlugin(operConf.DefaultNetwork.Type).Status(operConf, bootstrapResult, &status)
+	if !knownNetworkType {
 		// Preserve any status fields set by the unknown network plugin
 		status = *oldStatus
 	}
+	if bootstrapResult.ExternalNetwork.Enabled && status.ClusterNetworkMTU == 0 {
+		// The MTU of the external network is unknown, keep the current one
+		status.ClusterNetworkMTU = oldStatus.ClusterNetworkMTU
+	}
 
 	if oldStatus.NetworkType == "" || knownNetworkType {
 		status.NetworkType = string(operConf.DefaultNetwork.Type)
@@ -343,14 +342,6 @@ func StatusFromOperatorConfig(operConf *operv1.NetworkSpec, oldStatus *configv1.
 		}
 	}
 
-	// Determine the MTU from the provider
-	switch operConf.DefaultNetwork.Type {
-	case operv1.NetworkTypeOpenShiftSDN:
-		status.ClusterNetworkMTU = int(*operConf.DefaultNetwork.OpenShiftSDNConfig.MTU)
-	case operv1.NetworkTypeOVNKubernetes:
-		status.ClusterNetworkMTU = int(*operConf.DefaultNetwork.OVNKubernetesConfig.MTU)
-	}
-
 	// Set migration in the config status
 	if operConf.Migration != nil {
 		if operConf.Migration.Mode == operv1.LiveNetworkMigrationMode {
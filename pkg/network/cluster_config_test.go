@@ -442,7 +442,7 @@ func TestStatusFromConfig(t *testing.T) {
 	var mtu uint32 = 1300
 	crd.Spec.DefaultNetwork.OVNKubernetesConfig.MTU = &mtu
 
-	status := StatusFromOperatorConfig(&crd.Spec, &configv1.NetworkStatus{})
+	status := StatusFromOperatorConfig(&crd.Spec, &bootstrap.BootstrapResult{}, &configv1.NetworkStatus{})
 	g.Expect(status).To(Equal(&configv1.NetworkStatus{
 		ClusterNetwork: []configv1.ClusterNetworkEntry{
 			{
@@ -461,7 +461,7 @@ func TestStatusFromConfig(t *testing.T) {
 	}))
 
 	*crd.Spec.DefaultNetwork.OVNKubernetesConfig.MTU = 1500
-	status = StatusFromOperatorConfig(&crd.Spec, status)
+	status = StatusFromOperatorConfig(&crd.Spec, &bootstrap.BootstrapResult{}, status)
 	g.Expect(status).To(Equal(&configv1.NetworkStatus{
 		ClusterNetwork: []configv1.ClusterNetworkEntry{
 			{
@@ -484,7 +484,7 @@ func TestStatusFromConfig(t *testing.T) {
 	status.ServiceNetwork = []string{"172.30.0.0/17"}
 	status.ClusterNetworkMTU = 1450
 
-	status = StatusFromOperatorConfig(&crd.Spec, status)
+	status = StatusFromOperatorConfig(&crd.Spec, &bootstrap.BootstrapResult{}, status)
 	g.Expect(status).To(Equal(&configv1.NetworkStatus{
 		ClusterNetwork: []configv1.ClusterNetworkEntry{
 			{
@@ -511,7 +511,7 @@ func TestStatusFromConfigUnknown(t *testing.T) {
 
 	crd.Spec.DefaultNetwork.Type = "None"
 
-	status := StatusFromOperatorConfig(&crd.Spec, &configv1.NetworkStatus{})
+	status := StatusFromOperatorConfig(&crd.Spec, &bootstrap.BootstrapResult{}, &configv1.NetworkStatus{})
 	g.Expect(status).To(Equal(&configv1.NetworkStatus{
 		ClusterNetwork: []configv1.ClusterNetworkEntry{
 			{
@@ -535,7 +535,7 @@ func TestStatusFromConfigUnknown(t *testing.T) {
 	status.ClusterNetworkMTU = 1450
 
 	// The external changes should be preserved
-	status = StatusFromOperatorConfig(&crd.Spec, status)
+	status = StatusFromOperatorConfig(&crd.Spec, &bootstrap.BootstrapResult{}, status)
 	g.Expect(status).To(Equal(&configv1.NetworkStatus{
 		ClusterNetwork: []configv1.ClusterNetworkEntry{
 			{
@@ -0,0 +1,104 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+	"github.com/openshift/cluster-network-operator/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ExternalNetworkConfigMapName is the ConfigMap, in the operator namespace,
+	// that enables the external network mode for default network types that
+	// are not deployed by the operator.
+	ExternalNetworkConfigMapName = "external-network-config"
+	// externalNetworkDaemonSetsKey is the ConfigMap key holding the comma-separated
+	// list of namespace/name of the DaemonSets of the third-party CNI
+	externalNetworkDaemonSetsKey = "daemonsets"
+	// externalNetworkCNIConfigFileKey is the ConfigMap key holding the name of the
+	// CNI configuration file written by the third-party CNI
+	externalNetworkCNIConfigFileKey = "cni-config-file"
+	// externalNetworkCNIConfigDirKey is the ConfigMap key holding the host
+	// directory the third-party CNI writes its configuration file to
+	externalNetworkCNIConfigDirKey = "cni-config-dir"
+	// externalNetworkMTUKey is the ConfigMap key holding the MTU of the cluster network
+	externalNetworkMTUKey = "mtu"
+)
+
+// bootstrapExternalNetwork reads the external-network-config ConfigMap. The
+// MTU defaults to the host MTU found by the MTU prober.
+func bootstrapExternalNetwork(kubeClient cnoclient.Client) (*bootstrap.ExternalNetworkBootstrapResult, error) {
+	cm := &corev1.ConfigMap{}
+	nsn := types.NamespacedName{Namespace: names.APPLIED_NAMESPACE, Name: ExternalNetworkConfigMapName}
+	if err := kubeClient.ClientFor("").CRClient().Get(context.TODO(), nsn, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &bootstrap.ExternalNetworkBootstrapResult{}, nil
+		}
+		return nil, fmt.Errorf("could not retrieve %s ConfigMap: %w", ExternalNetworkConfigMapName, err)
+	}
+	res, err := parseExternalNetworkConfig(cm.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s ConfigMap: %w", ExternalNetworkConfigMapName, err)
+	}
+
+	if res.MTU == 0 {
+		mtu, err := util.ReadMTUConfigMap(context.TODO(), kubeClient)
+		if err != nil {
+			klog.Warningf("Could not determine the MTU of the external network: %v", err)
+		} else {
+			res.MTU = mtu
+		}
+	}
+	return res, nil
+}
+
+// parseExternalNetworkConfig parses the data of the external-network-config ConfigMap.
+func parseExternalNetworkConfig(data map[string]string) (*bootstrap.ExternalNetworkBootstrapResult, error) {
+	res := &bootstrap.ExternalNetworkBootstrapResult{Enabled: true}
+
+	for _, ds := range strings.Split(data[externalNetworkDaemonSetsKey], ",") {
+		ds = strings.TrimSpace(ds)
+		if ds == "" {
+			continue
+		}
+		namespace, name, ok := strings.Cut(ds, "/")
+		if !ok || namespace == "" || name == "" {
+			return nil, fmt.Errorf("%s: %q is not of the form namespace/name", externalNetworkDaemonSetsKey, ds)
+		}
+		res.DaemonSets = append(res.DaemonSets, types.NamespacedName{Namespace: namespace, Name: name})
+	}
+
+	if file := data[externalNetworkCNIConfigFileKey]; file != "" {
+		if file != filepath.Base(file) {
+			return nil, fmt.Errorf("%s: %q must be a file name, not a path", externalNetworkCNIConfigFileKey, file)
+		}
+		res.CNIConfigFile = file
+	}
+
+	if dir := data[externalNetworkCNIConfigDirKey]; dir != "" {
+		if !filepath.IsAbs(dir) {
+			return nil, fmt.Errorf("%s: %q must be an absolute path", externalNetworkCNIConfigDirKey, dir)
+		}
+		res.CNIConfigDir = filepath.Clean(dir)
+	}
+
+	if mtu := data[externalNetworkMTUKey]; mtu != "" {
+		val, err := strconv.Atoi(mtu)
+		if err != nil || val <= 0 {
+			return nil, fmt.Errorf("%s: %q is not a valid MTU", externalNetworkMTUKey, mtu)
+		}
+		res.MTU = val
+	}
+	return res, nil
+}
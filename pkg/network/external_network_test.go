@@ -0,0 +1,148 @@
+package network
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestParseExternalNetworkConfig(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     map[string]string
+		expected *bootstrap.ExternalNetworkBootstrapResult
+		err      string
+	}{
+		{
+			name:     "empty",
+			data:     map[string]string{},
+			expected: &bootstrap.ExternalNetworkBootstrapResult{Enabled: true},
+		},
+		{
+			name: "all keys",
+			data: map[string]string{
+				"daemonsets":      "kube-system/cilium, kube-system/cilium-envoy",
+				"cni-config-file": "05-cilium.conflist",
+				"cni-config-dir":  "/etc/kubernetes/cni/net.d/",
+				"mtu":             "1450",
+			},
+			expected: &bootstrap.ExternalNetworkBootstrapResult{
+				Enabled: true,
+				DaemonSets: []types.NamespacedName{
+					{Namespace: "kube-system", Name: "cilium"},
+					{Namespace: "kube-system", Name: "cilium-envoy"},
+				},
+				CNIConfigFile: "05-cilium.conflist",
+				CNIConfigDir:  "/etc/kubernetes/cni/net.d",
+				MTU:           1450,
+			},
+		},
+		{
+			name: "daemonset without namespace",
+			data: map[string]string{"daemonsets": "cilium"},
+			err:  "not of the form namespace/name",
+		},
+		{
+			name: "cni config path",
+			data: map[string]string{"cni-config-file": "/etc/cni/net.d/05-cilium.conflist"},
+			err:  "must be a file name",
+		},
+		{
+			name: "relative cni config dir",
+			data: map[string]string{"cni-config-dir": "cni/net.d"},
+			err:  "must be an absolute path",
+		},
+		{
+			name: "invalid mtu",
+			data: map[string]string{"mtu": "-1"},
+			err:  "not a valid MTU",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			res, err := parseExternalNetworkConfig(tc.data)
+			if tc.err != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.err)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(res).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestExternalNetworkStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	conf := &operv1.NetworkSpec{
+		ServiceNetwork: []string{"172.30.0.0/16"},
+		ClusterNetwork: []operv1.ClusterNetworkEntry{{CIDR: "10.128.0.0/15", HostPrefix: 23}},
+		DefaultNetwork: operv1.DefaultNetworkDefinition{Type: "Cilium"},
+	}
+	oldStatus := &configv1.NetworkStatus{
+		NetworkType:       "Cilium",
+		ServiceNetwork:    []string{"172.31.0.0/16"},
+		ClusterNetwork:    []configv1.ClusterNetworkEntry{{CIDR: "10.0.0.0/14", HostPrefix: 24}},
+		ClusterNetworkMTU: 1400,
+	}
+
+	// Without the external network mode, the status is left to the third party
+	status := StatusFromOperatorConfig(conf, &bootstrap.BootstrapResult{}, oldStatus)
+	g.Expect(status).To(Equal(oldStatus))
+
+	// With the external network mode, the operator owns the status
+	bootstrapResult := &bootstrap.BootstrapResult{
+		ExternalNetwork: bootstrap.ExternalNetworkBootstrapResult{Enabled: true, MTU: 1450},
+	}
+	status = StatusFromOperatorConfig(conf, bootstrapResult, oldStatus)
+	g.Expect(status.NetworkType).To(Equal("Cilium"))
+	g.Expect(status.ServiceNetwork).To(Equal([]string{"172.30.0.0/16"}))
+	g.Expect(status.ClusterNetwork).To(Equal([]configv1.ClusterNetworkEntry{{CIDR: "10.128.0.0/15", HostPrefix: 23}}))
+	g.Expect(status.ClusterNetworkMTU).To(Equal(1450))
+
+	// An unknown MTU doesn't overwrite the one reported by the third party
+	bootstrapResult.ExternalNetwork.MTU = 0
+	status = StatusFromOperatorConfig(conf, bootstrapResult, oldStatus)
+	g.Expect(status.ClusterNetworkMTU).To(Equal(1400))
+}
+
+func TestRenderMultusExternalNetwork(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	crd := MultusConfig.DeepCopy()
+	config := &crd.Spec
+	config.DefaultNetwork.Type = "Cilium"
+	fillDefaults(config, nil)
+
+	bootstrapResult := fakeBootstrapResult()
+	bootstrapResult.ExternalNetwork = bootstrap.ExternalNetworkBootstrapResult{Enabled: true, CNIConfigFile: "05-cilium.conflist"}
+	g.Expect(renderMultusDaemonConfig(g, config, bootstrapResult)).To(
+		ContainSubstring(`"readinessindicatorfile": "/hostroot/var/run/multus/cni/net.d/05-cilium.conflist"`))
+
+	// multus waits for the file in the directory the third-party CNI writes to
+	bootstrapResult.ExternalNetwork.CNIConfigDir = "/etc/kubernetes/cni/net.d"
+	g.Expect(renderMultusDaemonConfig(g, config, bootstrapResult)).To(
+		ContainSubstring(`"readinessindicatorfile": "/hostroot/etc/kubernetes/cni/net.d/05-cilium.conflist"`))
+}
+
+func renderMultusDaemonConfig(g *WithT, config *operv1.NetworkSpec, bootstrapResult *bootstrap.BootstrapResult) string {
+	objs, err := renderMultus(config, bootstrapResult, manifestDir)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	var daemonConfig string
+	for _, obj := range objs {
+		if obj.GetKind() == "ConfigMap" && obj.GetName() == "multus-daemon-config" {
+			daemonConfig, _, _ = uns.NestedString(obj.Object, "data", "daemon-config.json")
+		}
+	}
+	return daemonConfig
+}
@@ -1,6 +1,7 @@
 package network
 
 import (
+	"fmt"
 	v1 "github.com/openshift/api/config/v1"
 	"github.com/openshift/cluster-network-operator/pkg/hypershift"
 	"net"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/pkg/errors"
 	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	operv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
@@ -71,30 +73,32 @@ func noKubeProxyConfig(conf *operv1.NetworkSpec) bool {
 }
 
 // validateKubeProxy checks that the kube-proxy specific configuration is basically sane.
-func validateKubeProxy(conf *operv1.NetworkSpec) []error {
-	out := []error{}
+func validateKubeProxy(conf *operv1.NetworkSpec) field.ErrorList {
+	out := field.ErrorList{}
 	p := conf.KubeProxyConfig
 	if p == nil {
 		return out
 	}
+	path := field.NewPath("spec", "kubeProxyConfig")
+	argsPath := path.Child("proxyArguments")
 	if !acceptsKubeProxyConfig(conf) {
 		if noKubeProxyConfig(conf) {
 			return out
 		}
-		out = append(out, errors.Errorf("network type %q does not allow specifying kube-proxy options", conf.DefaultNetwork.Type))
+		out = append(out, field.Forbidden(path, fmt.Sprintf("network type %q does not allow specifying kube-proxy options", conf.DefaultNetwork.Type)))
 		return out
 	}
 
 	if p.IptablesSyncPeriod != "" {
 		_, err := time.ParseDuration(p.IptablesSyncPeriod)
 		if err != nil {
-			out = append(out, errors.Errorf("IptablesSyncPeriod is not a valid duration (%v)", err))
+			out = append(out, field.Invalid(path.Child("iptablesSyncPeriod"), p.IptablesSyncPeriod, fmt.Sprintf("not a valid duration (%v)", err)))
 		}
 	}
 
 	if p.BindAddress != "" {
 		if net.ParseIP(p.BindAddress) == nil {
-			out = append(out, errors.Errorf("BindAddress must be a valid IP address"))
+			out = append(out, field.Invalid(path.Child("bindAddress"), p.BindAddress, "must be a valid IP address"))
 		}
 	}
 
@@ -104,16 +108,16 @@ func validateKubeProxy(conf *operv1.NetworkSpec) []error {
 	if p.ProxyArguments != nil {
 		if val, ok := p.ProxyArguments["metrics-port"]; ok {
 			if len(val) != 1 || val[0] != "9101" {
-				out = append(out, errors.Errorf("kube-proxy --metrics-port cannot be overridden"))
+				out = append(out, field.Forbidden(argsPath.Key("metrics-port"), "kube-proxy --metrics-port cannot be overridden"))
 			}
 		}
 		if val, ok := p.ProxyArguments["healthz-port"]; ok {
 			if len(val) != 1 || val[0] != "10256" {
-				out = append(out, errors.Errorf("kube-proxy --healthz-port cannot be overridden"))
+				out = append(out, field.Forbidden(argsPath.Key("healthz-port"), "kube-proxy --healthz-port cannot be overridden"))
 			}
 		}
 		if _, ok := p.ProxyArguments["feature-gates"]; ok {
-			out = append(out, errors.Errorf("kube-proxy --feature-gates cannot be overridden"))
+			out = append(out, field.Forbidden(argsPath.Key("feature-gates"), "kube-proxy --feature-gates cannot be overridden"))
 		}
 	}
 
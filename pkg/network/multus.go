@@ -86,6 +86,13 @@ func renderMultusConfig(manifestDir, defaultNetworkType string, useDHCP bool, us
 	data.Data["MultusCNIConfDir"] = MultusCNIConfDir
 	data.Data["SystemCNIConfDir"] = SystemCNIConfDir
 	data.Data["DefaultNetworkType"] = defaultNetworkType
+	// The third-party CNI of the external network mode writes its configuration
+	// to the directory it is configured with, by default where multus looks for it
+	data.Data["ExternalCNIConfigFile"] = bootstrapResult.ExternalNetwork.CNIConfigFile
+	data.Data["ExternalCNIConfigDir"] = MultusCNIConfDir
+	if bootstrapResult.ExternalNetwork.CNIConfigDir != "" {
+		data.Data["ExternalCNIConfigDir"] = bootstrapResult.ExternalNetwork.CNIConfigDir
+	}
 	data.Data["MultusSocketParentDir"] = MultusSocketParentDir
 	data.Data["CNIBinDir"] = CNIBinDir
 	data.Data["CniSysctlAllowlist"] = "default-cni-sysctl-allowlist"
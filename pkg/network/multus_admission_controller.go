@@ -140,6 +140,30 @@ func renderMultusAdmissonControllerConfig(manifestDir string, externalControlPla
 			}
 		}
 
+		// The scheduling options of the HostedControlPlane take precedence
+		hcp := bootstrapResult.Infra.HostedControlPlane
+		requests := hcp.ResourceRequests("multus-admission-controller")
+		if cpu, ok := hcp.ResourceRequestOverrides["multus-admission-controller.multus-admission-controller"][v1.ResourceCPU]; ok {
+			data.Data["ResourceRequestCPU"] = cpu.MilliValue()
+		}
+		if memory, ok := hcp.ResourceRequestOverrides["multus-admission-controller.multus-admission-controller"][v1.ResourceMemory]; ok {
+			data.Data["ResourceRequestMemory"] = memory.Value() / bytesInMiB
+		}
+		data.Data["HCPResourceRequests"] = requests
+		data.Data["HCPAnnotations"] = hcp.Annotations
+		data.Data["HCPTopologySpreadConstraints"], err = hcp.TopologySpreadConstraintsYaml(map[string]string{"app": "multus-admission-controller"})
+		if err != nil {
+			return nil, fmt.Errorf("failed to yaml marshal topology spread constraints: %v", err)
+		}
+		data.Data["HCPPodAffinity"], err = hcp.PodAffinityYaml()
+		if err != nil {
+			return nil, fmt.Errorf("failed to yaml marshal pod affinity: %v", err)
+		}
+		data.Data["HCPPodAntiAffinity"], err = hcp.PodAntiAffinityYaml()
+		if err != nil {
+			return nil, fmt.Errorf("failed to yaml marshal pod anti-affinity: %v", err)
+		}
+
 		data.Data["ReleaseImage"] = hsc.ReleaseImage
 	}
 
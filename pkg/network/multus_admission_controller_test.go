@@ -9,8 +9,11 @@ import (
 	"github.com/openshift/cluster-network-operator/pkg/names"
 
 	cnofake "github.com/openshift/cluster-network-operator/pkg/client/fake"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 var MultusAdmissionControllerConfig = operv1.Network{
@@ -160,6 +163,67 @@ func TestRenderMultusAdmissonControllerConfigForHyperShift(t *testing.T) {
 	}
 }
 
+func TestRenderMultusAdmissonControllerHyperShiftSchedulingOptions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	fakeClient := cnofake.NewFakeClient(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "MyCM", Namespace: "clusters-test"},
+		Data:       map[string]string{"MyCMKey": "key"},
+	})
+	bootstrap := fakeBootstrapResultWithHyperShift()
+	bootstrap.Infra.HostedControlPlane.Annotations = map[string]string{"example.com/team": "network", "example.com/config": `{"quote": "a \"b\": c"}`}
+	bootstrap.Infra.HostedControlPlane.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+		MaxSkew:           1,
+		TopologyKey:       "topology.kubernetes.io/zone",
+		WhenUnsatisfiable: corev1.ScheduleAnyway,
+		LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "custom"}},
+	}}
+	bootstrap.Infra.HostedControlPlane.PodAffinity = &corev1.PodAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "kube-apiserver"}},
+			TopologyKey:   "topology.kubernetes.io/zone",
+		}},
+	}
+	bootstrap.Infra.HostedControlPlane.ResourceRequestOverrides = map[string]corev1.ResourceList{
+		"multus-admission-controller.multus-admission-controller": {corev1.ResourceMemory: resource.MustParse("1Gi")},
+		"multus-admission-controller.hosted-cluster-token":        {corev1.ResourceCPU: resource.MustParse("20m")},
+	}
+
+	hsc := hypershift.NewHyperShiftConfig()
+	hsc.Enabled = true
+	hsc.CAConfigMap = "MyCM"
+	hsc.CAConfigMapKey = "MyCMKey"
+	hsc.Namespace = "clusters-test"
+	hsc.ReleaseImage = "MyImage"
+
+	objs, err := renderMultusAdmissonControllerConfig(manifestDir, false, bootstrap, fakeClient, hsc, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	obj := findInObjs("apps", "Deployment", "multus-admission-controller", "clusters-test", objs)
+	g.Expect(obj).NotTo(BeNil())
+	deployment := &appsv1.Deployment{}
+	g.Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deployment)).To(Succeed())
+	podSpec := deployment.Spec.Template.Spec
+
+	g.Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue("example.com/team", "network"))
+	g.Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue("example.com/config", `{"quote": "a \"b\": c"}`))
+	g.Expect(podSpec.TopologySpreadConstraints).To(Equal(bootstrap.Infra.HostedControlPlane.TopologySpreadConstraints))
+	g.Expect(podSpec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(Equal(
+		bootstrap.Infra.HostedControlPlane.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution))
+	// The default affinity is kept
+	g.Expect(podSpec.Affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+	for _, container := range podSpec.Containers {
+		cpu, memory := container.Resources.Requests[corev1.ResourceCPU], container.Resources.Requests[corev1.ResourceMemory]
+		switch container.Name {
+		case "multus-admission-controller":
+			g.Expect(cpu.String()).To(Equal("10m"))
+			g.Expect(memory.String()).To(Equal("1Gi"))
+		case "hosted-cluster-token":
+			g.Expect(cpu.String()).To(Equal("20m"))
+			g.Expect(memory.String()).To(Equal("30Mi"))
+		}
+	}
+}
+
 // TestRenderMultusAdmissionControllerGetNamespace tests getOpenshiftNamespaces()
 func TestRenderMultusAdmissionControllerGetNamespace(t *testing.T) {
 	g := NewGomegaWithT(t)
@@ -1,6 +1,7 @@
 package network
 
 import (
+	"fmt"
 	"log"
 	"net"
 	"os"
@@ -11,16 +12,83 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
+	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	configv1 "github.com/openshift/api/config/v1"
+	apifeatures "github.com/openshift/api/features"
 	netv1 "github.com/openshift/api/network/v1"
 	operv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/cluster-network-operator/pkg/render"
 )
 
+func init() {
+	RegisterNetworkPlugin(operv1.NetworkTypeOpenShiftSDN, openShiftSDNPlugin{})
+}
+
+// openShiftSDNPlugin is the NetworkPlugin for OpenShiftSDN. It is no longer
+// supported as the default network, but is still rendered during live migration.
+type openShiftSDNPlugin struct{}
+
+func (openShiftSDNPlugin) Validate(conf *operv1.NetworkSpec) field.ErrorList {
+	return field.ErrorList{field.Invalid(field.NewPath("spec", "defaultNetwork", "type"), conf.DefaultNetwork.Type,
+		fmt.Sprintf("unsupported network type %q", conf.DefaultNetwork.Type))}
+}
+
+func (openShiftSDNPlugin) FillDefaults(conf, previous *operv1.NetworkSpec, hostMTU int) {
+	fillOpenShiftSDNDefaults(conf, previous, hostMTU)
+}
+
+func (openShiftSDNPlugin) DropConfig(conf *operv1.NetworkSpec) {
+	conf.DefaultNetwork.OpenShiftSDNConfig = nil
+}
+
+func (openShiftSDNPlugin) NeedsMTUProbe(conf *operv1.NetworkSpec) bool {
+	c := conf.DefaultNetwork.OpenShiftSDNConfig
+	return c == nil || c.MTU == nil || *c.MTU == 0
+}
+
+func (openShiftSDNPlugin) IsChangeSafe(prev, next *operv1.NetworkSpec) []error {
+	return isOpenShiftSDNChangeSafe(prev, next)
+}
+
+func (openShiftSDNPlugin) Bootstrap(conf *operv1.Network, client cnoclient.Client, out *bootstrap.BootstrapResult) error {
+	return nil
+}
+
+func (openShiftSDNPlugin) Render(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.BootstrapResult, manifestDir string,
+	client cnoclient.Client, featureGates featuregates.FeatureGate) ([]*uns.Unstructured, bool, error) {
+	return renderOpenShiftSDN(conf, bootstrapResult, manifestDir)
+}
+
+// RenderMigrationCRDs generates the OVNKubernetes CRDs, for the migration to OVNKubernetes.
+func (openShiftSDNPlugin) RenderMigrationCRDs(conf *operv1.NetworkSpec, manifestDir string, featureGates featuregates.FeatureGate) ([]*uns.Unstructured, error) {
+	// When we migrate from SDN to OVNK, we must set the feature gate values so that
+	// the CRD installation can happen according to whether the feature gate is enabled or not
+	// in the cluster
+	data := render.MakeRenderData()
+	data.Data["OVN_ADMIN_NETWORK_POLICY_ENABLE"] = featureGates.Enabled(apifeatures.FeatureGateAdminNetworkPolicy)
+	data.Data["OVN_NETWORK_SEGMENTATION_ENABLE"] = featureGates.Enabled(apifeatures.FeatureGateNetworkSegmentation)
+	data.Data["OVN_OBSERVABILITY_ENABLE"] = featureGates.Enabled(apifeatures.FeatureGateOVNObservability)
+	data.Data["OVN_ROUTE_ADVERTISEMENTS_ENABLE"] = conf.DefaultNetwork.OVNKubernetesConfig != nil &&
+		conf.DefaultNetwork.OVNKubernetesConfig.RouteAdvertisements == operv1.RouteAdvertisementsEnabled
+
+	manifests, err := render.RenderTemplate(filepath.Join(manifestDir, "network/ovn-kubernetes/common/001-crd.yaml"), &data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render OVNKubernetes CRDs")
+	}
+	return manifests, err
+}
+
+func (openShiftSDNPlugin) Status(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.BootstrapResult, status *configv1.NetworkStatus) bool {
+	status.ClusterNetworkMTU = int(*conf.DefaultNetwork.OpenShiftSDNConfig.MTU)
+	return true
+}
+
 // renderOpenShiftSDN returns the manifests for the openshift-sdn.
 // This creates
 // - the ClusterNetwork object
@@ -0,0 +1,356 @@
+package network
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+
+	corev1 "k8s.io/api/core/v1"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// AllContainers is the container name of the overrides that apply to every
+// container of an operand.
+const AllContainers = "*"
+
+// OperandOverrides are the overrides of the DaemonSets and Deployments
+// rendered by the operator, by name.
+type OperandOverrides map[string]OperandOverride
+
+// OperandOverride is the override of the pod template of a DaemonSet or a
+// Deployment.
+type OperandOverride struct {
+	// Containers are the overrides of the containers, by name. The overrides
+	// of AllContainers apply to every container, before the overrides of the
+	// container itself.
+	Containers map[string]ContainerOverride `json:"containers,omitempty"`
+	// NodeSelector is merged into the node selector of the pods.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations are added to the tolerations of the pods.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// ContainerOverride is the override of a container.
+type ContainerOverride struct {
+	// Env sets environment variables, replacing the rendered variables with
+	// the same name.
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// Resources are merged into the resource requests and limits.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// LogLevel is the value of the *_LOG_LEVEL environment variable of the
+	// container.
+	LogLevel *string `json:"logLevel,omitempty"`
+}
+
+// ParseOperandOverrides decodes and validates the operand overrides in the
+// data of a ConfigMap, one YAML OperandOverride per operand name.
+func ParseOperandOverrides(data map[string]string) (OperandOverrides, field.ErrorList) {
+	dataPath := field.NewPath("data")
+	errs := field.ErrorList{}
+	overrides := OperandOverrides{}
+	for name, value := range data {
+		path := dataPath.Key(name)
+		for _, msg := range validation.IsDNS1123Subdomain(name) {
+			errs = append(errs, field.Invalid(path, name, msg))
+		}
+		override := OperandOverride{}
+		if err := sigsyaml.UnmarshalStrict([]byte(value), &override); err != nil {
+			errs = append(errs, field.Invalid(path, field.OmitValueType{}, err.Error()))
+			continue
+		}
+		errs = append(errs, validateOperandOverride(path, &override)...)
+		overrides[name] = override
+	}
+	return overrides, errs
+}
+
+// validateOperandOverride validates the fields of override
+func validateOperandOverride(path *field.Path, override *OperandOverride) field.ErrorList {
+	errs := field.ErrorList{}
+
+	for name, container := range override.Containers {
+		containerPath := path.Child("containers").Key(name)
+		if name != AllContainers {
+			for _, msg := range validation.IsDNS1123Label(name) {
+				errs = append(errs, field.Invalid(containerPath, name, msg))
+			}
+		}
+		for i, env := range container.Env {
+			envPath := containerPath.Child("env").Index(i)
+			for _, msg := range validation.IsEnvVarName(env.Name) {
+				errs = append(errs, field.Invalid(envPath.Child("name"), env.Name, msg))
+			}
+			if env.Value != "" && env.ValueFrom != nil {
+				errs = append(errs, field.Invalid(envPath, env.Name, "may not have both a value and valueFrom"))
+			}
+		}
+		if container.Resources != nil {
+			resourcesPath := containerPath.Child("resources")
+			if len(container.Resources.Claims) > 0 {
+				errs = append(errs, field.Forbidden(resourcesPath.Child("claims"), "resource claims can not be overridden"))
+			}
+			for resource, request := range container.Resources.Requests {
+				if limit, ok := container.Resources.Limits[resource]; ok && request.Cmp(limit) > 0 {
+					errs = append(errs, field.Invalid(resourcesPath.Child("requests").Key(string(resource)), request.String(),
+						fmt.Sprintf("must be less than or equal to the %s limit", resource)))
+				}
+			}
+		}
+		if container.LogLevel != nil && *container.LogLevel == "" {
+			errs = append(errs, field.Required(containerPath.Child("logLevel"), "may not be empty"))
+		}
+	}
+
+	for key, value := range override.NodeSelector {
+		selectorPath := path.Child("nodeSelector").Key(key)
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = append(errs, field.Invalid(selectorPath, key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(value) {
+			errs = append(errs, field.Invalid(selectorPath, value, msg))
+		}
+	}
+
+	for i, toleration := range override.Tolerations {
+		errs = append(errs, validateToleration(path.Child("tolerations").Index(i), &toleration)...)
+	}
+	return errs
+}
+
+// validateToleration does the validation of the apiserver for tolerations
+func validateToleration(path *field.Path, toleration *corev1.Toleration) field.ErrorList {
+	errs := field.ErrorList{}
+	if toleration.Key != "" {
+		for _, msg := range validation.IsQualifiedName(toleration.Key) {
+			errs = append(errs, field.Invalid(path.Child("key"), toleration.Key, msg))
+		}
+	}
+	switch toleration.Operator {
+	case corev1.TolerationOpEqual, "":
+		if toleration.Key == "" {
+			errs = append(errs, field.Invalid(path.Child("operator"), toleration.Operator, "must be Exists when key is empty"))
+		}
+		for _, msg := range validation.IsValidLabelValue(toleration.Value) {
+			errs = append(errs, field.Invalid(path.Child("value"), toleration.Value, msg))
+		}
+	case corev1.TolerationOpExists:
+		if toleration.Value != "" {
+			errs = append(errs, field.Invalid(path.Child("value"), toleration.Value, "must be empty when operator is Exists"))
+		}
+	default:
+		errs = append(errs, field.NotSupported(path.Child("operator"), toleration.Operator,
+			[]string{string(corev1.TolerationOpEqual), string(corev1.TolerationOpExists)}))
+	}
+	switch toleration.Effect {
+	case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		errs = append(errs, field.NotSupported(path.Child("effect"), toleration.Effect,
+			[]string{string(corev1.TaintEffectNoSchedule), string(corev1.TaintEffectPreferNoSchedule), string(corev1.TaintEffectNoExecute)}))
+	}
+	if toleration.TolerationSeconds != nil && toleration.Effect != corev1.TaintEffectNoExecute {
+		errs = append(errs, field.Invalid(path.Child("tolerationSeconds"), *toleration.TolerationSeconds, "may only be set when effect is NoExecute"))
+	}
+	return errs
+}
+
+// OperandOverridesError is returned by Render when the operand overrides are
+// invalid or cannot be applied to the rendered objects.
+type OperandOverridesError struct {
+	Err error
+}
+
+func (e *OperandOverridesError) Error() string {
+	return fmt.Sprintf("invalid operand overrides: %v", e.Err)
+}
+
+func (e *OperandOverridesError) Unwrap() error {
+	return e.Err
+}
+
+// renderOperandOverrides applies the operand overrides of the bootstrap result
+// to the rendered objects. Objects that already have their overrides are
+// skipped, so that the renderers that must apply the overrides early, such as
+// before ovnkube-node is split for canary rollouts, can do so.
+func renderOperandOverrides(objs []*uns.Unstructured, bootstrapResult *bootstrap.BootstrapResult) error {
+	if len(bootstrapResult.OperandOverrides.Data) == 0 {
+		return nil
+	}
+	overrides, errs := ParseOperandOverrides(bootstrapResult.OperandOverrides.Data)
+	if len(errs) > 0 {
+		return &OperandOverridesError{Err: errs.ToAggregate()}
+	}
+	if err := ApplyOperandOverrides(objs, overrides); err != nil {
+		return &OperandOverridesError{Err: err}
+	}
+	return nil
+}
+
+// ApplyOperandOverrides applies the overrides to the rendered DaemonSets and
+// Deployments, in place, and annotates them with the hash of their overrides.
+// Objects that are already annotated are skipped. Overrides of operands that
+// are not rendered are ignored; an override of a container that does not exist
+// is an error.
+func ApplyOperandOverrides(objs []*uns.Unstructured, overrides OperandOverrides) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+	errs := []error{}
+	for _, obj := range objs {
+		if obj.GetAPIVersion() != "apps/v1" || (obj.GetKind() != "DaemonSet" && obj.GetKind() != "Deployment") {
+			continue
+		}
+		override, ok := overrides[obj.GetName()]
+		if !ok {
+			continue
+		}
+		if _, applied := obj.GetAnnotations()[names.OperandOverridesHashAnnotation]; applied {
+			continue
+		}
+		if err := applyOperandOverride(obj, &override); err != nil {
+			errs = append(errs, fmt.Errorf("could not apply the overrides of %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err))
+			continue
+		}
+		buf, err := json.Marshal(override)
+		if err != nil {
+			return err
+		}
+		hash := sha1.Sum(buf)
+		anno := obj.GetAnnotations()
+		if anno == nil {
+			anno = map[string]string{}
+		}
+		anno[names.OperandOverridesHashAnnotation] = hex.EncodeToString(hash[:])
+		obj.SetAnnotations(anno)
+		log.Printf("Applied the operand overrides of %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// applyOperandOverride applies override to the pod template of obj
+func applyOperandOverride(obj *uns.Unstructured, override *OperandOverride) error {
+	podSpecMap, found, err := uns.NestedMap(obj.Object, "spec", "template", "spec")
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no pod template")
+	}
+	podSpec := &corev1.PodSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(podSpecMap, podSpec); err != nil {
+		return err
+	}
+
+	containerNames := make([]string, 0, len(override.Containers))
+	for name := range override.Containers {
+		containerNames = append(containerNames, name)
+	}
+	sort.Strings(containerNames)
+	for _, name := range containerNames {
+		if name == AllContainers {
+			continue
+		}
+		if _, ok := findContainer(podSpec.Containers, name); !ok {
+			return fmt.Errorf("no container %s", name)
+		}
+	}
+	logLevelSet := false
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if containerOverride, ok := override.Containers[AllContainers]; ok {
+			logLevelSet = applyContainerOverride(container, &containerOverride) || logLevelSet
+		}
+		if containerOverride, ok := override.Containers[container.Name]; ok {
+			if !applyContainerOverride(container, &containerOverride) && containerOverride.LogLevel != nil {
+				return fmt.Errorf("container %s has no log level environment variable", container.Name)
+			}
+		}
+	}
+	if containerOverride, ok := override.Containers[AllContainers]; ok && containerOverride.LogLevel != nil && !logLevelSet {
+		return fmt.Errorf("no container has a log level environment variable")
+	}
+
+	if len(override.NodeSelector) > 0 && podSpec.NodeSelector == nil {
+		podSpec.NodeSelector = map[string]string{}
+	}
+	for key, value := range override.NodeSelector {
+		podSpec.NodeSelector[key] = value
+	}
+
+	for _, toleration := range override.Tolerations {
+		found := false
+		for i := range podSpec.Tolerations {
+			if podSpec.Tolerations[i].MatchToleration(&toleration) {
+				podSpec.Tolerations[i] = toleration
+				found = true
+				break
+			}
+		}
+		if !found {
+			podSpec.Tolerations = append(podSpec.Tolerations, toleration)
+		}
+	}
+
+	out, err := runtime.DefaultUnstructuredConverter.ToUnstructured(podSpec)
+	if err != nil {
+		return err
+	}
+	return uns.SetNestedMap(obj.Object, out, "spec", "template", "spec")
+}
+
+// applyContainerOverride applies override to container. It returns true if
+// the override has a log level and the container a variable to set it in.
+func applyContainerOverride(container *corev1.Container, override *ContainerOverride) bool {
+	for _, env := range override.Env {
+		setEnv(container, env)
+	}
+
+	if override.Resources != nil {
+		if len(override.Resources.Requests) > 0 && container.Resources.Requests == nil {
+			container.Resources.Requests = corev1.ResourceList{}
+		}
+		for resource, quantity := range override.Resources.Requests {
+			container.Resources.Requests[resource] = quantity
+		}
+		if len(override.Resources.Limits) > 0 && container.Resources.Limits == nil {
+			container.Resources.Limits = corev1.ResourceList{}
+		}
+		for resource, quantity := range override.Resources.Limits {
+			container.Resources.Limits[resource] = quantity
+		}
+	}
+
+	logLevelSet := false
+	if override.LogLevel != nil {
+		for _, env := range container.Env {
+			if strings.HasSuffix(env.Name, "LOG_LEVEL") {
+				setEnv(container, corev1.EnvVar{Name: env.Name, Value: *override.LogLevel})
+				logLevelSet = true
+			}
+		}
+	}
+	return logLevelSet
+}
+
+// setEnv sets an environment variable of container, replacing the variable
+// with the same name if there is one
+func setEnv(container *corev1.Container, env corev1.EnvVar) {
+	for i := range container.Env {
+		if container.Env[i].Name == env.Name {
+			container.Env[i] = env
+			return
+		}
+	}
+	container.Env = append(container.Env, env)
+}
@@ -0,0 +1,190 @@
+package network
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+)
+
+func renderedDaemonSet(t *testing.T) *uns.Unstructured {
+	t.Helper()
+	ds := &appsv1.DaemonSet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DaemonSet"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-ovn-kubernetes", Name: "ovnkube-node"},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "ovn-controller",
+							Env:  []corev1.EnvVar{{Name: "OVN_LOG_LEVEL", Value: "info"}},
+						},
+						{
+							Name: "ovnkube-controller",
+							Env:  []corev1.EnvVar{{Name: "OVN_KUBE_LOG_LEVEL", Value: "4"}, {Name: "K8S_NODE", Value: "node"}},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("10m"),
+									corev1.ResourceMemory: resource.MustParse("600Mi"),
+								},
+							},
+						},
+					},
+					NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+					Tolerations:  []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+				},
+			},
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &uns.Unstructured{Object: obj}
+}
+
+func TestApplyOperandOverrides(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	overrides, errs := ParseOperandOverrides(map[string]string{
+		"ovnkube-node": `
+containers:
+  "*":
+    env:
+    - name: GODEBUG
+      value: madvdontneed=1
+  ovnkube-controller:
+    logLevel: "5"
+    resources:
+      requests:
+        memory: 1Gi
+      limits:
+        memory: 2Gi
+nodeSelector:
+  node-role.kubernetes.io/edge: ""
+tolerations:
+- key: dedicated
+  operator: Equal
+  value: edge
+  effect: NoSchedule
+`,
+		"not-rendered": `
+nodeSelector:
+  foo: bar
+`,
+	})
+	g.Expect(errs).To(BeEmpty())
+
+	obj := renderedDaemonSet(t)
+	configMap := &uns.Unstructured{}
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	configMap.SetName("ovnkube-node")
+	g.Expect(ApplyOperandOverrides([]*uns.Unstructured{configMap, obj}, overrides)).To(Succeed())
+
+	ds := &appsv1.DaemonSet{}
+	g.Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, ds)).To(Succeed())
+	podSpec := ds.Spec.Template.Spec
+	g.Expect(podSpec.Containers[0].Env).To(Equal([]corev1.EnvVar{
+		{Name: "OVN_LOG_LEVEL", Value: "info"},
+		{Name: "GODEBUG", Value: "madvdontneed=1"},
+	}))
+	g.Expect(podSpec.Containers[1].Env).To(Equal([]corev1.EnvVar{
+		{Name: "OVN_KUBE_LOG_LEVEL", Value: "5"},
+		{Name: "K8S_NODE", Value: "node"},
+		{Name: "GODEBUG", Value: "madvdontneed=1"},
+	}))
+	g.Expect(podSpec.Containers[1].Resources.Requests.Cpu().String()).To(Equal("10m"))
+	g.Expect(podSpec.Containers[1].Resources.Requests.Memory().String()).To(Equal("1Gi"))
+	g.Expect(podSpec.Containers[1].Resources.Limits.Memory().String()).To(Equal("2Gi"))
+	g.Expect(podSpec.NodeSelector).To(Equal(map[string]string{"kubernetes.io/os": "linux", "node-role.kubernetes.io/edge": ""}))
+	g.Expect(podSpec.Tolerations).To(Equal([]corev1.Toleration{
+		{Operator: corev1.TolerationOpExists},
+		{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "edge", Effect: corev1.TaintEffectNoSchedule},
+	}))
+	g.Expect(obj.GetAnnotations()).To(HaveKey(names.OperandOverridesHashAnnotation))
+
+	// objects that already have their overrides are skipped
+	applied := obj.DeepCopy()
+	g.Expect(ApplyOperandOverrides([]*uns.Unstructured{obj}, overrides)).To(Succeed())
+	g.Expect(obj).To(Equal(applied))
+
+	// overrides of containers that are not rendered are errors
+	overrides, errs = ParseOperandOverrides(map[string]string{
+		"ovnkube-node": `
+containers:
+  nbdb:
+    logLevel: dbg
+`,
+	})
+	g.Expect(errs).To(BeEmpty())
+	g.Expect(ApplyOperandOverrides([]*uns.Unstructured{renderedDaemonSet(t)}, overrides)).To(
+		MatchError("could not apply the overrides of DaemonSet openshift-ovn-kubernetes/ovnkube-node: no container nbdb"))
+}
+
+func TestParseOperandOverridesInvalid(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	_, errs := ParseOperandOverrides(map[string]string{
+		"Multus": `
+tolerations:
+- operator: Equal
+`,
+		"ovnkube-node": `
+containers:
+  ovnkube-controller:
+    logLevel: ""
+    resources:
+      requests:
+        memory: 2Gi
+      limits:
+        memory: 1Gi
+    env:
+    - name: "1NVALID"
+nodeSelector:
+  "not a label": x
+`,
+		"multus-additional-cni-plugins": `
+priorityClassName: system-node-critical
+`,
+	})
+	messages := []string{}
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	g.Expect(messages).To(ConsistOf(
+		ContainSubstring(`data[Multus]: Invalid value: "Multus": a lowercase RFC 1123 subdomain`),
+		ContainSubstring(`data[Multus].tolerations[0].operator: Invalid value: "Equal": must be Exists when key is empty`),
+		ContainSubstring(`data[ovnkube-node].containers[ovnkube-controller].logLevel: Required value`),
+		ContainSubstring(`data[ovnkube-node].containers[ovnkube-controller].resources.requests[memory]: Invalid value: "2Gi": must be less than or equal to the memory limit`),
+		ContainSubstring(`data[ovnkube-node].containers[ovnkube-controller].env[0].name: Invalid value: "1NVALID"`),
+		ContainSubstring(`data[ovnkube-node].nodeSelector[not a label]: Invalid value: "not a label"`),
+		ContainSubstring(`data[multus-additional-cni-plugins]: Invalid value: error unmarshaling JSON: while decoding JSON: json: unknown field "priorityClassName"`),
+	))
+}
+
+func TestRenderOperandOverridesInvalid(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	bootstrapResult := &bootstrap.BootstrapResult{}
+	bootstrapResult.OperandOverrides.Data = map[string]string{"ovnkube-node": "priorityClassName: system-node-critical\n"}
+	err := renderOperandOverrides([]*uns.Unstructured{renderedDaemonSet(t)}, bootstrapResult)
+	var overridesErr *OperandOverridesError
+	g.Expect(errors.As(err, &overridesErr)).To(BeTrue())
+
+	bootstrapResult.OperandOverrides.Data = map[string]string{"ovnkube-node": "containers:\n  nbdb:\n    logLevel: dbg\n"}
+	err = renderOperandOverrides([]*uns.Unstructured{renderedDaemonSet(t)}, bootstrapResult)
+	g.Expect(errors.As(err, &overridesErr)).To(BeTrue())
+	g.Expect(overridesErr.Err).To(MatchError(ContainSubstring("no container nbdb")))
+}
@@ -31,8 +31,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
-	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
 	utilnet "k8s.io/utils/net"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -79,6 +79,62 @@ const (
 	defaultV6MasqueradeSubnet = "fd69::/112"
 )
 
+func init() {
+	RegisterNetworkPlugin(operv1.NetworkTypeOVNKubernetes, ovnKubernetesPlugin{})
+}
+
+// ovnKubernetesPlugin is the NetworkPlugin for OVNKubernetes.
+type ovnKubernetesPlugin struct{}
+
+func (ovnKubernetesPlugin) Validate(conf *operv1.NetworkSpec) field.ErrorList {
+	return validateOVNKubernetes(conf)
+}
+
+func (ovnKubernetesPlugin) FillDefaults(conf, previous *operv1.NetworkSpec, hostMTU int) {
+	fillOVNKubernetesDefaults(conf, previous, hostMTU)
+}
+
+func (ovnKubernetesPlugin) DropConfig(conf *operv1.NetworkSpec) {
+	conf.DefaultNetwork.OVNKubernetesConfig = nil
+}
+
+func (ovnKubernetesPlugin) NeedsMTUProbe(conf *operv1.NetworkSpec) bool {
+	c := conf.DefaultNetwork.OVNKubernetesConfig
+	return c == nil || c.MTU == nil || *c.MTU == 0
+}
+
+func (ovnKubernetesPlugin) IsChangeSafe(prev, next *operv1.NetworkSpec) []error {
+	return isOVNKubernetesChangeSafe(prev, next)
+}
+
+func (ovnKubernetesPlugin) Bootstrap(conf *operv1.Network, client cnoclient.Client, out *bootstrap.BootstrapResult) error {
+	o, err := bootstrapOVN(conf, client, &out.Infra)
+	if err != nil {
+		return err
+	}
+	out.OVN = *o
+	return nil
+}
+
+func (ovnKubernetesPlugin) Render(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.BootstrapResult, manifestDir string,
+	client cnoclient.Client, featureGates featuregates.FeatureGate) ([]*uns.Unstructured, bool, error) {
+	return renderOVNKubernetes(conf, bootstrapResult, manifestDir, client, featureGates)
+}
+
+// RenderMigrationCRDs generates the OpenShiftSDN CRDs, for the migration from OpenShiftSDN.
+func (ovnKubernetesPlugin) RenderMigrationCRDs(conf *operv1.NetworkSpec, manifestDir string, featureGates featuregates.FeatureGate) ([]*uns.Unstructured, error) {
+	manifests, err := render.RenderTemplate(filepath.Join(manifestDir, "network/openshift-sdn/001-crd.yaml"), &render.RenderData{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render OpenShiftSDN CRDs")
+	}
+	return manifests, err
+}
+
+func (ovnKubernetesPlugin) Status(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.BootstrapResult, status *configv1.NetworkStatus) bool {
+	status.ClusterNetworkMTU = int(*conf.DefaultNetwork.OVNKubernetesConfig.MTU)
+	return true
+}
+
 // renderOVNKubernetes returns the manifests for the ovn-kubernetes.
 // This creates
 // - the openshift-ovn-kubernetes namespace
@@ -234,6 +290,11 @@ func renderOVNKubernetes(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.Bo
 	data.Data["HCPNodeSelector"] = bootstrapResult.OVN.OVNKubernetesConfig.HyperShiftConfig.HCPNodeSelector
 	data.Data["HCPLabels"] = bootstrapResult.OVN.OVNKubernetesConfig.HyperShiftConfig.HCPLabels
 	data.Data["HCPTolerations"] = bootstrapResult.OVN.OVNKubernetesConfig.HyperShiftConfig.HCPTolerations
+	data.Data["HCPAnnotations"] = bootstrapResult.OVN.OVNKubernetesConfig.HyperShiftConfig.HCPAnnotations
+	data.Data["HCPTopologySpreadConstraints"] = bootstrapResult.OVN.OVNKubernetesConfig.HyperShiftConfig.HCPTopologySpreadConstraints
+	data.Data["HCPPodAffinity"] = bootstrapResult.OVN.OVNKubernetesConfig.HyperShiftConfig.HCPPodAffinity
+	data.Data["HCPPodAntiAffinity"] = bootstrapResult.OVN.OVNKubernetesConfig.HyperShiftConfig.HCPPodAntiAffinity
+	data.Data["HCPResourceRequests"] = bootstrapResult.OVN.OVNKubernetesConfig.HyperShiftConfig.HCPResourceRequests
 	data.Data["OVN_NB_INACTIVITY_PROBE"] = nb_inactivity_probe
 	data.Data["OVN_CERT_CN"] = OVN_CERT_CN
 	data.Data["OVN_NORTHD_PROBE_INTERVAL"] = os.Getenv("OVN_NORTHD_PROBE_INTERVAL")
@@ -265,7 +326,7 @@ func renderOVNKubernetes(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.Bo
 	}
 	data.Data["OVN_cidr"] = ippools
 
-	data.Data["OVN_service_cidr"] = strings.Join(conf.ServiceNetwork, ",")
+	data.Data["OVN_service_cidr"] = strings.Join(renderedServiceNetworks(conf, bootstrapResult), ",")
 
 	hybridOverlayStatus := "disabled"
 	if c.HybridOverlayConfig != nil {
@@ -478,6 +539,12 @@ func renderOVNKubernetes(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.Bo
 	if err != nil {
 		return nil, progressing, fmt.Errorf("unable to render OVN: failed to handle IP family annotation or change: %w", err)
 	}
+	// The service network CIDRs are tracked so that a service network migration
+	// knows when ovnkube has been rolled out with the additional CIDRs
+	err = setOVNObjectAnnotation(objs, names.ServiceNetworkCIDRsAnnotation, data.Data["OVN_service_cidr"].(string))
+	if err != nil {
+		return nil, progressing, fmt.Errorf("unable to render OVN: failed to set %s annotation: %w", names.ServiceNetworkCIDRsAnnotation, err)
+	}
 
 	// process upgrades only if we aren't already handling an IP family migration
 	if updateNode && updateControlPlane {
@@ -489,6 +556,23 @@ func renderOVNKubernetes(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.Bo
 		updateNode, renderPrePull = shouldUpdateOVNKonPrepull(bootstrapResult.OVN, os.Getenv("RELEASE_VERSION"))
 	}
 
+	// The operand overrides are part of the ovnkube-node pod template that is
+	// rolled out to the canary nodes first
+	if err := renderOperandOverrides(objs, bootstrapResult); err != nil {
+		return nil, progressing, fmt.Errorf("unable to render OVN: %w", err)
+	}
+
+	// If canary rollouts are enabled, roll out node changes to the canary nodes first
+	objs, canaryDone, err := renderOVNNodeCanary(objs, bootstrapResult.OVN)
+	if err != nil {
+		return nil, progressing, fmt.Errorf("unable to render OVN: failed to render ovnkube-node canary: %w", err)
+	}
+	updateNodeCanary := updateNode
+	if updateNode && !canaryDone {
+		updateNode = false
+		progressing = true
+	}
+
 	// Skip rendering ovn-ipsec-host daemonset when renderIPsecHostDaemonSet flag is not set.
 	if !renderIPsecHostDaemonSet {
 		objs = k8s.RemoveObjByGroupKindName(objs, "apps", "DaemonSet", util.OVN_NAMESPACE, "ovn-ipsec-host")
@@ -557,6 +641,17 @@ func renderOVNKubernetes(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.Bo
 			o.SetAnnotations(anno)
 		})
 	}
+	if !updateNodeCanary && bootstrapResult.OVN.NodeCanaryUpdateStatus != nil {
+		klog.Infof("annotate local copy of %s DaemonSet with create-only", util.OVN_NODE_CANARY)
+		k8s.UpdateObjByGroupKindName(objs, "apps", "DaemonSet", util.OVN_NAMESPACE, util.OVN_NODE_CANARY, func(o *uns.Unstructured) {
+			anno := o.GetAnnotations()
+			if anno == nil {
+				anno = map[string]string{}
+			}
+			anno[names.CreateOnlyAnnotation] = "true" // skip if annotated and object exists already
+			o.SetAnnotations(anno)
+		})
+	}
 
 	if !renderPrePull {
 		// remove prepull from the list of objects to render.
@@ -733,6 +828,21 @@ func bootstrapOVNHyperShiftConfig(hc *hypershift.HyperShiftConfig, kubeClient cn
 	ovnHypershiftResult.HCPNodeSelector = hcp.NodeSelector
 	ovnHypershiftResult.HCPLabels = hcp.Labels
 	ovnHypershiftResult.HCPTolerations = hcp.Tolerations
+	ovnHypershiftResult.HCPAnnotations = hcp.Annotations
+	ovnHypershiftResult.HCPResourceRequests = hcp.ResourceRequests(util.OVN_CONTROL_PLANE)
+	var err error
+	ovnHypershiftResult.HCPTopologySpreadConstraints, err = hcp.TopologySpreadConstraintsYaml(map[string]string{"app": util.OVN_CONTROL_PLANE})
+	if err != nil {
+		return nil, fmt.Errorf("failed to yaml marshal topology spread constraints: %v", err)
+	}
+	ovnHypershiftResult.HCPPodAffinity, err = hcp.PodAffinityYaml()
+	if err != nil {
+		return nil, fmt.Errorf("failed to yaml marshal pod affinity: %v", err)
+	}
+	ovnHypershiftResult.HCPPodAntiAffinity, err = hcp.PodAntiAffinityYaml()
+	if err != nil {
+		return nil, fmt.Errorf("failed to yaml marshal pod anti-affinity: %v", err)
+	}
 
 	switch hcp.ControllerAvailabilityPolicy {
 	case hypershift.HighlyAvailable:
@@ -872,13 +982,25 @@ func bootstrapOVNConfig(conf *operv1.Network, kubeClient cnoclient.Client, hc *h
 
 	ovnConfigResult.DisableUDPAggregation = getDisableUDPAggregation(kubeClient.ClientFor("").CRClient())
 
+	ovnConfigResult.NodeCanaryLabel, err = bootstrapOVNNodeCanaryLabel(kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	if ovnConfigResult.NodeCanaryLabel != "" {
+		ovnConfigResult.NodeCanaryNodes, err = getNodeListByLabel(kubeClient, ovnConfigResult.NodeCanaryLabel)
+		if err != nil {
+			return nil, fmt.Errorf("Could not get node list with label %s : %w", ovnConfigResult.NodeCanaryLabel, err)
+		}
+	}
+
 	return ovnConfigResult, nil
 }
 
 // validateOVNKubernetes checks that the ovn-kubernetes specific configuration
 // is basically sane.
-func validateOVNKubernetes(conf *operv1.NetworkSpec) []error {
-	out := []error{}
+func validateOVNKubernetes(conf *operv1.NetworkSpec) field.ErrorList {
+	out := field.ErrorList{}
+	specPath := field.NewPath("spec")
 
 	var cnHasIPv4, cnHasIPv6 bool
 	for _, cn := range conf.ClusterNetwork {
@@ -889,7 +1011,7 @@ func validateOVNKubernetes(conf *operv1.NetworkSpec) []error {
 		}
 	}
 	if !cnHasIPv6 && !cnHasIPv4 {
-		out = append(out, errors.Errorf("ClusterNetwork cannot be empty"))
+		out = append(out, field.Required(specPath.Child("clusterNetwork"), "ClusterNetwork cannot be empty"))
 	}
 
 	var snHasIPv4, snHasIPv6 bool
@@ -901,33 +1023,32 @@ func validateOVNKubernetes(conf *operv1.NetworkSpec) []error {
 		}
 	}
 	if !snHasIPv6 && !snHasIPv4 {
-		out = append(out, errors.Errorf("ServiceNetwork cannot be empty"))
+		out = append(out, field.Required(specPath.Child("serviceNetwork"), "ServiceNetwork cannot be empty"))
 	}
 
 	if cnHasIPv4 != snHasIPv4 || cnHasIPv6 != snHasIPv6 {
-		out = append(out, errors.Errorf("ClusterNetwork and ServiceNetwork must have matching IP families"))
+		out = append(out, field.Invalid(specPath.Child("serviceNetwork"), conf.ServiceNetwork, "ClusterNetwork and ServiceNetwork must have matching IP families"))
 	}
 	if len(conf.ServiceNetwork) > 2 || (len(conf.ServiceNetwork) == 2 && (!snHasIPv4 || !snHasIPv6)) {
-		out = append(out, errors.Errorf("ServiceNetwork must have either a single CIDR or a dual-stack pair of CIDRs"))
+		out = append(out, field.Invalid(specPath.Child("serviceNetwork"), conf.ServiceNetwork, "ServiceNetwork must have either a single CIDR or a dual-stack pair of CIDRs"))
 	}
 
 	oc := conf.DefaultNetwork.OVNKubernetesConfig
 	if oc != nil {
+		ovnPath := specPath.Child("defaultNetwork", "ovnKubernetesConfig")
 		minMTU := MinMTUIPv4
 		if cnHasIPv6 {
 			minMTU = MinMTUIPv6
 		}
 		if oc.MTU != nil && (*oc.MTU < minMTU || *oc.MTU > MaxMTU) {
-			out = append(out, errors.Errorf("invalid MTU %d", *oc.MTU))
+			out = append(out, field.Invalid(ovnPath.Child("mtu"), int64(*oc.MTU), fmt.Sprintf("invalid MTU %d, must be between %d and %d", *oc.MTU, minMTU, MaxMTU)))
 		}
 		if oc.GenevePort != nil && (*oc.GenevePort < 1 || *oc.GenevePort > 65535) {
-			out = append(out, errors.Errorf("invalid GenevePort %d", *oc.GenevePort))
+			out = append(out, field.Invalid(ovnPath.Child("genevePort"), int64(*oc.GenevePort), fmt.Sprintf("invalid GenevePort %d", *oc.GenevePort)))
 		}
 	}
 
-	if err := validateOVNKubernetesSubnets(conf); err != nil {
-		out = append(out, err)
-	}
+	out = append(out, validateOVNKubernetesSubnets(conf)...)
 	return out
 }
 
@@ -1157,6 +1278,7 @@ func bootstrapOVN(conf *operv1.Network, kubeClient cnoclient.Client, infraStatus
 		controlPlaneStatus.Name = controlPlaneDeployment.Name
 		controlPlaneStatus.IPFamilyMode = controlPlaneDeployment.GetAnnotations()[names.NetworkIPFamilyModeAnnotation]
 		controlPlaneStatus.ClusterNetworkCIDRs = controlPlaneDeployment.GetAnnotations()[names.ClusterNetworkCIDRsAnnotation]
+		controlPlaneStatus.ServiceNetworkCIDRs = controlPlaneDeployment.GetAnnotations()[names.ServiceNetworkCIDRsAnnotation]
 		controlPlaneStatus.Version = controlPlaneDeployment.GetAnnotations()["release.openshift.io/version"]
 		controlPlaneStatus.Progressing = deploymentProgressing(controlPlaneDeployment)
 
@@ -1186,8 +1308,11 @@ func bootstrapOVN(conf *operv1.Network, kubeClient cnoclient.Client, infraStatus
 		nodeStatus.Name = nodeDaemonSet.Name
 		nodeStatus.IPFamilyMode = nodeDaemonSet.GetAnnotations()[names.NetworkIPFamilyModeAnnotation]
 		nodeStatus.ClusterNetworkCIDRs = nodeDaemonSet.GetAnnotations()[names.ClusterNetworkCIDRsAnnotation]
+		nodeStatus.ServiceNetworkCIDRs = nodeDaemonSet.GetAnnotations()[names.ServiceNetworkCIDRsAnnotation]
 		nodeStatus.Version = nodeDaemonSet.GetAnnotations()["release.openshift.io/version"]
 		nodeStatus.Progressing = daemonSetProgressing(nodeDaemonSet, true)
+		nodeStatus.TemplateHash = nodeDaemonSet.GetAnnotations()[names.OVNKubeNodeTemplateHashAnnotation]
+		nodeStatus.CanaryLabel = nodeDaemonSet.GetAnnotations()[names.OVNKubeNodeCanaryLabelAnnotation]
 		// Retrieve OVN IPsec status from ovnkube-node daemonset as this is being used to rollout IPsec
 		// config.
 		ovnIPsecStatus.IsOVNIPsecActiveOrRollingOut = !isOVNIPsecNotActiveInDaemonSet(nodeDaemonSet)
@@ -1216,12 +1341,31 @@ func bootstrapOVN(conf *operv1.Network, kubeClient cnoclient.Client, infraStatus
 		prepullerStatus.Progressing = daemonSetProgressing(prePullerDaemonSet, true)
 	}
 
+	var canaryStatus *bootstrap.OVNUpdateStatus
+	var canaryFailedChecks []string
+	// The canary DaemonSet may still exist after canary rollouts are disabled,
+	// and must be deleted before ovnkube-node runs on the canary nodes again.
+	canaryStatus, err = bootstrapOVNNodeCanaryStatus(kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	if ovnConfigResult.NodeCanaryLabel != "" {
+		if canaryStatus != nil && !canaryStatus.Progressing {
+			canaryFailedChecks, err = bootstrapOVNNodeCanaryChecks(kubeClient, ovnConfigResult.NodeCanaryNodes)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	res := bootstrap.OVNBootstrapResult{
 		ControlPlaneReplicaCount: controlPlaneReplicaCount,
 		ControlPlaneUpdateStatus: controlPlaneStatus,
 		NodeUpdateStatus:         nodeStatus,
 		IPsecUpdateStatus:        ovnIPsecStatus,
 		PrePullerUpdateStatus:    prepullerStatus,
+		NodeCanaryUpdateStatus:   canaryStatus,
+		NodeCanaryFailedChecks:   canaryFailedChecks,
 		OVNKubernetesConfig:      ovnConfigResult,
 		FlowsConfig:              bootstrapFlowsConfig(kubeClient.ClientFor("").CRClient()),
 	}
@@ -1762,17 +1906,20 @@ func isIPSecEnabledInPod(pod v1.PodTemplateSpec, containerName string) bool {
 //   - Validates whether provided subnets have enough IPs to allocate to all nodes as per
 //     Clusternetwork CIDR and hostPrefix
 //   - Exhibits error if InternalJoinSubnet is not same as InternalSubnet if both are present
-func validateOVNKubernetesSubnets(conf *operv1.NetworkSpec) error {
+func validateOVNKubernetesSubnets(conf *operv1.NetworkSpec) field.ErrorList {
 	if conf.DefaultNetwork.OVNKubernetesConfig == nil {
 		return nil
 	}
-	out := []error{}
+	out := field.ErrorList{}
+	specPath := field.NewPath("spec")
+	ovnPath := specPath.Child("defaultNetwork", "ovnKubernetesConfig")
 	pool := iputil.IPPool{}
 	var cnHasIPv4, cnHasIPv6 bool
-	for _, cn := range conf.ClusterNetwork {
+	for i, cn := range conf.ClusterNetwork {
+		cidrPath := specPath.Child("clusterNetwork").Index(i).Child("cidr")
 		_, cidr, err := net.ParseCIDR(cn.CIDR)
 		if err != nil {
-			out = append(out, errors.Errorf("could not parse spec.clusterNetwork %s", cn.CIDR))
+			out = append(out, field.Invalid(cidrPath, cn.CIDR, fmt.Sprintf("could not parse spec.clusterNetwork %s", cn.CIDR)))
 			continue
 		}
 		if utilnet.IsIPv6CIDRString(cn.CIDR) {
@@ -1781,17 +1928,18 @@ func validateOVNKubernetesSubnets(conf *operv1.NetworkSpec) error {
 			cnHasIPv4 = true
 		}
 		if err := pool.Add(*cidr); err != nil {
-			out = append(out, errors.Errorf("Whole or subset of ClusterNetwork CIDR %s is already in use: %s", cn.CIDR, err))
+			out = append(out, field.Invalid(cidrPath, cn.CIDR, fmt.Sprintf("Whole or subset of ClusterNetwork CIDR %s is already in use: %s", cn.CIDR, err)))
 		}
 	}
-	for _, snet := range conf.ServiceNetwork {
+	for i, snet := range conf.ServiceNetwork {
+		snetPath := specPath.Child("serviceNetwork").Index(i)
 		_, cidr, err := net.ParseCIDR(snet)
 		if err != nil {
-			out = append(out, errors.Wrapf(err, "could not parse spec.serviceNetwork %s", snet))
+			out = append(out, field.Invalid(snetPath, snet, fmt.Sprintf("could not parse spec.serviceNetwork %s: %v", snet, err)))
 			continue
 		}
 		if err := pool.Add(*cidr); err != nil {
-			out = append(out, errors.Errorf("Whole or subset of ServiceNetwork CIDR %s is already in use: %s", snet, err))
+			out = append(out, field.Invalid(snetPath, snet, fmt.Sprintf("Whole or subset of ServiceNetwork CIDR %s is already in use: %s", snet, err)))
 		}
 	}
 
@@ -1799,17 +1947,19 @@ func validateOVNKubernetesSubnets(conf *operv1.NetworkSpec) error {
 	// Note: oc.V4InternalSubnet will be deprecated in future as per k8s guidelines
 	// oc.V4InternalSubnet and oc.IPv4.InternalJoinSubnet must be same if both are present
 	v4InternalSubnet := oc.V4InternalSubnet
+	v4InternalSubnetPath := ovnPath.Child("v4InternalSubnet")
 	if oc.IPv4 != nil && oc.IPv4.InternalJoinSubnet != "" {
+		v4InternalSubnetPath = ovnPath.Child("ipv4", "internalJoinSubnet")
 		if v4InternalSubnet != "" && v4InternalSubnet != oc.IPv4.InternalJoinSubnet {
-			out = append(out, errors.Errorf("v4InternalSubnet will be deprecated soon, until then it must be same as v4InternalJoinSubnet %s ", oc.IPv4.InternalJoinSubnet))
+			out = append(out, field.Invalid(ovnPath.Child("v4InternalSubnet"), v4InternalSubnet, fmt.Sprintf("v4InternalSubnet will be deprecated soon, until then it must be same as v4InternalJoinSubnet %s ", oc.IPv4.InternalJoinSubnet)))
 		}
 		v4InternalSubnet = oc.IPv4.InternalJoinSubnet
 	}
 	if v4InternalSubnet != "" {
 		if !cnHasIPv4 {
-			out = append(out, errors.Errorf("JoinSubnet %s and ClusterNetwork must have matching IP families", v4InternalSubnet))
+			out = append(out, field.Invalid(v4InternalSubnetPath, v4InternalSubnet, fmt.Sprintf("JoinSubnet %s and ClusterNetwork must have matching IP families", v4InternalSubnet)))
 		}
-		if err := validateOVNKubernetesSubnet("v4InternalJoinSubnet", v4InternalSubnet, &pool, conf.ClusterNetwork); err != nil {
+		if err := validateOVNKubernetesSubnet(v4InternalSubnetPath, "v4InternalJoinSubnet", v4InternalSubnet, &pool, conf.ClusterNetwork); err != nil {
 			out = append(out, err)
 		}
 	}
@@ -1817,35 +1967,39 @@ func validateOVNKubernetesSubnets(conf *operv1.NetworkSpec) error {
 	// Note: oc.V6InternalSubnet will be deprecated in future as per k8s guidelines
 	// oc.V6InternalSubnet and oc.IPv6.InternalJoinSubnet must be same if both are present
 	v6InternalSubnet := oc.V6InternalSubnet
+	v6InternalSubnetPath := ovnPath.Child("v6InternalSubnet")
 	if oc.IPv6 != nil && oc.IPv6.InternalJoinSubnet != "" {
+		v6InternalSubnetPath = ovnPath.Child("ipv6", "internalJoinSubnet")
 		if v6InternalSubnet != "" && v6InternalSubnet != oc.IPv6.InternalJoinSubnet {
-			out = append(out, errors.Errorf("v6InternalSubnet will be deprecated soon, until then it must be same as v6InternalJoinSubnet %s ", oc.IPv6.InternalJoinSubnet))
+			out = append(out, field.Invalid(ovnPath.Child("v6InternalSubnet"), v6InternalSubnet, fmt.Sprintf("v6InternalSubnet will be deprecated soon, until then it must be same as v6InternalJoinSubnet %s ", oc.IPv6.InternalJoinSubnet)))
 		}
 		v6InternalSubnet = oc.IPv6.InternalJoinSubnet
 	}
 	if v6InternalSubnet != "" {
 		if !cnHasIPv6 {
-			out = append(out, errors.Errorf("JoinSubnet %s and ClusterNetwork must have matching IP families", v6InternalSubnet))
+			out = append(out, field.Invalid(v6InternalSubnetPath, v6InternalSubnet, fmt.Sprintf("JoinSubnet %s and ClusterNetwork must have matching IP families", v6InternalSubnet)))
 		}
-		if err := validateOVNKubernetesSubnet("v6InternalJoinSubnet", v6InternalSubnet, &pool, conf.ClusterNetwork); err != nil {
+		if err := validateOVNKubernetesSubnet(v6InternalSubnetPath, "v6InternalJoinSubnet", v6InternalSubnet, &pool, conf.ClusterNetwork); err != nil {
 			out = append(out, err)
 		}
 	}
 
 	if oc.IPv4 != nil && oc.IPv4.InternalTransitSwitchSubnet != "" {
+		path := ovnPath.Child("ipv4", "internalTransitSwitchSubnet")
 		if !cnHasIPv4 {
-			out = append(out, errors.Errorf("v4InternalTransitSwitchSubnet %s and ClusterNetwork must have matching IP families", oc.IPv4.InternalTransitSwitchSubnet))
+			out = append(out, field.Invalid(path, oc.IPv4.InternalTransitSwitchSubnet, fmt.Sprintf("v4InternalTransitSwitchSubnet %s and ClusterNetwork must have matching IP families", oc.IPv4.InternalTransitSwitchSubnet)))
 		}
-		if err := validateOVNKubernetesSubnet("v4InternalTransitSwitchSubnet", oc.IPv4.InternalTransitSwitchSubnet, &pool, conf.ClusterNetwork); err != nil {
+		if err := validateOVNKubernetesSubnet(path, "v4InternalTransitSwitchSubnet", oc.IPv4.InternalTransitSwitchSubnet, &pool, conf.ClusterNetwork); err != nil {
 			out = append(out, err)
 		}
 	}
 
 	if oc.IPv6 != nil && oc.IPv6.InternalTransitSwitchSubnet != "" {
+		path := ovnPath.Child("ipv6", "internalTransitSwitchSubnet")
 		if !cnHasIPv6 {
-			out = append(out, errors.Errorf("v6InternalTransitSwitchSubnet %s and ClusterNetwork must have matching IP families", oc.IPv6.InternalTransitSwitchSubnet))
+			out = append(out, field.Invalid(path, oc.IPv6.InternalTransitSwitchSubnet, fmt.Sprintf("v6InternalTransitSwitchSubnet %s and ClusterNetwork must have matching IP families", oc.IPv6.InternalTransitSwitchSubnet)))
 		}
-		if err := validateOVNKubernetesSubnet("v6InternalTransitSwitchSubnet", oc.IPv6.InternalTransitSwitchSubnet, &pool, conf.ClusterNetwork); err != nil {
+		if err := validateOVNKubernetesSubnet(path, "v6InternalTransitSwitchSubnet", oc.IPv6.InternalTransitSwitchSubnet, &pool, conf.ClusterNetwork); err != nil {
 			out = append(out, err)
 		}
 	}
@@ -1854,46 +2008,48 @@ func validateOVNKubernetesSubnets(conf *operv1.NetworkSpec) error {
 	// Validate whether masquerade CIDR is from same IP family as clusterNetwork.
 	if oc.GatewayConfig != nil {
 		if oc.GatewayConfig.IPv4.InternalMasqueradeSubnet != "" {
+			path := ovnPath.Child("gatewayConfig", "ipv4", "internalMasqueradeSubnet")
 			if !cnHasIPv4 {
-				out = append(out, errors.Errorf("v4InternalMasqueradeSubnet %s and ClusterNetwork must have matching IP families", oc.GatewayConfig.IPv4.InternalMasqueradeSubnet))
+				out = append(out, field.Invalid(path, oc.GatewayConfig.IPv4.InternalMasqueradeSubnet, fmt.Sprintf("v4InternalMasqueradeSubnet %s and ClusterNetwork must have matching IP families", oc.GatewayConfig.IPv4.InternalMasqueradeSubnet)))
 			}
 			// Masquerade subnet does not need subnet length check. Sending ClusterNetwork
 			// nil while calling validateOVNKubernetesSubnet to avoid subnet length check.
-			if err := validateOVNKubernetesSubnet("v4InternalMasqueradeSubnet", oc.GatewayConfig.IPv4.InternalMasqueradeSubnet, &pool, nil); err != nil {
+			if err := validateOVNKubernetesSubnet(path, "v4InternalMasqueradeSubnet", oc.GatewayConfig.IPv4.InternalMasqueradeSubnet, &pool, nil); err != nil {
 				out = append(out, err)
 			}
 		}
 		if oc.GatewayConfig.IPv6.InternalMasqueradeSubnet != "" {
+			path := ovnPath.Child("gatewayConfig", "ipv6", "internalMasqueradeSubnet")
 			if !cnHasIPv6 {
-				out = append(out, errors.Errorf("v6InternalMasqueradeSubnet %s and ClusterNetwork must have matching IP families", oc.GatewayConfig.IPv6.InternalMasqueradeSubnet))
+				out = append(out, field.Invalid(path, oc.GatewayConfig.IPv6.InternalMasqueradeSubnet, fmt.Sprintf("v6InternalMasqueradeSubnet %s and ClusterNetwork must have matching IP families", oc.GatewayConfig.IPv6.InternalMasqueradeSubnet)))
 			}
 			// Masquerade subnet does not need subnet length check. Sending ClusterNetwork
 			// nil while calling validateOVNKubernetesSubnet to avoid subnet length check.
-			if err := validateOVNKubernetesSubnet("v6InternalMasqueradeSubnet", oc.GatewayConfig.IPv6.InternalMasqueradeSubnet, &pool, nil); err != nil {
+			if err := validateOVNKubernetesSubnet(path, "v6InternalMasqueradeSubnet", oc.GatewayConfig.IPv6.InternalMasqueradeSubnet, &pool, nil); err != nil {
 				out = append(out, err)
 			}
 		}
 	}
 
-	return kerrors.NewAggregate(out)
+	return out
 }
 
 // Check subnet length and overlapping with other subnets
-func validateOVNKubernetesSubnet(name, subnet string, otherSubnets *iputil.IPPool, cn []operv1.ClusterNetworkEntry) error {
+func validateOVNKubernetesSubnet(path *field.Path, name, subnet string, otherSubnets *iputil.IPPool, cn []operv1.ClusterNetworkEntry) *field.Error {
 	_, cidr, err := net.ParseCIDR(subnet)
 	if err != nil {
-		return fmt.Errorf("%s is invalid: %s", name, err)
+		return field.Invalid(path, subnet, fmt.Sprintf("%s is invalid: %s", name, err))
 	} else if cn != nil && !utilnet.IsIPv6CIDRString(subnet) {
 		if !isV4NodeSubnetLargeEnough(cn, subnet) {
-			return fmt.Errorf("%s %s is not large enough for the maximum number of nodes which can be supported by ClusterNetwork", name, subnet)
+			return field.Invalid(path, subnet, fmt.Sprintf("%s %s is not large enough for the maximum number of nodes which can be supported by ClusterNetwork", name, subnet))
 		}
 	} else if cn != nil && utilnet.IsIPv6CIDRString(subnet) {
 		if !isV6NodeSubnetLargeEnough(cn, subnet) {
-			return fmt.Errorf("%s %s is not large enough for the maximum number of nodes which can be supported by ClusterNetwork", name, subnet)
+			return field.Invalid(path, subnet, fmt.Sprintf("%s %s is not large enough for the maximum number of nodes which can be supported by ClusterNetwork", name, subnet))
 		}
 	}
 	if err := otherSubnets.Add(*cidr); err != nil {
-		return fmt.Errorf("Whole or subset of %s CIDR %s is already in use: %s", name, subnet, err)
+		return field.Invalid(path, subnet, fmt.Sprintf("Whole or subset of %s CIDR %s is already in use: %s", name, subnet, err))
 	}
 	return nil
 }
@@ -1969,7 +2125,7 @@ func GetMasqueradeSubnet(conf *operv1.OVNKubernetesConfig) (v4Subnet, v6Subnet s
 			v4Subnet = conf.GatewayConfig.IPv4.InternalMasqueradeSubnet
 		}
 		if conf.GatewayConfig.IPv6.InternalMasqueradeSubnet != "" {
-			v4Subnet = conf.GatewayConfig.IPv6.InternalMasqueradeSubnet
+			v6Subnet = conf.GatewayConfig.IPv6.InternalMasqueradeSubnet
 		}
 	}
 	return
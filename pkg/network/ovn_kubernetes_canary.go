@@ -0,0 +1,334 @@
+package network
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+	"github.com/openshift/cluster-network-operator/pkg/util"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// OVNNodeCanaryConfigMapName is the ConfigMap, in the operator namespace, that
+	// enables canary rollouts of ovnkube-node.
+	OVNNodeCanaryConfigMapName = "ovnkube-node-canary-config"
+	// ovnNodeCanaryLabelKey is the ConfigMap key holding the label of the canary nodes
+	ovnNodeCanaryLabelKey = "canary-node-label"
+)
+
+var gvrPodNetworkConnectivityCheck = schema.GroupVersionResource{Group: "controlplane.operator.openshift.io", Version: "v1alpha1", Resource: "podnetworkconnectivitychecks"}
+
+// bootstrapOVNNodeCanaryLabel returns the canary node label from the
+// ovnkube-node-canary-config ConfigMap, or "" if canary rollouts are disabled.
+func bootstrapOVNNodeCanaryLabel(kubeClient cnoclient.Client) (string, error) {
+	cm := &corev1.ConfigMap{}
+	nsn := types.NamespacedName{Namespace: names.APPLIED_NAMESPACE, Name: OVNNodeCanaryConfigMapName}
+	if err := kubeClient.ClientFor("").CRClient().Get(context.TODO(), nsn, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("could not retrieve %s ConfigMap: %w", OVNNodeCanaryConfigMapName, err)
+	}
+	return cm.Data[ovnNodeCanaryLabelKey], nil
+}
+
+// bootstrapOVNNodeCanaryStatus returns the status of the ovnkube-node-canary
+// DaemonSet, or nil if it doesn't exist.
+func bootstrapOVNNodeCanaryStatus(kubeClient cnoclient.Client) (*bootstrap.OVNUpdateStatus, error) {
+	ds := &appsv1.DaemonSet{}
+	nsn := types.NamespacedName{Namespace: util.OVN_NAMESPACE, Name: util.OVN_NODE_CANARY}
+	if err := kubeClient.ClientFor("").CRClient().Get(context.TODO(), nsn, ds); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Failed to retrieve existing %s DaemonSet: %w", util.OVN_NODE_CANARY, err)
+	}
+	status := &bootstrap.OVNUpdateStatus{
+		Kind:         "DaemonSet",
+		Namespace:    ds.Namespace,
+		Name:         ds.Name,
+		Version:      ds.GetAnnotations()["release.openshift.io/version"],
+		TemplateHash: ds.GetAnnotations()[names.OVNKubeNodeTemplateHashAnnotation],
+		// A hung canary is exactly what we're trying to catch, so don't allow it.
+		Progressing: daemonSetProgressing(ds, false),
+	}
+	klog.Infof("%s DaemonSet status: progressing=%t", util.OVN_NODE_CANARY, status.Progressing)
+	return status, nil
+}
+
+// bootstrapOVNNodeCanaryChecks returns the names of the PodNetworkConnectivityChecks
+// to the network-check-target pods of the given nodes that are currently failing.
+func bootstrapOVNNodeCanaryChecks(kubeClient cnoclient.Client, nodes []string) ([]string, error) {
+	checks, err := kubeClient.Default().Dynamic().Resource(gvrPodNetworkConnectivityCheck).Namespace("openshift-network-diagnostics").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not list PodNetworkConnectivityChecks: %w", err)
+	}
+	return unreachableChecksToNodes(checks.Items, nodes), nil
+}
+
+// unreachableChecksToNodes returns the names of the checks to the network-check-target
+// pod of one of the nodes whose Reachable condition is False. There is a single
+// network-check-source pod, which most likely doesn't run on a canary node, so
+// the checks are selected by their target, and not by their source.
+// A node that no check targets yet is reported as a missing check, unless there
+// are no checks at all, which means network diagnostics are disabled.
+func unreachableChecksToNodes(checks []uns.Unstructured, nodes []string) []string {
+	failed := []string{}
+	if len(checks) == 0 {
+		return failed
+	}
+	// The checks are named $(SOURCE)-to-network-check-target-$(NODE), with the
+	// short name of the node.
+	const targetPrefix = "-to-network-check-target-"
+	targets := sets.New[string]()
+	for _, node := range nodes {
+		targets.Insert(strings.Split(node, ".")[0])
+	}
+	checked := sets.New[string]()
+	for _, check := range checks {
+		i := strings.LastIndex(check.GetName(), targetPrefix)
+		if i < 0 {
+			continue
+		}
+		target := check.GetName()[i+len(targetPrefix):]
+		if !targets.Has(target) {
+			continue
+		}
+		checked.Insert(target)
+		conditions, _, _ := uns.NestedSlice(check.Object, "status", "conditions")
+		for _, c := range conditions {
+			cond, ok := c.(map[string]interface{})
+			if ok && cond["type"] == "Reachable" && cond["status"] == string(metav1.ConditionFalse) {
+				failed = append(failed, check.GetName())
+				break
+			}
+		}
+	}
+	for _, target := range sets.List(targets.Difference(checked)) {
+		failed = append(failed, fmt.Sprintf("no check to network-check-target on node %s", target))
+	}
+	return failed
+}
+
+// ovnNodeTemplateHash returns the hash of the pod template of the ovnkube-node DaemonSet.
+func ovnNodeTemplateHash(ds *uns.Unstructured) (string, error) {
+	template, _, err := uns.NestedMap(ds.Object, "spec", "template")
+	if err != nil {
+		return "", err
+	}
+	bytes, err := json.Marshal(template)
+	if err != nil {
+		return "", err
+	}
+	h := sha1.Sum(bytes)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// shouldUpdateOVNKNodeOnCanary determines if the ovnkube-node DaemonSet should be
+// updated to the pod template with the given hash, or if it should wait for the
+// ovnkube-node-canary DaemonSet to roll the change out to the canary nodes first.
+func shouldUpdateOVNKNodeOnCanary(ovn bootstrap.OVNBootstrapResult, templateHash string) (updateNode bool) {
+	// Fresh cluster - full steam ahead!
+	if ovn.NodeUpdateStatus == nil {
+		return true
+	}
+	// Return true so that we reconcile any changes that somehow could have happened.
+	if ovn.NodeUpdateStatus.TemplateHash == templateHash {
+		return true
+	}
+	if ovn.NodeCanaryUpdateStatus == nil || ovn.NodeCanaryUpdateStatus.TemplateHash != templateHash {
+		klog.Infof("Rolling out ovnkube-node change to canary nodes first")
+		return false
+	}
+	if ovn.NodeCanaryUpdateStatus.Progressing {
+		klog.Infof("Waiting for %s DaemonSet to finish rolling out before updating ovnkube-node", util.OVN_NODE_CANARY)
+		return false
+	}
+	if len(ovn.NodeCanaryFailedChecks) > 0 {
+		klog.Warningf("Connectivity checks to canary nodes are failing, not updating ovnkube-node: %v", ovn.NodeCanaryFailedChecks)
+		return false
+	}
+	klog.Infof("%s DaemonSet rollout complete, now updating ovnkube-node", util.OVN_NODE_CANARY)
+	return true
+}
+
+// renderOVNNodeCanary splits the ovnkube-node DaemonSet in two when canary rollouts
+// are enabled: ovnkube-node-canary runs on the nodes with the canary label, and
+// ovnkube-node runs everywhere else. Both DaemonSets are annotated with the hash
+// of their pod template, so that the canary can be rolled out first.
+// The two DaemonSets never both run on a node: when the canary label changes,
+// the ovnkube-node-canary DaemonSet is first removed, then ovnkube-node is
+// rolled out to its new set of nodes, and only then ovnkube-node-canary is
+// created again for the new canary nodes.
+// Returns true if the ovnkube-node DaemonSet should be updated.
+func renderOVNNodeCanary(objs []*uns.Unstructured, ovn bootstrap.OVNBootstrapResult) ([]*uns.Unstructured, bool, error) {
+	canaryLabel := ovn.OVNKubernetesConfig.NodeCanaryLabel
+	runningLabel := canaryLabel
+	if ovn.NodeUpdateStatus != nil {
+		runningLabel = ovn.NodeUpdateStatus.CanaryLabel
+	}
+	if canaryLabel == "" && runningLabel == "" {
+		return objs, true, nil
+	}
+
+	var node, service *uns.Unstructured
+	for _, obj := range objs {
+		if obj.GroupVersionKind().Group == "apps" && obj.GetKind() == "DaemonSet" && obj.GetNamespace() == util.OVN_NAMESPACE && obj.GetName() == util.OVN_NODE {
+			node = obj
+		}
+		if obj.GroupVersionKind().Group == "" && obj.GetKind() == "Service" && obj.GetNamespace() == util.OVN_NAMESPACE && obj.GetName() == ovnNodeMetricsService {
+			service = obj
+		}
+	}
+	if node == nil {
+		return objs, true, nil
+	}
+	if canaryLabel != "" && (len(ovn.OVNKubernetesConfig.DpuHostModeNodes) > 0 || len(ovn.OVNKubernetesConfig.DpuModeNodes) > 0 || len(ovn.OVNKubernetesConfig.SmartNicModeNodes) > 0) {
+		return nil, true, fmt.Errorf("canary rollouts of ovnkube-node are not supported on clusters with DPU or SmartNIC nodes, remove the %s ConfigMap", OVNNodeCanaryConfigMapName)
+	}
+
+	templateHash, err := ovnNodeTemplateHash(node)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to hash the ovnkube-node pod template: %w", err)
+	}
+	canary := node.DeepCopy()
+
+	if runningLabel != canaryLabel {
+		if ovn.NodeCanaryUpdateStatus != nil {
+			// Keep ovnkube-node off of the old canary nodes until the
+			// ovnkube-node-canary DaemonSet, which is no longer rendered, is pruned.
+			klog.Infof("Canary node label changed to %q; removing %s DaemonSet first", canaryLabel, util.OVN_NODE_CANARY)
+			if err := setOVNNodeCanaryLabel(node, runningLabel, templateHash); err != nil {
+				return nil, true, err
+			}
+			return objs, false, nil
+		}
+		// Roll ovnkube-node out to its new set of nodes; the canary is
+		// created once this is done.
+		klog.Infof("Canary node label changed to %q; rolling out ovnkube-node to all other nodes", canaryLabel)
+		if err := setOVNNodeCanaryLabel(node, canaryLabel, templateHash); err != nil {
+			return nil, true, err
+		}
+		return objs, true, nil
+	}
+
+	if err := setOVNNodeCanaryLabel(node, canaryLabel, templateHash); err != nil {
+		return nil, true, err
+	}
+	updateNode := shouldUpdateOVNKNodeOnCanary(ovn, templateHash)
+	if ovn.NodeCanaryUpdateStatus == nil && ovn.NodeUpdateStatus != nil && ovn.NodeUpdateStatus.Progressing {
+		// ovnkube-node may still be leaving the canary nodes
+		klog.Infof("Waiting for ovnkube-node DaemonSet to finish rolling out before creating %s DaemonSet", util.OVN_NODE_CANARY)
+		return objs, updateNode, nil
+	}
+
+	// The canary pods have their own app label, so that the selectors of the
+	// two DaemonSets don't overlap.
+	canary.SetName(util.OVN_NODE_CANARY)
+	for _, path := range [][]string{
+		{"spec", "selector", "matchLabels"},
+		{"spec", "template", "metadata", "labels"},
+	} {
+		if err := uns.SetNestedField(canary.Object, util.OVN_NODE_CANARY, append(path, "app")...); err != nil {
+			return nil, true, err
+		}
+	}
+	if err := addNodeAffinityExpression(canary, canaryLabel, corev1.NodeSelectorOpExists); err != nil {
+		return nil, true, err
+	}
+	anno := canary.GetAnnotations()
+	if anno == nil {
+		anno = map[string]string{}
+	}
+	anno[names.OVNKubeNodeTemplateHashAnnotation] = templateHash
+	anno[names.OVNKubeNodeCanaryLabelAnnotation] = canaryLabel
+	canary.SetAnnotations(anno)
+	objs = append(objs, canary)
+
+	// The metrics of the canary pods are scraped through a Service of their own,
+	// which the ovnkube-node ServiceMonitor selects as well.
+	if service != nil {
+		canaryService := service.DeepCopy()
+		canaryService.SetName(ovnNodeMetricsService + "-canary")
+		// the pods share the serving certificate of the ovnkube-node Service
+		anno := canaryService.GetAnnotations()
+		delete(anno, "service.beta.openshift.io/serving-cert-secret-name")
+		canaryService.SetAnnotations(anno)
+		if err := uns.SetNestedField(canaryService.Object, util.OVN_NODE_CANARY, "spec", "selector", "app"); err != nil {
+			return nil, true, err
+		}
+		objs = append(objs, canaryService)
+	}
+
+	return objs, updateNode, nil
+}
+
+// ovnNodeMetricsService is the Service of the metrics of the ovnkube-node pods
+const ovnNodeMetricsService = "ovn-kubernetes-node"
+
+// setOVNNodeCanaryLabel keeps the ovnkube-node DaemonSet off of the nodes with
+// the canary label, if any, and annotates it with the label and the hash of
+// its pod template.
+func setOVNNodeCanaryLabel(node *uns.Unstructured, canaryLabel, templateHash string) error {
+	if canaryLabel != "" {
+		if err := addNodeAffinityExpression(node, canaryLabel, corev1.NodeSelectorOpDoesNotExist); err != nil {
+			return err
+		}
+	}
+	anno := node.GetAnnotations()
+	if anno == nil {
+		anno = map[string]string{}
+	}
+	anno[names.OVNKubeNodeTemplateHashAnnotation] = templateHash
+	anno[names.OVNKubeNodeCanaryLabelAnnotation] = canaryLabel
+	node.SetAnnotations(anno)
+	return nil
+}
+
+// addNodeAffinityExpression adds an expression on the given label to every node
+// selector term of the required node affinity of a DaemonSet.
+func addNodeAffinityExpression(ds *uns.Unstructured, label string, operator corev1.NodeSelectorOperator) error {
+	path := []string{"spec", "template", "spec", "affinity", "nodeAffinity", "requiredDuringSchedulingIgnoredDuringExecution", "nodeSelectorTerms"}
+	terms, _, err := uns.NestedSlice(ds.Object, path...)
+	if err != nil {
+		return err
+	}
+	if len(terms) == 0 {
+		terms = []interface{}{map[string]interface{}{}}
+	}
+	expression := map[string]interface{}{
+		"key":      label,
+		"operator": string(operator),
+	}
+	for i := range terms {
+		term, ok := terms[i].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("invalid node selector term in %s", ds.GetName())
+		}
+		expressions, _, err := uns.NestedSlice(term, "matchExpressions")
+		if err != nil {
+			return err
+		}
+		term["matchExpressions"] = append(expressions, expression)
+	}
+	return uns.SetNestedSlice(ds.Object, terms, path...)
+}
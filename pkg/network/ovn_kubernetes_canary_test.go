@@ -0,0 +1,329 @@
+package network
+
+import (
+	"testing"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+	cnofake "github.com/openshift/cluster-network-operator/pkg/client/fake"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRenderOVNKubernetesNodeCanary(t *testing.T) {
+	config := &operv1.NetworkSpec{
+		ServiceNetwork: []string{"172.30.0.0/16"},
+		ClusterNetwork: []operv1.ClusterNetworkEntry{
+			{
+				CIDR:       "10.128.0.0/15",
+				HostPrefix: 23,
+			},
+		},
+		DefaultNetwork: operv1.DefaultNetworkDefinition{
+			Type: operv1.NetworkTypeOVNKubernetes,
+			OVNKubernetesConfig: &operv1.OVNKubernetesConfig{
+				GenevePort: ptrToUint32(8061),
+			},
+		},
+	}
+	fillDefaults(config, nil)
+	t.Setenv("RELEASE_VERSION", "2.0.0")
+
+	// the cluster is at the current version, but ovnkube-node was rendered with a different template
+	bootstrapResult := fakeBootstrapResult()
+	bootstrapResult.OVN = bootstrap.OVNBootstrapResult{
+		ControlPlaneReplicaCount: 3,
+		ControlPlaneUpdateStatus: &bootstrap.OVNUpdateStatus{
+			Kind:         "Deployment",
+			Namespace:    "openshift-ovn-kubernetes",
+			Name:         "ovnkube-control-plane",
+			Version:      "2.0.0",
+			IPFamilyMode: names.IPFamilySingleStack,
+		},
+		NodeUpdateStatus: &bootstrap.OVNUpdateStatus{
+			Kind:         "DaemonSet",
+			Namespace:    "openshift-ovn-kubernetes",
+			Name:         "ovnkube-node",
+			Version:      "2.0.0",
+			IPFamilyMode: names.IPFamilySingleStack,
+			TemplateHash: "old",
+			CanaryLabel:  "node-role.kubernetes.io/canary",
+		},
+		OVNKubernetesConfig: &bootstrap.OVNConfigBoostrapResult{
+			DpuHostModeLabel:  OVN_NODE_SELECTOR_DEFAULT_DPU_HOST,
+			DpuModeLabel:      OVN_NODE_SELECTOR_DEFAULT_DPU,
+			SmartNicModeLabel: OVN_NODE_SELECTOR_DEFAULT_SMART_NIC,
+			HyperShiftConfig: &bootstrap.OVNHyperShiftBootstrapResult{
+				Enabled: false,
+			},
+			NodeCanaryLabel: "node-role.kubernetes.io/canary",
+			NodeCanaryNodes: []string{"canary-0"},
+		},
+	}
+	featureGatesCNO := getDefaultFeatureGates()
+	fakeClient := cnofake.NewFakeClient()
+
+	var objs []*uns.Unstructured
+	renderNode := func() (*uns.Unstructured, *uns.Unstructured, bool) {
+		t.Helper()
+		var progressing bool
+		var err error
+		objs, progressing, err = renderOVNKubernetes(config, bootstrapResult, manifestDirOvn, fakeClient, featureGatesCNO)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		node := findInObjs("apps", "DaemonSet", "ovnkube-node", "openshift-ovn-kubernetes", objs)
+		if node == nil {
+			t.Fatalf("expected ovnkube-node DaemonSet to be rendered")
+		}
+		canary := findInObjs("apps", "DaemonSet", "ovnkube-node-canary", "openshift-ovn-kubernetes", objs)
+		return node, canary, progressing
+	}
+	render := func() (*uns.Unstructured, *uns.Unstructured, bool) {
+		t.Helper()
+		node, canary, progressing := renderNode()
+		if canary == nil {
+			t.Fatalf("expected ovnkube-node-canary DaemonSet to be rendered")
+		}
+		return node, canary, progressing
+	}
+	nodeAffinityTerms := func(ds *uns.Unstructured) []interface{} {
+		terms, _, _ := uns.NestedSlice(ds.Object, "spec", "template", "spec", "affinity", "nodeAffinity", "requiredDuringSchedulingIgnoredDuringExecution", "nodeSelectorTerms")
+		return terms
+	}
+
+	// the change goes to the canary first
+	node, canary, progressing := render()
+	if _, ok := node.GetAnnotations()[names.CreateOnlyAnnotation]; !ok {
+		t.Errorf("ovnkube-node DaemonSet should be create-only until the canary is rolled out")
+	}
+	if _, ok := canary.GetAnnotations()[names.CreateOnlyAnnotation]; ok {
+		t.Errorf("ovnkube-node-canary DaemonSet should be updated")
+	}
+	if !progressing {
+		t.Errorf("render should be progressing while waiting for the canary")
+	}
+	hash := canary.GetAnnotations()[names.OVNKubeNodeTemplateHashAnnotation]
+	if hash == "" || hash != node.GetAnnotations()[names.OVNKubeNodeTemplateHashAnnotation] {
+		t.Errorf("both DaemonSets should have the same template hash, got %q and %q", hash, node.GetAnnotations()[names.OVNKubeNodeTemplateHashAnnotation])
+	}
+	// the selectors of the two DaemonSets don't overlap
+	selector, _, _ := uns.NestedStringMap(canary.Object, "spec", "selector", "matchLabels")
+	if len(selector) != 1 || selector["app"] != "ovnkube-node-canary" {
+		t.Errorf("unexpected ovnkube-node-canary selector %v", selector)
+	}
+	podLabels, _, _ := uns.NestedStringMap(canary.Object, "spec", "template", "metadata", "labels")
+	if podLabels["app"] != "ovnkube-node-canary" {
+		t.Errorf("unexpected ovnkube-node-canary pod labels %v", podLabels)
+	}
+	if !hasExpression(nodeAffinityTerms(node), "node-role.kubernetes.io/canary", "DoesNotExist") {
+		t.Errorf("ovnkube-node should stay off of the canary nodes")
+	}
+	if !hasExpression(nodeAffinityTerms(canary), "node-role.kubernetes.io/canary", "Exists") {
+		t.Errorf("ovnkube-node-canary should only run on the canary nodes")
+	}
+	service := findInObjs("", "Service", "ovn-kubernetes-node-canary", "openshift-ovn-kubernetes", objs)
+	if service == nil {
+		t.Fatalf("expected ovn-kubernetes-node-canary Service to be rendered")
+	}
+	serviceSelector, _, _ := uns.NestedStringMap(service.Object, "spec", "selector")
+	if serviceSelector["app"] != "ovnkube-node-canary" || service.GetLabels()["app"] != "ovnkube-node" {
+		t.Errorf("the canary pods should be monitored like the ovnkube-node pods, got selector %v and labels %v", serviceSelector, service.GetLabels())
+	}
+
+	// the canary is still rolling out
+	bootstrapResult.OVN.NodeCanaryUpdateStatus = &bootstrap.OVNUpdateStatus{
+		Kind:         "DaemonSet",
+		Namespace:    "openshift-ovn-kubernetes",
+		Name:         "ovnkube-node-canary",
+		Version:      "2.0.0",
+		TemplateHash: hash,
+		Progressing:  true,
+	}
+	node, _, _ = render()
+	if _, ok := node.GetAnnotations()[names.CreateOnlyAnnotation]; !ok {
+		t.Errorf("ovnkube-node DaemonSet should be create-only while the canary is rolling out")
+	}
+
+	// the canary is rolled out but connectivity checks are failing
+	bootstrapResult.OVN.NodeCanaryUpdateStatus.Progressing = false
+	bootstrapResult.OVN.NodeCanaryFailedChecks = []string{"network-check-source-canary-0-to-network-check-target-worker-1"}
+	node, _, _ = render()
+	if _, ok := node.GetAnnotations()[names.CreateOnlyAnnotation]; !ok {
+		t.Errorf("ovnkube-node DaemonSet should be create-only while connectivity checks are failing")
+	}
+
+	// the canary is healthy
+	bootstrapResult.OVN.NodeCanaryFailedChecks = nil
+	node, _, progressing = render()
+	if _, ok := node.GetAnnotations()[names.CreateOnlyAnnotation]; ok {
+		t.Errorf("ovnkube-node DaemonSet should be updated once the canary is healthy")
+	}
+	if progressing {
+		t.Errorf("render should not be progressing once the canary is healthy")
+	}
+
+	// an override of ovnkube-node changes the template, and goes to the canary first
+	bootstrapResult.OperandOverrides.Data = map[string]string{"ovnkube-node": "nodeSelector:\n  node-role.kubernetes.io/edge: \"\"\n"}
+	node, canary, progressing = render()
+	if _, ok := node.GetAnnotations()[names.CreateOnlyAnnotation]; !ok {
+		t.Errorf("ovnkube-node DaemonSet should be create-only until the canary is rolled out with the overrides")
+	}
+	if !progressing {
+		t.Errorf("render should be progressing while waiting for the canary")
+	}
+	if canary.GetAnnotations()[names.OVNKubeNodeTemplateHashAnnotation] == hash {
+		t.Errorf("the overrides should change the template hash")
+	}
+	for _, ds := range []*uns.Unstructured{node, canary} {
+		nodeSelector, _, _ := uns.NestedStringMap(ds.Object, "spec", "template", "spec", "nodeSelector")
+		if _, ok := nodeSelector["node-role.kubernetes.io/edge"]; !ok {
+			t.Errorf("%s DaemonSet should have the overrides, got node selector %v", ds.GetName(), nodeSelector)
+		}
+	}
+	bootstrapResult.OperandOverrides.Data = nil
+
+	// changing the canary label first removes the canary, and keeps ovnkube-node
+	// off of the old canary nodes
+	bootstrapResult.OVN.OVNKubernetesConfig.NodeCanaryLabel = "node-role.kubernetes.io/other-canary"
+	bootstrapResult.OVN.NodeCanaryUpdateStatus.TemplateHash = "old"
+	node, canary, progressing = renderNode()
+	if canary != nil {
+		t.Errorf("ovnkube-node-canary DaemonSet should not be rendered when the canary label changes")
+	}
+	if _, ok := node.GetAnnotations()[names.CreateOnlyAnnotation]; !ok {
+		t.Errorf("ovnkube-node DaemonSet should be create-only until the canary is removed")
+	}
+	if !hasExpression(nodeAffinityTerms(node), "node-role.kubernetes.io/canary", "DoesNotExist") {
+		t.Errorf("ovnkube-node should stay off of the old canary nodes")
+	}
+	if !progressing {
+		t.Errorf("render should be progressing while waiting for the canary to be removed")
+	}
+
+	// once the canary is removed, ovnkube-node is rolled out to its new set of nodes
+	bootstrapResult.OVN.NodeCanaryUpdateStatus = nil
+	node, canary, _ = renderNode()
+	if canary != nil {
+		t.Errorf("ovnkube-node-canary DaemonSet should not be rendered before ovnkube-node is rolled out")
+	}
+	if _, ok := node.GetAnnotations()[names.CreateOnlyAnnotation]; ok {
+		t.Errorf("ovnkube-node DaemonSet should be updated once the canary is removed")
+	}
+	if hasExpression(nodeAffinityTerms(node), "node-role.kubernetes.io/canary", "DoesNotExist") ||
+		!hasExpression(nodeAffinityTerms(node), "node-role.kubernetes.io/other-canary", "DoesNotExist") {
+		t.Errorf("ovnkube-node should only stay off of the new canary nodes")
+	}
+	if node.GetAnnotations()[names.OVNKubeNodeCanaryLabelAnnotation] != "node-role.kubernetes.io/other-canary" {
+		t.Errorf("ovnkube-node should be annotated with the new canary label")
+	}
+
+	// the canary is only created once ovnkube-node left the new canary nodes
+	bootstrapResult.OVN.NodeUpdateStatus.CanaryLabel = "node-role.kubernetes.io/other-canary"
+	bootstrapResult.OVN.NodeUpdateStatus.TemplateHash = node.GetAnnotations()[names.OVNKubeNodeTemplateHashAnnotation]
+	bootstrapResult.OVN.NodeUpdateStatus.Progressing = true
+	_, canary, _ = renderNode()
+	if canary != nil {
+		t.Errorf("ovnkube-node-canary DaemonSet should not be rendered while ovnkube-node is rolling out")
+	}
+	bootstrapResult.OVN.NodeUpdateStatus.Progressing = false
+	_, canary, _ = render()
+	if !hasExpression(nodeAffinityTerms(canary), "node-role.kubernetes.io/other-canary", "Exists") {
+		t.Errorf("ovnkube-node-canary should only run on the new canary nodes")
+	}
+
+	// disabling canary rollouts removes the canary before ovnkube-node runs on the canary nodes again
+	bootstrapResult.OVN.OVNKubernetesConfig.NodeCanaryLabel = ""
+	bootstrapResult.OVN.NodeCanaryUpdateStatus = &bootstrap.OVNUpdateStatus{
+		Kind:         "DaemonSet",
+		Namespace:    "openshift-ovn-kubernetes",
+		Name:         "ovnkube-node-canary",
+		Version:      "2.0.0",
+		TemplateHash: bootstrapResult.OVN.NodeUpdateStatus.TemplateHash,
+	}
+	node, canary, _ = renderNode()
+	if canary != nil {
+		t.Errorf("ovnkube-node-canary DaemonSet should not be rendered once canary rollouts are disabled")
+	}
+	if _, ok := node.GetAnnotations()[names.CreateOnlyAnnotation]; !ok {
+		t.Errorf("ovnkube-node DaemonSet should be create-only until the canary is removed")
+	}
+	bootstrapResult.OVN.NodeCanaryUpdateStatus = nil
+	node, _, _ = renderNode()
+	if _, ok := node.GetAnnotations()[names.CreateOnlyAnnotation]; ok {
+		t.Errorf("ovnkube-node DaemonSet should be updated once the canary is removed")
+	}
+	if hasExpression(nodeAffinityTerms(node), "node-role.kubernetes.io/other-canary", "DoesNotExist") {
+		t.Errorf("ovnkube-node should run on all nodes once canary rollouts are disabled")
+	}
+
+	// canary rollouts are refused on clusters with SmartNIC or DPU nodes
+	bootstrapResult.OVN.OVNKubernetesConfig.NodeCanaryLabel = "node-role.kubernetes.io/other-canary"
+	bootstrapResult.OVN.OVNKubernetesConfig.SmartNicModeNodes = []string{"smart-nic-0"}
+	if _, _, err := renderOVNKubernetes(config, bootstrapResult, manifestDirOvn, fakeClient, featureGatesCNO); err == nil {
+		t.Errorf("expected canary rollouts to be refused on a cluster with SmartNIC nodes")
+	}
+}
+
+func hasExpression(terms []interface{}, key, operator string) bool {
+	if len(terms) == 0 {
+		return false
+	}
+	for _, term := range terms {
+		expressions, _, _ := uns.NestedSlice(term.(map[string]interface{}), "matchExpressions")
+		found := false
+		for _, e := range expressions {
+			e := e.(map[string]interface{})
+			if e["key"] == key && e["operator"] == operator {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func Test_unreachableChecksToNodes(t *testing.T) {
+	check := func(source, target, reachable string) uns.Unstructured {
+		return uns.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name": "network-check-source-" + source + "-to-network-check-target-" + target,
+				"ownerReferences": []interface{}{
+					map[string]interface{}{"apiVersion": "v1", "kind": "Node", "name": source, "uid": source},
+				},
+			},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Reachable", "status": reachable},
+				},
+			},
+		}}
+	}
+
+	// The only network-check-source pod runs off of the canary nodes
+	checks := []uns.Unstructured{
+		check("worker-0", "canary-0", "True"),
+		check("worker-0", "canary-1", "False"),
+		check("worker-0", "canary-10", "False"),
+		check("worker-0", "worker-1", "False"),
+		check("canary-0", "worker-1", "False"),
+	}
+	failed := unreachableChecksToNodes(checks, []string{"canary-0.example.com", "canary-1"})
+	if len(failed) != 1 || failed[0] != "network-check-source-worker-0-to-network-check-target-canary-1" {
+		t.Errorf("unexpected unreachable checks %v", failed)
+	}
+
+	// A canary node without checks is not considered reachable
+	failed = unreachableChecksToNodes(checks, []string{"canary-0", "canary-2"})
+	if len(failed) != 1 || failed[0] != "no check to network-check-target on node canary-2" {
+		t.Errorf("unexpected unreachable checks %v", failed)
+	}
+
+	// Without network diagnostics, there is nothing to gate on
+	if failed := unreachableChecksToNodes(nil, []string{"canary-0"}); len(failed) != 0 {
+		t.Errorf("unexpected unreachable checks %v", failed)
+	}
+}
@@ -19,8 +19,10 @@ import (
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -1182,8 +1184,8 @@ func TestValidateOVNKubernetesSubnetsIPv4(t *testing.T) {
 	ovnConfig.IPv4 = &operv1.IPv4OVNKubernetesConfig{}
 	ovnConfig.IPv6 = &operv1.IPv6OVNKubernetesConfig{}
 
-	err := validateOVNKubernetesSubnets(config)
-	g.Expect(err).NotTo(HaveOccurred())
+	errs := validateOVNKubernetesSubnets(config)
+	g.Expect(errs).To(BeEmpty())
 	fillDefaults(config, nil)
 
 	errExpect := func(substr string) {
@@ -1259,8 +1261,8 @@ func TestValidateOVNKubernetesSubnetsIPv6(t *testing.T) {
 	ovnConfig.IPv4 = &operv1.IPv4OVNKubernetesConfig{}
 	ovnConfig.IPv6 = &operv1.IPv6OVNKubernetesConfig{}
 
-	err := validateOVNKubernetesSubnets(config)
-	g.Expect(err).NotTo(HaveOccurred())
+	errs := validateOVNKubernetesSubnets(config)
+	g.Expect(errs).To(BeEmpty())
 	fillDefaults(config, nil)
 
 	errExpect := func(substr string) {
@@ -4114,3 +4116,91 @@ func Test_renderOVNKubernetes(t *testing.T) {
 		})
 	}
 }
+
+func TestGetMasqueradeSubnet(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	v4, v6 := GetMasqueradeSubnet(nil)
+	g.Expect(v4).To(Equal(defaultV4MasqueradeSubnet))
+	g.Expect(v6).To(Equal(defaultV6MasqueradeSubnet))
+
+	v4, v6 = GetMasqueradeSubnet(&operv1.OVNKubernetesConfig{
+		GatewayConfig: &operv1.GatewayConfig{
+			IPv6: operv1.IPv6GatewayConfig{InternalMasqueradeSubnet: "fd69::/112"},
+		},
+	})
+	g.Expect(v4).To(Equal(defaultV4MasqueradeSubnet))
+	g.Expect(v6).To(Equal("fd69::/112"))
+}
+
+func TestRenderOVNKubernetesHyperShiftSchedulingOptions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	crd := OVNKubernetesConfig.DeepCopy()
+	config := &crd.Spec
+	fillDefaults(config, nil)
+
+	hcp := &hypershift.HostedControlPlane{
+		ControllerAvailabilityPolicy: hypershift.HighlyAvailable,
+		Annotations:                  map[string]string{"example.com/team": "network", "example.com/config": `{"quote": "a \"b\": c"}`},
+		TopologySpreadConstraints: []v1.TopologySpreadConstraint{{
+			MaxSkew:           1,
+			TopologyKey:       "topology.kubernetes.io/zone",
+			WhenUnsatisfiable: v1.DoNotSchedule,
+		}},
+		PodAntiAffinity: &v1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{{
+				Weight: 10,
+				PodAffinityTerm: v1.PodAffinityTerm{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "noisy"}},
+					TopologyKey:   "kubernetes.io/hostname",
+				},
+			}},
+		},
+		ResourceRequestOverrides: map[string]v1.ResourceList{
+			"ovnkube-control-plane.ovnkube-control-plane": {v1.ResourceCPU: resource.MustParse("100m")},
+		},
+	}
+	bootstrapResult := fakeBootstrapResultWithHyperShift()
+	bootstrapResult.Infra.HostedControlPlane = hcp
+	hyperShiftResult, err := bootstrapOVNHyperShiftConfig(&hypershift.HyperShiftConfig{Enabled: true, Namespace: "clusters-test", ReleaseImage: "release:latest"}, nil, &bootstrapResult.Infra)
+	g.Expect(err).NotTo(HaveOccurred())
+	bootstrapResult.OVN = bootstrap.OVNBootstrapResult{
+		ControlPlaneReplicaCount: hyperShiftResult.ControlPlaneReplicas,
+		OVNKubernetesConfig: &bootstrap.OVNConfigBoostrapResult{
+			DpuHostModeLabel:  OVN_NODE_SELECTOR_DEFAULT_DPU_HOST,
+			DpuModeLabel:      OVN_NODE_SELECTOR_DEFAULT_DPU,
+			SmartNicModeLabel: OVN_NODE_SELECTOR_DEFAULT_SMART_NIC,
+			HyperShiftConfig:  hyperShiftResult,
+		},
+	}
+
+	objs, _, err := renderOVNKubernetes(config, bootstrapResult, manifestDirOvn, cnofake.NewFakeClient(), getDefaultFeatureGates())
+	g.Expect(err).NotTo(HaveOccurred())
+	obj := findInObjs("apps", "Deployment", "ovnkube-control-plane", "clusters-test", objs)
+	g.Expect(obj).NotTo(BeNil())
+	deployment := &appsv1.Deployment{}
+	g.Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deployment)).To(Succeed())
+	podSpec := deployment.Spec.Template.Spec
+
+	g.Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue("example.com/team", "network"))
+	g.Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue("example.com/config", `{"quote": "a \"b\": c"}`))
+	g.Expect(podSpec.TopologySpreadConstraints).To(Equal([]v1.TopologySpreadConstraint{{
+		MaxSkew:           1,
+		TopologyKey:       "topology.kubernetes.io/zone",
+		WhenUnsatisfiable: v1.DoNotSchedule,
+		LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "ovnkube-control-plane"}},
+	}}))
+	// The anti-affinity of the replicas is kept
+	g.Expect(podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+	g.Expect(podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(Equal(hcp.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution))
+	g.Expect(podSpec.Affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+	for _, container := range podSpec.Containers {
+		cpu := container.Resources.Requests[v1.ResourceCPU]
+		if container.Name == "ovnkube-control-plane" {
+			g.Expect(cpu.String()).To(Equal("100m"))
+		} else {
+			g.Expect(cpu.String()).To(Equal("10m"))
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package network
+
+import (
+	"fmt"
+	"log"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
+
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
+)
+
+// NetworkPlugin implements a default network type. Plugins register
+// themselves with RegisterNetworkPlugin, usually from an init function.
+type NetworkPlugin interface {
+	// Validate returns the errors in the configuration of the plugin, with
+	// the path of each invalid field.
+	Validate(conf *operv1.NetworkSpec) field.ErrorList
+
+	// FillDefaults fills in the default values of the plugin configuration,
+	// carrying them forward from previous, if set.
+	FillDefaults(conf, previous *operv1.NetworkSpec, hostMTU int)
+
+	// DropConfig removes the configuration of the plugin from conf, when the
+	// plugin is not deployed.
+	DropConfig(conf *operv1.NetworkSpec)
+
+	// NeedsMTUProbe returns true if the configuration of the plugin has no
+	// MTU, so that the MTU of the cluster has to be probed.
+	NeedsMTUProbe(conf *operv1.NetworkSpec) bool
+
+	// IsChangeSafe returns the errors for the changes between prev and next
+	// that the plugin does not support.
+	IsChangeSafe(prev, next *operv1.NetworkSpec) []error
+
+	// Bootstrap gathers the cluster state the plugin needs to render, and
+	// records it in out.
+	Bootstrap(conf *operv1.Network, client cnoclient.Client, out *bootstrap.BootstrapResult) error
+
+	// Render generates the manifests of the plugin. It also returns true if
+	// the rollout is in progress and rendering should be retried.
+	Render(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.BootstrapResult, manifestDir string,
+		client cnoclient.Client, featureGates featuregates.FeatureGate) ([]*uns.Unstructured, bool, error)
+
+	// RenderMigrationCRDs generates the CRDs of the network type that the
+	// custom resources of the plugin are converted to or from during a
+	// migration of the default network type.
+	RenderMigrationCRDs(conf *operv1.NetworkSpec, manifestDir string, featureGates featuregates.FeatureGate) ([]*uns.Unstructured, error)
+
+	// Status fills in the fields of the cluster network status that are
+	// specific to the plugin. It returns false if the operator does not own
+	// the status, in which case whatever the plugin itself set is preserved.
+	Status(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.BootstrapResult, status *configv1.NetworkStatus) bool
+}
+
+var networkPlugins = map[operv1.NetworkType]NetworkPlugin{}
+
+// RegisterNetworkPlugin registers the plugin implementing a default network type.
+// It panics if a plugin is already registered for that type.
+func RegisterNetworkPlugin(networkType operv1.NetworkType, plugin NetworkPlugin) {
+	if _, ok := networkPlugins[networkType]; ok {
+		panic(fmt.Sprintf("network plugin %q is already registered", networkType))
+	}
+	networkPlugins[networkType] = plugin
+}
+
+// GetNetworkPlugin returns the plugin implementing a default network type. For
+// unregistered types, that is a plugin that does not render anything, since
+// the network is then deployed by a third party.
+func GetNetworkPlugin(networkType operv1.NetworkType) NetworkPlugin {
+	if plugin, ok := networkPlugins[networkType]; ok {
+		return plugin
+	}
+	return thirdPartyPlugin{networkType: networkType}
+}
+
+// thirdPartyPlugin is the plugin for network types not deployed by the operator.
+// When the external network mode is configured, the operator still owns the
+// cluster network status and reports the health of the third-party CNI.
+type thirdPartyPlugin struct {
+	networkType operv1.NetworkType
+}
+
+func (thirdPartyPlugin) Validate(conf *operv1.NetworkSpec) field.ErrorList {
+	return nil
+}
+
+func (thirdPartyPlugin) FillDefaults(conf, previous *operv1.NetworkSpec, hostMTU int) {}
+
+func (thirdPartyPlugin) DropConfig(conf *operv1.NetworkSpec) {}
+
+func (thirdPartyPlugin) NeedsMTUProbe(conf *operv1.NetworkSpec) bool {
+	return false
+}
+
+func (thirdPartyPlugin) IsChangeSafe(prev, next *operv1.NetworkSpec) []error {
+	return nil
+}
+
+func (thirdPartyPlugin) Bootstrap(conf *operv1.Network, client cnoclient.Client, out *bootstrap.BootstrapResult) error {
+	ext, err := bootstrapExternalNetwork(client)
+	if err != nil {
+		return err
+	}
+	out.ExternalNetwork = *ext
+	return nil
+}
+
+func (p thirdPartyPlugin) Render(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.BootstrapResult, manifestDir string,
+	client cnoclient.Client, featureGates featuregates.FeatureGate) ([]*uns.Unstructured, bool, error) {
+	if bootstrapResult.ExternalNetwork.Enabled {
+		log.Printf("Network type %s is deployed externally, not rendering it", p.networkType)
+	} else {
+		log.Printf("NOTICE: Unknown network type %s, ignoring", p.networkType)
+	}
+	return nil, false, nil
+}
+
+func (p thirdPartyPlugin) RenderMigrationCRDs(conf *operv1.NetworkSpec, manifestDir string, featureGates featuregates.FeatureGate) ([]*uns.Unstructured, error) {
+	log.Printf("NOTICE: Unsupported network type %s, ignoring", p.networkType)
+	return nil, nil
+}
+
+func (thirdPartyPlugin) Status(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.BootstrapResult, status *configv1.NetworkStatus) bool {
+	ext := bootstrapResult.ExternalNetwork
+	if !ext.Enabled {
+		return false
+	}
+	// An unknown MTU is left to StatusFromOperatorConfig, which keeps the current one
+	if ext.MTU > 0 {
+		status.ClusterNetworkMTU = ext.MTU
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package network
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
+
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
+)
+
+// testPlugin is a NetworkPlugin that renders a single ConfigMap and reports
+// a fixed MTU, which it needs probed.
+type testPlugin struct {
+	thirdPartyPlugin
+}
+
+func (testPlugin) NeedsMTUProbe(conf *operv1.NetworkSpec) bool {
+	return true
+}
+
+func (testPlugin) Render(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.BootstrapResult, manifestDir string,
+	client cnoclient.Client, featureGates featuregates.FeatureGate) ([]*uns.Unstructured, bool, error) {
+	obj := &uns.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("openshift-test-plugin")
+	obj.SetName("test-plugin")
+	return []*uns.Unstructured{obj}, true, nil
+}
+
+func (testPlugin) Status(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.BootstrapResult, status *configv1.NetworkStatus) bool {
+	status.ClusterNetworkMTU = 1234
+	return true
+}
+
+func TestNetworkPluginRegistry(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(GetNetworkPlugin(operv1.NetworkTypeOVNKubernetes)).To(Equal(ovnKubernetesPlugin{}))
+	g.Expect(GetNetworkPlugin(operv1.NetworkTypeOpenShiftSDN)).To(Equal(openShiftSDNPlugin{}))
+	g.Expect(GetNetworkPlugin("Calico")).To(Equal(thirdPartyPlugin{networkType: "Calico"}))
+
+	g.Expect(func() {
+		RegisterNetworkPlugin(operv1.NetworkTypeOVNKubernetes, testPlugin{})
+	}).To(Panic())
+}
+
+func TestRegisteredNetworkPlugin(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	const networkType operv1.NetworkType = "TestPlugin"
+	RegisterNetworkPlugin(networkType, testPlugin{})
+	defer delete(networkPlugins, networkType)
+
+	conf := &operv1.NetworkSpec{
+		ServiceNetwork: []string{"172.30.0.0/16"},
+		DefaultNetwork: operv1.DefaultNetworkDefinition{
+			Type:                networkType,
+			OVNKubernetesConfig: &operv1.OVNKubernetesConfig{},
+		},
+	}
+	g.Expect(NeedMTUProbe(nil, conf)).To(BeTrue())
+
+	// The configurations of the other plugins are dropped
+	fillDefaultNetworkDefaults(conf, nil, 1500)
+	g.Expect(conf.DefaultNetwork.OVNKubernetesConfig).To(BeNil())
+
+	objs, progressing, err := renderDefaultNetwork(conf, &bootstrap.BootstrapResult{}, manifestDir, nil, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(progressing).To(BeTrue())
+	g.Expect(objs).To(HaveLen(1))
+	g.Expect(objs[0].GetName()).To(Equal("test-plugin"))
+
+	// The status of a registered plugin is owned by the operator
+	status := StatusFromOperatorConfig(conf, &bootstrap.BootstrapResult{}, &configv1.NetworkStatus{ClusterNetworkMTU: 1500})
+	g.Expect(status.NetworkType).To(Equal(string(networkType)))
+	g.Expect(status.ClusterNetworkMTU).To(Equal(1234))
+}
@@ -12,7 +12,6 @@ import (
 	"strings"
 
 	configv1 "github.com/openshift/api/config/v1"
-	apifeatures "github.com/openshift/api/features"
 	operv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
 	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
@@ -22,6 +21,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/util/retry"
 	utilnet "k8s.io/utils/net"
 
@@ -85,6 +85,13 @@ func Render(operConf *operv1.NetworkSpec, clusterConf *configv1.NetworkSpec, man
 	}
 	objs = append(objs, o...)
 
+	// render the ServiceCIDRs of a service network migration
+	o, err = renderServiceNetworkMigration(bootstrapResult, manifestDir)
+	if err != nil {
+		return nil, progressing, err
+	}
+	objs = append(objs, o...)
+
 	if operConf.Migration != nil && operConf.Migration.NetworkType != "" {
 		// During SDN Migration, CNO needs to convert the custom resources of
 		// egressIP, egressFirewall, etc. Therefore we need to render the CRDs for
@@ -161,8 +168,8 @@ func Render(operConf *operv1.NetworkSpec, clusterConf *configv1.NetworkSpec, man
 		objs = append(objs, o...)
 	}
 
-	err = registerNetworkingConsolePlugin(bootstrapResult, client)
-	if err != nil {
+	// apply the supported overrides of the rendered operands
+	if err := renderOperandOverrides(objs, bootstrapResult); err != nil {
 		return nil, progressing, err
 	}
 
@@ -256,18 +263,24 @@ func DeprecatedCanonicalize(conf *operv1.NetworkSpec) {
 // Validate checks that the supplied configuration is reasonable.
 // This should be called after Canonicalize
 func Validate(conf *operv1.NetworkSpec) error {
-	errs := []error{}
+	if errs := ValidateFields(conf); len(errs) > 0 {
+		return errors.Errorf("invalid configuration: %v", errs)
+	}
+	return nil
+}
+
+// ValidateFields checks that the supplied configuration is reasonable, and
+// returns an error for each invalid field. This should be called after
+// Canonicalize
+func ValidateFields(conf *operv1.NetworkSpec) field.ErrorList {
+	errs := field.ErrorList{}
 
 	errs = append(errs, validateIPPools(conf)...)
 	errs = append(errs, validateDefaultNetwork(conf)...)
 	errs = append(errs, validateMultus(conf)...)
 	errs = append(errs, validateKubeProxy(conf)...)
 	errs = append(errs, validateMigration(conf)...)
-
-	if len(errs) > 0 {
-		return errors.Errorf("invalid configuration: %v", errs)
-	}
-	return nil
+	return errs
 }
 
 // FillDefaults computes any default values and applies them to the configuration
@@ -303,6 +316,15 @@ func FillDefaults(conf, previous *operv1.NetworkSpec, hostMTU int) {
 // FillDefaults and Validate should have been called, but beware that prev may
 // be from an older version.
 func IsChangeSafe(prev, next *operv1.NetworkSpec, infraStatus *bootstrap.InfraStatus) error {
+	if errs := changeSafetyErrors(prev, next, infraStatus); len(errs) > 0 {
+		return errors.Errorf("invalid configuration: %v", errs)
+	}
+	return nil
+}
+
+// changeSafetyErrors returns every reason why the change between prev and
+// next is not allowed.
+func changeSafetyErrors(prev, next *operv1.NetworkSpec, infraStatus *bootstrap.InfraStatus) []error {
 	if prev == nil {
 		return nil
 	}
@@ -336,10 +358,7 @@ func IsChangeSafe(prev, next *operv1.NetworkSpec, infraStatus *bootstrap.InfraSt
 	// Check kube-proxy
 	errs = append(errs, isKubeProxyChangeSafe(prev, next)...)
 
-	if len(errs) > 0 {
-		return errors.Errorf("invalid configuration: %v", errs)
-	}
-	return nil
+	return errs
 }
 
 // NeedMTUProbe returns true if we need to probe the cluster's MTU.
@@ -347,18 +366,7 @@ func IsChangeSafe(prev, next *operv1.NetworkSpec, infraStatus *bootstrap.InfraSt
 // to "carry forward". If not, we'll have to probe it.
 func NeedMTUProbe(prev, next *operv1.NetworkSpec) bool {
 	needsMTU := func(c *operv1.NetworkSpec) bool {
-		if c == nil {
-			return true
-		}
-		d := c.DefaultNetwork
-		switch d.Type {
-		case operv1.NetworkTypeOVNKubernetes:
-			return d.OVNKubernetesConfig == nil || d.OVNKubernetesConfig.MTU == nil || *d.OVNKubernetesConfig.MTU == 0
-		case operv1.NetworkTypeOpenShiftSDN:
-			return d.OpenShiftSDNConfig == nil || d.OpenShiftSDNConfig.MTU == nil || *d.OpenShiftSDNConfig.MTU == 0
-		}
-		// other network types don't need MTU
-		return false
+		return c == nil || GetNetworkPlugin(c.DefaultNetwork.Type).NeedsMTUProbe(c)
 	}
 	return needsMTU(prev) && needsMTU(next)
 }
@@ -397,7 +405,7 @@ func isNetworkChangeSafe(prev, next *operv1.NetworkSpec, infraRes *bootstrap.Inf
 		return errors.Errorf("unsupported change to ServiceNetwork")
 	default:
 		// this is not a single/dual stack migration; check if the clusterNetwork change is ok
-		return isClusterNetworkChangeSafe(prev, next)
+		return isClusterNetworkChangeSafe(prev, next, infraRes)
 	}
 
 	// Validate that this is either a BareMetal or None PlatformType. For all other
@@ -440,7 +448,12 @@ func isNetworkChangeSafe(prev, next *operv1.NetworkSpec, infraRes *bootstrap.Inf
 	return nil
 }
 
-func isClusterNetworkChangeSafe(prev, next *operv1.NetworkSpec) error {
+// isClusterNetworkChangeSafe checks a change of the clusterNetwork entries that
+// is not a single/dual stack migration. With OVNKubernetes, a CIDR can be
+// widened and its hostPrefix increased, as long as the pod subnets already
+// allocated to nodes stay within the CIDRs. Nodes keep the subnet they have;
+// only nodes that join afterwards get a subnet with the new hostPrefix.
+func isClusterNetworkChangeSafe(prev, next *operv1.NetworkSpec, infraRes *bootstrap.InfraStatus) error {
 
 	// quick check to make sure clusterNetwork slices are of same size as we do not
 	// support adding/removing additional clusterNetwork entries unless it's for a
@@ -474,31 +487,142 @@ func isClusterNetworkChangeSafe(prev, next *operv1.NetworkSpec) error {
 		if err != nil {
 			return errors.Errorf("error parsing CIDR from ClusterNetwork entry %s: %v", next.ClusterNetwork[i].CIDR, err)
 		}
-		prevHostPrefix := e.HostPrefix
-		nextHostPrefix := next.ClusterNetwork[i].HostPrefix
-
-		// changing hostPrefix is not allowed
-		if prevHostPrefix != nextHostPrefix {
-			return errors.Errorf("modifying a clusterNetwork's hostPrefix value is unsupported")
-		}
-
 		if !prevIp.Equal(nextIp) {
 			return errors.Errorf("modifying IP network value for clusterNetwork CIDR is unsupported")
 		}
 
+		// Only smaller node subnets can be allocated next to the existing
+		// ones: a larger subnet could overlap with several of them.
+		if next.ClusterNetwork[i].HostPrefix < e.HostPrefix {
+			return errors.Errorf("modifying a clusterNetwork's hostPrefix value is unsupported, except to increase it")
+		}
+
 		prevMaskSize, _ := prevMask.Mask.Size()
 		nextMaskSize, _ := nextMask.Mask.Size()
 		if prevMaskSize < nextMaskSize {
 			return errors.Errorf("reducing IP range with a larger CIDR mask for clusterNetwork CIDR is unsupported")
 		}
 	}
+
+	if err := validateClusterNetworkOverlaps(next, infraRes); err != nil {
+		return err
+	}
+	return validateNodeSubnetsContained(next, infraRes)
+}
+
+// validateClusterNetworkOverlaps checks that the clusterNetwork CIDRs don't
+// overlap with the machine network, the service network or the internal
+// subnets of ovn-kubernetes, including the default ones that are not in the
+// configuration. Widening a CIDR can make it overlap with them.
+func validateClusterNetworkOverlaps(conf *operv1.NetworkSpec, infraRes *bootstrap.InfraStatus) error {
+	type namedSubnet struct {
+		name   string
+		subnet string
+	}
+	others := []namedSubnet{}
+	for _, snet := range conf.ServiceNetwork {
+		others = append(others, namedSubnet{"serviceNetwork", snet})
+	}
+	for _, mnet := range infraRes.MachineNetworks {
+		others = append(others, namedSubnet{"machineNetwork", mnet})
+	}
+	oc := conf.DefaultNetwork.OVNKubernetesConfig
+	v4Join, v6Join := GetInternalSubnets(oc)
+	v4Transit, v6Transit := GetTransitSwitchSubnets(oc)
+	v4Masquerade, v6Masquerade := GetMasqueradeSubnet(oc)
+	others = append(others,
+		namedSubnet{"internalJoinSubnet", v4Join}, namedSubnet{"internalJoinSubnet", v6Join},
+		namedSubnet{"internalTransitSwitchSubnet", v4Transit}, namedSubnet{"internalTransitSwitchSubnet", v6Transit},
+		namedSubnet{"internalMasqueradeSubnet", v4Masquerade}, namedSubnet{"internalMasqueradeSubnet", v6Masquerade})
+
+	for _, other := range others {
+		_, cidr, err := net.ParseCIDR(other.subnet)
+		// The default route of a machine network is not an overlap
+		if err != nil || other.subnet == "0.0.0.0/0" || other.subnet == "::/0" {
+			continue
+		}
+		pool := iputil.IPPool{}
+		for _, cn := range conf.ClusterNetwork {
+			_, cnCIDR, err := net.ParseCIDR(cn.CIDR)
+			if err != nil {
+				return errors.Errorf("error parsing CIDR from ClusterNetwork entry %s: %v", cn.CIDR, err)
+			}
+			// validateIPPools already checked that the clusterNetwork entries don't overlap
+			_ = pool.Add(*cnCIDR)
+		}
+		if err := pool.Add(*cidr); err != nil {
+			return errors.Errorf("clusterNetwork overlaps with the %s %s: %v", other.name, other.subnet, err)
+		}
+	}
+	return nil
+}
+
+// validateNodeSubnetsContained checks that every pod subnet already allocated
+// to a node is within a clusterNetwork CIDR.
+func validateNodeSubnetsContained(conf *operv1.NetworkSpec, infraRes *bootstrap.InfraStatus) error {
+	outside := []string{}
+	for node, subnets := range infraRes.NodeSubnets {
+		for _, subnet := range subnets {
+			if _, ok := containingClusterNetwork(conf, subnet); !ok {
+				outside = append(outside, fmt.Sprintf("%s (%s)", node, subnet))
+			}
+		}
+	}
+	if len(outside) > 0 {
+		sort.Strings(outside)
+		return errors.Errorf("the pod subnets of nodes %s are not within the clusterNetwork", strings.Join(outside, ", "))
+	}
 	return nil
 }
 
+// containingClusterNetwork returns the clusterNetwork entry that contains a
+// node subnet.
+func containingClusterNetwork(conf *operv1.NetworkSpec, subnet string) (operv1.ClusterNetworkEntry, bool) {
+	_, subnetCIDR, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return operv1.ClusterNetworkEntry{}, false
+	}
+	subnetSize, subnetBits := subnetCIDR.Mask.Size()
+	for _, cn := range conf.ClusterNetwork {
+		_, cnCIDR, err := net.ParseCIDR(cn.CIDR)
+		if err != nil {
+			continue
+		}
+		cnSize, cnBits := cnCIDR.Mask.Size()
+		if cnBits == subnetBits && cnSize <= subnetSize && cnCIDR.Contains(subnetCIDR.IP) {
+			return cn, true
+		}
+	}
+	return operv1.ClusterNetworkEntry{}, false
+}
+
+// NodesWithStaleSubnets returns the sorted names of the nodes whose pod subnet
+// was allocated with a different hostPrefix than the one of its clusterNetwork
+// entry, i.e. before the hostPrefix was changed.
+func NodesWithStaleSubnets(conf *operv1.NetworkSpec, infraStatus *bootstrap.InfraStatus) []string {
+	stale := []string{}
+	for node, subnets := range infraStatus.NodeSubnets {
+		for _, subnet := range subnets {
+			cn, ok := containingClusterNetwork(conf, subnet)
+			if !ok {
+				continue
+			}
+			_, subnetCIDR, _ := net.ParseCIDR(subnet)
+			if size, _ := subnetCIDR.Mask.Size(); size != int(cn.HostPrefix) {
+				stale = append(stale, node)
+				break
+			}
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
 // validateIPPools checks that all IP addresses are valid
-// TODO: check for overlap
-func validateIPPools(conf *operv1.NetworkSpec) []error {
-	errs := []error{}
+func validateIPPools(conf *operv1.NetworkSpec) field.ErrorList {
+	errs := field.ErrorList{}
+	serviceNetworkPath := field.NewPath("spec", "serviceNetwork")
+	clusterNetworkPath := field.NewPath("spec", "clusterNetwork")
 
 	// Check all networks for overlaps
 	pool := iputil.IPPool{}
@@ -506,10 +630,10 @@ func validateIPPools(conf *operv1.NetworkSpec) []error {
 	var ipv4Service, ipv6Service, ipv4Cluster, ipv6Cluster bool
 
 	// Validate ServiceNetwork values
-	for _, snet := range conf.ServiceNetwork {
+	for i, snet := range conf.ServiceNetwork {
 		_, cidr, err := net.ParseCIDR(snet)
 		if err != nil {
-			errs = append(errs, errors.Wrapf(err, "could not parse spec.serviceNetwork %s", snet))
+			errs = append(errs, field.Invalid(serviceNetworkPath.Index(i), snet, fmt.Sprintf("could not parse CIDR: %v", err)))
 			continue
 		}
 		if utilnet.IsIPv6CIDR(cidr) {
@@ -518,17 +642,15 @@ func validateIPPools(conf *operv1.NetworkSpec) []error {
 			ipv4Service = true
 		}
 		if err := pool.Add(*cidr); err != nil {
-			errs = append(errs, errors.Errorf("Whole or subset of ServiceNetwork CIDR %s is already in use: %s", snet, err))
+			errs = append(errs, field.Invalid(serviceNetworkPath.Index(i), snet, fmt.Sprintf("whole or subset of CIDR is already in use: %s", err)))
 		}
 	}
 
 	// Validate count / dual-stack-ness
 	if len(conf.ServiceNetwork) == 0 {
-		errs = append(errs, errors.Errorf("spec.serviceNetwork must have at least 1 entry"))
-	} else if len(conf.ServiceNetwork) == 2 && !(ipv4Service && ipv6Service) {
-		errs = append(errs, errors.Errorf("spec.serviceNetwork must contain at most one IPv4 and one IPv6 network"))
-	} else if len(conf.ServiceNetwork) > 2 {
-		errs = append(errs, errors.Errorf("spec.serviceNetwork must contain at most one IPv4 and one IPv6 network"))
+		errs = append(errs, field.Required(serviceNetworkPath, "must have at least 1 entry"))
+	} else if len(conf.ServiceNetwork) > 2 || (len(conf.ServiceNetwork) == 2 && !(ipv4Service && ipv6Service)) {
+		errs = append(errs, field.Invalid(serviceNetworkPath, conf.ServiceNetwork, "must contain at most one IPv4 and one IPv6 network"))
 	}
 
 	// validate clusternetwork
@@ -536,10 +658,11 @@ func validateIPPools(conf *operv1.NetworkSpec) []error {
 	// - it is a valid ip
 	// - has a reasonable cidr
 	// - they do not overlap and do not overlap with the service cidr
-	for _, cnet := range conf.ClusterNetwork {
+	for i, cnet := range conf.ClusterNetwork {
+		cidrPath := clusterNetworkPath.Index(i).Child("cidr")
 		_, cidr, err := net.ParseCIDR(cnet.CIDR)
 		if err != nil {
-			errs = append(errs, errors.Errorf("could not parse spec.clusterNetwork %s", cnet.CIDR))
+			errs = append(errs, field.Invalid(cidrPath, cnet.CIDR, fmt.Sprintf("could not parse CIDR: %v", err)))
 			continue
 		}
 		if utilnet.IsIPv6CIDR(cidr) {
@@ -549,34 +672,34 @@ func validateIPPools(conf *operv1.NetworkSpec) []error {
 		}
 		// ignore hostPrefix if the plugin does not use it and has it unset
 		if pluginsUsingHostPrefix.Has(string(conf.DefaultNetwork.Type)) || (cnet.HostPrefix != 0) {
+			hostPrefixPath := clusterNetworkPath.Index(i).Child("hostPrefix")
 			ones, bits := cidr.Mask.Size()
 			// The comparison is inverted; smaller number is larger block
 			if cnet.HostPrefix < uint32(ones) {
-				errs = append(errs, errors.Errorf("hostPrefix %d is larger than its cidr %s",
-					cnet.HostPrefix, cnet.CIDR))
+				errs = append(errs, field.Invalid(hostPrefixPath, int64(cnet.HostPrefix), fmt.Sprintf("is larger than its cidr %s", cnet.CIDR)))
 			}
 			if int(cnet.HostPrefix) > bits-2 {
-				errs = append(errs, errors.Errorf("hostPrefix %d is too small, must be a /%d or larger",
-					cnet.HostPrefix, bits-2))
+				errs = append(errs, field.Invalid(hostPrefixPath, int64(cnet.HostPrefix), fmt.Sprintf("is too small, must be a /%d or larger", bits-2)))
 			}
 		}
 		if err := pool.Add(*cidr); err != nil {
-			errs = append(errs, errors.Errorf("Whole or subset of ClusterNetwork CIDR %s is already in use: %s", cnet.CIDR, err))
+			errs = append(errs, field.Invalid(cidrPath, cnet.CIDR, fmt.Sprintf("whole or subset of CIDR is already in use: %s", err)))
 		}
 	}
 
 	if len(conf.ClusterNetwork) < 1 {
-		errs = append(errs, errors.Errorf("spec.clusterNetwork must have at least 1 entry"))
+		errs = append(errs, field.Required(clusterNetworkPath, "must have at least 1 entry"))
 	}
 	if len(errs) == 0 && (ipv4Cluster != ipv4Service || ipv6Cluster != ipv6Service) {
-		errs = append(errs, errors.Errorf("spec.clusterNetwork and spec.serviceNetwork must either both be IPv4-only, both be IPv6-only, or both be dual-stack"))
+		errs = append(errs, field.Invalid(clusterNetworkPath, field.OmitValueType{},
+			"spec.clusterNetwork and spec.serviceNetwork must either both be IPv4-only, both be IPv6-only, or both be dual-stack"))
 	}
 
 	return errs
 }
 
 // validateMultus validates the combination of DisableMultiNetwork and AddtionalNetworks
-func validateMultus(conf *operv1.NetworkSpec) []error {
+func validateMultus(conf *operv1.NetworkSpec) field.ErrorList {
 	// DisableMultiNetwork defaults to false
 	deployMultus := true
 	if conf.DisableMultiNetwork != nil && *conf.DisableMultiNetwork {
@@ -586,109 +709,82 @@ func validateMultus(conf *operv1.NetworkSpec) []error {
 	// Additional Networks are useless without Multus, so don't let them
 	// exist without Multus and confuse things (for now)
 	if !deployMultus && len(conf.AdditionalNetworks) > 0 {
-		return []error{errors.Errorf("additional networks cannot be specified without deploying Multus")}
+		return field.ErrorList{field.Forbidden(field.NewPath("spec", "additionalNetworks"), "additional networks cannot be specified without deploying Multus")}
 	}
-	return []error{}
+	return field.ErrorList{}
 }
 
 // validateDefaultNetwork validates whichever network is specified
 // as the default network.
-func validateDefaultNetwork(conf *operv1.NetworkSpec) []error {
-	switch conf.DefaultNetwork.Type {
-	case operv1.NetworkTypeOpenShiftSDN:
-		return []error{errors.Errorf("unsupported network type %q", conf.DefaultNetwork.Type)}
-	case operv1.NetworkTypeOVNKubernetes:
-		return validateOVNKubernetes(conf)
-	default:
-		return nil
-	}
+func validateDefaultNetwork(conf *operv1.NetworkSpec) field.ErrorList {
+	return GetNetworkPlugin(conf.DefaultNetwork.Type).Validate(conf)
 }
 
 // validateMigration validates if migration path is possible
-func validateMigration(conf *operv1.NetworkSpec) []error {
-	return []error{}
+func validateMigration(conf *operv1.NetworkSpec) field.ErrorList {
+	return field.ErrorList{}
 }
 
 // renderDefaultNetwork generates the manifests corresponding to the requested
 // default network
 func renderDefaultNetwork(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.BootstrapResult, manifestDir string,
 	client cnoclient.Client, featureGates featuregates.FeatureGate) ([]*uns.Unstructured, bool, error) {
-	dn := conf.DefaultNetwork
 	if errs := validateDefaultNetwork(conf); len(errs) > 0 {
 		return nil, false, errors.Errorf("invalid Default Network configuration: %v", errs)
 	}
 
 	if conf.Migration != nil && conf.Migration.Mode == operv1.LiveNetworkMigrationMode {
 		log.Printf("Render both CNIs for live migration")
-		ovnObjs, ovnProgressing, err := renderOVNKubernetes(conf, bootstrapResult, manifestDir, client, featureGates)
-		if err != nil {
-			return nil, false, err
-		}
-		objs, sdnProgressing, err := renderOpenShiftSDN(conf, bootstrapResult, manifestDir)
+	}
+
+	objs := []*uns.Unstructured{}
+	progressing := false
+	for _, networkType := range activeNetworkTypes(conf) {
+		o, p, err := GetNetworkPlugin(networkType).Render(conf, bootstrapResult, manifestDir, client, featureGates)
 		if err != nil {
 			return nil, false, err
 		}
-		return append(objs, ovnObjs...), sdnProgressing || ovnProgressing, nil
+		// Objects of the plugins rendered later are applied first
+		objs = append(o, objs...)
+		progressing = progressing || p
 	}
+	return objs, progressing, nil
+}
 
-	switch dn.Type {
-	case operv1.NetworkTypeOpenShiftSDN:
-		return renderOpenShiftSDN(conf, bootstrapResult, manifestDir)
-	case operv1.NetworkTypeOVNKubernetes:
-		return renderOVNKubernetes(conf, bootstrapResult, manifestDir, client, featureGates)
-	default:
-		log.Printf("NOTICE: Unknown network type %s, ignoring", dn.Type)
-		return nil, false, nil
+// activeNetworkTypes returns the default network types that are deployed: both
+// OVNKubernetes and OpenShiftSDN during live migration, and the default
+// network type otherwise.
+func activeNetworkTypes(conf *operv1.NetworkSpec) []operv1.NetworkType {
+	if conf.Migration != nil && conf.Migration.Mode == operv1.LiveNetworkMigrationMode {
+		return []operv1.NetworkType{operv1.NetworkTypeOVNKubernetes, operv1.NetworkTypeOpenShiftSDN}
 	}
+	return []operv1.NetworkType{conf.DefaultNetwork.Type}
 }
 
-// renderCRDForMigration generates OpenShiftSDN CRDs when default network is OVNKubernetes,
-// and generates OVNKubernetes CRDs when default network is OpenShiftSDN.
+// renderCRDForMigration generates the CRDs of the network type that the default
+// network type is migrated to or from.
 func renderCRDForMigration(conf *operv1.NetworkSpec, manifestDir string, featureGates featuregates.FeatureGate) ([]*uns.Unstructured, error) {
-	switch conf.DefaultNetwork.Type {
-	case operv1.NetworkTypeOpenShiftSDN:
-		// When we migrate from SDN to OVNK, we must set the feature gate values so that
-		// the CRD installation can happen according to whether the feature gate is enabled or not
-		// in the cluster
-		data := render.MakeRenderData()
-		data.Data["OVN_ADMIN_NETWORK_POLICY_ENABLE"] = featureGates.Enabled(apifeatures.FeatureGateAdminNetworkPolicy)
-		data.Data["OVN_NETWORK_SEGMENTATION_ENABLE"] = featureGates.Enabled(apifeatures.FeatureGateNetworkSegmentation)
-		data.Data["OVN_OBSERVABILITY_ENABLE"] = featureGates.Enabled(apifeatures.FeatureGateOVNObservability)
-		data.Data["OVN_ROUTE_ADVERTISEMENTS_ENABLE"] = conf.DefaultNetwork.OVNKubernetesConfig != nil &&
-			conf.DefaultNetwork.OVNKubernetesConfig.RouteAdvertisements == operv1.RouteAdvertisementsEnabled
-
-		manifests, err := render.RenderTemplate(filepath.Join(manifestDir, "network/ovn-kubernetes/common/001-crd.yaml"), &data)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to render OVNKubernetes CRDs")
-		}
-		return manifests, err
-	case operv1.NetworkTypeOVNKubernetes:
-		manifests, err := render.RenderTemplate(filepath.Join(manifestDir, "network/openshift-sdn/001-crd.yaml"), &render.RenderData{})
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to render OpenShiftSDN CRDs")
-		}
-		return manifests, err
-	default:
-		log.Printf("NOTICE: Unsupported network type %s, ignoring", conf.DefaultNetwork.Type)
-		return nil, nil
-	}
+	return GetNetworkPlugin(conf.DefaultNetwork.Type).RenderMigrationCRDs(conf, manifestDir, featureGates)
 }
 
 func fillDefaultNetworkDefaults(conf, previous *operv1.NetworkSpec, hostMTU int) {
 	if conf.Migration != nil && conf.Migration.Mode == operv1.LiveNetworkMigrationMode {
 		log.Printf("fill default for both sdn and ovnkube during live migration")
-		fillOpenShiftSDNDefaults(conf, previous, hostMTU)
-		fillOVNKubernetesDefaults(conf, previous, hostMTU)
+	}
+	for _, networkType := range activeNetworkTypes(conf) {
+		GetNetworkPlugin(networkType).FillDefaults(conf, previous, hostMTU)
+	}
+
+	// Drop the configuration of the registered plugins that are not in use,
+	// unless the network is deployed by a third party
+	if _, ok := networkPlugins[conf.DefaultNetwork.Type]; !ok {
 		return
 	}
-	switch conf.DefaultNetwork.Type {
-	case operv1.NetworkTypeOpenShiftSDN:
-		fillOpenShiftSDNDefaults(conf, previous, hostMTU)
-		conf.DefaultNetwork.OVNKubernetesConfig = nil
-	case operv1.NetworkTypeOVNKubernetes:
-		fillOVNKubernetesDefaults(conf, previous, hostMTU)
-		conf.DefaultNetwork.OpenShiftSDNConfig = nil
-	default:
+	active := sets.New(activeNetworkTypes(conf)...)
+	for networkType, plugin := range networkPlugins {
+		if !active.Has(networkType) {
+			plugin.DropConfig(conf)
+		}
 	}
 }
 
@@ -705,14 +801,7 @@ func isDefaultNetworkChangeSafe(prev, next *operv1.NetworkSpec) []error {
 	}
 
 	if prev.Migration == nil || prev.Migration.NetworkType == "" {
-		switch prev.DefaultNetwork.Type {
-		case operv1.NetworkTypeOpenShiftSDN:
-			return isOpenShiftSDNChangeSafe(prev, next)
-		case operv1.NetworkTypeOVNKubernetes:
-			return isOVNKubernetesChangeSafe(prev, next)
-		default:
-			return nil
-		}
+		return GetNetworkPlugin(prev.DefaultNetwork.Type).IsChangeSafe(prev, next)
 	}
 	return nil
 }
@@ -951,8 +1040,9 @@ func renderNetworkingConsolePlugin(manifestDir string, bootstrapResult *bootstra
 	return manifests, nil
 }
 
-// registerNetworkingConsolePlugin enables console plugin for networking-console if not already enabled
-func registerNetworkingConsolePlugin(bootstrapResult *bootstrap.BootstrapResult, cl cnoclient.Client) error {
+// RegisterNetworkingConsolePlugin enables console plugin for networking-console if not already enabled.
+// It is not part of Render, which must not change the cluster.
+func RegisterNetworkingConsolePlugin(bootstrapResult *bootstrap.BootstrapResult, cl cnoclient.Client) error {
 	if !bootstrapResult.Infra.ConsolePluginCRDExists {
 		return nil
 	}
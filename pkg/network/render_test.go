@@ -274,13 +274,55 @@ func TestDisallowExpandingClusterNetworkCIDRMaskForSDN(t *testing.T) {
 	g.Expect(err).To(MatchError(ContainSubstring("network type is OpenShiftSDN. changing clusterNetwork entries is only supported for OVNKubernetes")))
 }
 
-func TestDisallowHostPrefixChangeOnOvn(t *testing.T) {
+func TestAllowHostPrefixChangeOnOvn(t *testing.T) {
 	g, infra, prev, next := setupTestInfraAndBasicRenderConfigs(t, OVNKubernetesConfig, OVNKubernetesConfig)
 
-	// Changes to the cluster network's prefix are not supported.
-	next.ClusterNetwork[0].HostPrefix = 31
+	// Nodes keep their subnet, new nodes get a subnet with the new prefix
+	infra.NodeSubnets = map[string][]string{"node-a": {"10.128.0.0/23"}}
+	next.ClusterNetwork[0].HostPrefix = 24
+	err := IsChangeSafe(prev, next, infra)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(NodesWithStaleSubnets(next, infra)).To(Equal([]string{"node-a"}))
+}
+
+func TestDisallowHostPrefixDecreaseOnOvn(t *testing.T) {
+	g, infra, prev, next := setupTestInfraAndBasicRenderConfigs(t, OVNKubernetesConfig, OVNKubernetesConfig)
+
+	// Larger node subnets could overlap with the existing ones
+	next.ClusterNetwork[0].HostPrefix = 22
 	err := IsChangeSafe(prev, next, infra)
-	g.Expect(err).To(MatchError(ContainSubstring("invalid configuration: [modifying a clusterNetwork's hostPrefix value is unsupported]")))
+	g.Expect(err).To(MatchError(ContainSubstring("modifying a clusterNetwork's hostPrefix value is unsupported, except to increase it")))
+}
+
+func TestDisallowClusterNetworkChangeExcludingNodeSubnets(t *testing.T) {
+	g, infra, prev, next := setupTestInfraAndBasicRenderConfigs(t, OVNKubernetesConfig, OVNKubernetesConfig)
+
+	infra.NodeSubnets = map[string][]string{
+		"node-a": {"10.128.0.0/23"},
+		"node-b": {"10.200.0.0/23"},
+	}
+	next.ClusterNetwork[0].CIDR = "10.128.0.0/14"
+	err := IsChangeSafe(prev, next, infra)
+	g.Expect(err).To(MatchError(ContainSubstring("the pod subnets of nodes node-b (10.200.0.0/23) are not within the clusterNetwork")))
+}
+
+func TestDisallowExpandingClusterNetworkCIDROverMachineNetwork(t *testing.T) {
+	g, infra, prev, next := setupTestInfraAndBasicRenderConfigs(t, OVNKubernetesConfig, OVNKubernetesConfig)
+
+	infra.MachineNetworks = []string{"10.130.0.0/16"}
+	next.ClusterNetwork[0].CIDR = "10.128.0.0/14"
+	err := IsChangeSafe(prev, next, infra)
+	g.Expect(err).To(MatchError(ContainSubstring("clusterNetwork overlaps with the machineNetwork 10.130.0.0/16")))
+}
+
+func TestDisallowExpandingClusterNetworkCIDROverDefaultJoinSubnet(t *testing.T) {
+	g, infra, prev, next := setupTestInfraAndBasicRenderConfigs(t, OVNKubernetesConfig, OVNKubernetesConfig)
+
+	// The default join subnet is 100.64.0.0/16
+	prev.ClusterNetwork[0].CIDR = "100.0.0.0/14"
+	next.ClusterNetwork[0].CIDR = "100.0.0.0/9"
+	err := IsChangeSafe(prev, next, infra)
+	g.Expect(err).To(MatchError(ContainSubstring("clusterNetwork overlaps with the internalJoinSubnet 100.64.0.0/16")))
 }
 
 func TestDisallowShrinkingClusterNetworkCIDRMaskForOVN(t *testing.T) {
@@ -655,3 +697,43 @@ func Test_renderAdditionalRoutingCapabilities(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateFields(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	crd := OVNKubernetesConfig.DeepCopy()
+	config := &crd.Spec
+	g.Expect(ValidateFields(config)).To(BeEmpty())
+
+	config.ServiceNetwork = []string{"172.30.0.0/33"}
+	config.ClusterNetwork = append(config.ClusterNetwork, operv1.ClusterNetworkEntry{CIDR: "192.168.0.0/24", HostPrefix: 20})
+	config.KubeProxyConfig = &operv1.ProxyConfig{BindAddress: "1.2.3.4"}
+	disable := true
+	config.DisableMultiNetwork = &disable
+	config.AdditionalNetworks = []operv1.AdditionalNetworkDefinition{{Type: operv1.NetworkTypeRaw, Name: "net"}}
+
+	errs := ValidateFields(config)
+	fields := []string{}
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	g.Expect(fields).To(ConsistOf(
+		"spec.serviceNetwork[0]",
+		"spec.clusterNetwork[2].hostPrefix",
+		// the OVNKubernetes validation checks the service network as well
+		"spec.serviceNetwork[0]",
+		"spec.additionalNetworks",
+		"spec.kubeProxyConfig",
+	))
+
+	// The errors of the default network configuration have precise paths
+	config = OVNKubernetesConfig.DeepCopy().Spec.DeepCopy()
+	mtu := uint32(70000)
+	config.DefaultNetwork.OVNKubernetesConfig.MTU = &mtu
+	errs = ValidateFields(config)
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Field).To(Equal("spec.defaultNetwork.ovnKubernetesConfig.mtu"))
+	g.Expect(errs[0].Error()).To(Equal("spec.defaultNetwork.ovnKubernetesConfig.mtu: Invalid value: 70000: invalid MTU 70000, must be between 576 and 65536"))
+
+	g.Expect(Validate(config)).To(MatchError("invalid configuration: [spec.defaultNetwork.ovnKubernetesConfig.mtu: Invalid value: 70000: invalid MTU 70000, must be between 576 and 65536]"))
+}
@@ -0,0 +1,117 @@
+package network
+
+import (
+	"net"
+	"path/filepath"
+	"strings"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/pkg/errors"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilnet "k8s.io/utils/net"
+
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	iputil "github.com/openshift/cluster-network-operator/pkg/util/ip"
+)
+
+// ValidateServiceNetworkMigration checks that the service network of conf can
+// be migrated from the original CIDRs to the target ones. The target must have
+// the same IP families as the original, in the same order, and must not
+// overlap with the original or the machine network, since both ranges are in
+// use during the migration.
+func ValidateServiceNetworkMigration(conf *operv1.NetworkSpec, original, target []string, infraStatus *bootstrap.InfraStatus) error {
+	if conf.DefaultNetwork.Type != operv1.NetworkTypeOVNKubernetes {
+		return errors.Errorf("network type is %s. service network migration is only supported for OVNKubernetes", conf.DefaultNetwork.Type)
+	}
+	if infraStatus.HostedControlPlane != nil {
+		return errors.Errorf("service network migration is not supported with a hosted control plane")
+	}
+	if len(original) != len(target) {
+		return errors.Errorf("the target service network %s must have as many CIDRs as the original service network %s",
+			strings.Join(target, ","), strings.Join(original, ","))
+	}
+	for i := range target {
+		if utilnet.IsIPv6CIDRString(target[i]) != utilnet.IsIPv6CIDRString(original[i]) {
+			return errors.Errorf("the target service network %s must have the IP families of the original service network %s, in the same order",
+				strings.Join(target, ","), strings.Join(original, ","))
+		}
+	}
+
+	spec := conf.DeepCopy()
+	spec.ServiceNetwork = target
+	if errs := ValidateFields(spec); len(errs) > 0 {
+		return errors.Errorf("invalid target service network: %v", errs.ToAggregate())
+	}
+
+	others := append([]string{}, original...)
+	for _, mnet := range infraStatus.MachineNetworks {
+		// The default route of a machine network is not an overlap
+		if mnet != "0.0.0.0/0" && mnet != "::/0" {
+			others = append(others, mnet)
+		}
+	}
+	for _, other := range others {
+		_, otherCIDR, err := net.ParseCIDR(other)
+		if err != nil {
+			continue
+		}
+		pool := iputil.IPPool{}
+		for _, snet := range target {
+			_, cidr, _ := net.ParseCIDR(snet)
+			// ValidateFields already checked that the target CIDRs are valid and don't overlap
+			_ = pool.Add(*cidr)
+		}
+		if err := pool.Add(*otherCIDR); err != nil {
+			return errors.Errorf("the target service network overlaps with %s: %v", other, err)
+		}
+	}
+	return nil
+}
+
+// ServiceCIDRName returns the name of the ServiceCIDR object rendered for an
+// additional service network, e.g. openshift-service-network-172-31-0-0-16.
+func ServiceCIDRName(cidr string) string {
+	parts := strings.FieldsFunc(cidr, func(r rune) bool { return r == '.' || r == ':' || r == '/' })
+	return "openshift-service-network-" + strings.Join(parts, "-")
+}
+
+// renderedServiceNetworks returns the service network CIDRs that the default
+// network is configured with: spec.serviceNetwork, followed by the additional
+// service networks of an in-progress migration.
+func renderedServiceNetworks(conf *operv1.NetworkSpec, bootstrapResult *bootstrap.BootstrapResult) []string {
+	serviceNetworks := append([]string{}, conf.ServiceNetwork...)
+	return append(serviceNetworks, bootstrapResult.ServiceNetworkMigration.AdditionalServiceNetworks...)
+}
+
+// renderServiceNetworkMigration renders a ServiceCIDR for each additional
+// service network the apiserver may allocate cluster IPs from during a service
+// network migration, and the Services that keep the original cluster IPs of
+// the apiserver and cluster DNS Services. Once the migration is done, nothing
+// is rendered and these objects are deleted.
+func renderServiceNetworkMigration(bootstrapResult *bootstrap.BootstrapResult, manifestDir string) ([]*uns.Unstructured, error) {
+	migration := bootstrapResult.ServiceNetworkMigration
+	cidrs := migration.ServiceCIDRs
+	if len(cidrs) == 0 && migration.OriginalKubernetesService == nil && migration.OriginalDNSServiceIP == "" {
+		return nil, nil
+	}
+
+	type serviceCIDR struct {
+		Name string
+		CIDR string
+	}
+	serviceCIDRs := []serviceCIDR{}
+	for _, cidr := range cidrs {
+		serviceCIDRs = append(serviceCIDRs, serviceCIDR{Name: ServiceCIDRName(cidr), CIDR: cidr})
+	}
+
+	data := render.MakeRenderData()
+	data.Data["ServiceCIDRs"] = serviceCIDRs
+	data.Data["OriginalKubernetesService"] = migration.OriginalKubernetesService
+	data.Data["OriginalDNSServiceIP"] = migration.OriginalDNSServiceIP
+	manifests, err := render.RenderDir(filepath.Join(manifestDir, "network", "service-network-migration"), &data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render service network migration manifests")
+	}
+	return manifests, nil
+}
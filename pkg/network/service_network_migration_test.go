@@ -0,0 +1,180 @@
+package network
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
+	cnofake "github.com/openshift/cluster-network-operator/pkg/client/fake"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+)
+
+func TestValidateServiceNetworkMigration(t *testing.T) {
+	testCases := []struct {
+		name        string
+		networkType operv1.NetworkType
+		original    []string
+		target      []string
+		infra       bootstrap.InfraStatus
+		err         string
+	}{
+		{
+			name:     "valid",
+			original: []string{"172.30.0.0/16"},
+			target:   []string{"172.31.0.0/16"},
+		},
+		{
+			name:     "valid dual-stack",
+			original: []string{"172.30.0.0/16", "fd02::/112"},
+			target:   []string{"172.31.0.0/16", "fd03::/112"},
+		},
+		{
+			name:        "not OVNKubernetes",
+			networkType: operv1.NetworkTypeOpenShiftSDN,
+			original:    []string{"172.30.0.0/16"},
+			target:      []string{"172.31.0.0/16"},
+			err:         "service network migration is only supported for OVNKubernetes",
+		},
+		{
+			name:     "different number of CIDRs",
+			original: []string{"172.30.0.0/16"},
+			target:   []string{"172.31.0.0/16", "fd03::/112"},
+			err:      "must have as many CIDRs as the original service network",
+		},
+		{
+			name:     "different IP family",
+			original: []string{"172.30.0.0/16"},
+			target:   []string{"fd03::/112"},
+			err:      "must have the IP families of the original service network",
+		},
+		{
+			name:     "overlaps with the cluster network",
+			original: []string{"172.30.0.0/16"},
+			target:   []string{"10.128.0.0/16"},
+			err:      "invalid target service network",
+		},
+		{
+			name:     "overlaps with the original",
+			original: []string{"172.30.0.0/16"},
+			target:   []string{"172.30.0.0/15"},
+			err:      "the target service network overlaps with 172.30.0.0/16",
+		},
+		{
+			name:     "overlaps with the machine network",
+			original: []string{"172.30.0.0/16"},
+			target:   []string{"192.168.0.0/16"},
+			infra:    bootstrap.InfraStatus{MachineNetworks: []string{"192.168.10.0/24"}},
+			err:      "the target service network overlaps with 192.168.10.0/24",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			conf := OVNKubernetesConfig.Spec.DeepCopy()
+			conf.ServiceNetwork = tc.original
+			if len(tc.original) == 2 {
+				conf.ClusterNetwork = append(conf.ClusterNetwork, operv1.ClusterNetworkEntry{CIDR: "fd01::/48", HostPrefix: 64})
+			}
+			if tc.networkType != "" {
+				conf.DefaultNetwork.Type = tc.networkType
+			}
+			err := ValidateServiceNetworkMigration(conf, tc.original, tc.target, &tc.infra)
+			if tc.err == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+			} else {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.err)))
+			}
+		})
+	}
+}
+
+func TestRenderServiceNetworkMigration(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	bootstrapResult := fakeBootstrapResult()
+	objs, err := renderServiceNetworkMigration(bootstrapResult, manifestDir)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(objs).To(BeEmpty())
+
+	bootstrapResult.ServiceNetworkMigration.ServiceCIDRs = []string{"172.31.0.0/16", "fd03::/112"}
+	objs, err = renderServiceNetworkMigration(bootstrapResult, manifestDir)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(objs).To(HaveLen(2))
+	g.Expect(objs[0]).To(HaveKubernetesID("ServiceCIDR", "", "openshift-service-network-172-31-0-0-16"))
+	g.Expect(objs[1]).To(HaveKubernetesID("ServiceCIDR", "", "openshift-service-network-fd03-112"))
+	cidrs, _, err := uns.NestedStringSlice(objs[1].Object, "spec", "cidrs")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cidrs).To(Equal([]string{"fd03::/112"}))
+
+	// The original cluster IPs of the apiserver and cluster DNS Services
+	bootstrapResult.ServiceNetworkMigration.ServiceCIDRs = []string{"172.31.0.0/16"}
+	bootstrapResult.ServiceNetworkMigration.OriginalKubernetesService = &bootstrap.OriginalKubernetesService{
+		ClusterIP: "172.30.0.1", AddressType: "IPv4", Addresses: []string{"10.0.0.1", "10.0.0.2"}, Port: 6443,
+	}
+	bootstrapResult.ServiceNetworkMigration.OriginalDNSServiceIP = "172.30.0.10"
+	objs, err = renderServiceNetworkMigration(bootstrapResult, manifestDir)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(objs).To(HaveLen(4))
+	g.Expect(objs).To(ContainElement(HaveKubernetesID("Service", "openshift-dns", "dns-default-original")))
+	g.Expect(objs).To(ContainElement(HaveKubernetesID("Service", "default", "kubernetes-original")))
+	g.Expect(objs).To(ContainElement(HaveKubernetesID("EndpointSlice", "default", "kubernetes-original")))
+	for _, obj := range objs {
+		if obj.GetKind() == "EndpointSlice" {
+			endpoints, _, err := uns.NestedSlice(obj.Object, "endpoints")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(endpoints).To(HaveLen(2))
+			g.Expect(obj.GetLabels()).To(HaveKeyWithValue("kubernetes.io/service-name", "kubernetes-original"))
+		}
+		if obj.GetKind() == "Service" {
+			clusterIP, _, err := uns.NestedString(obj.Object, "spec", "clusterIP")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect([]string{"172.30.0.1", "172.30.0.10"}).To(ContainElement(clusterIP))
+		}
+	}
+}
+
+func TestRenderOVNKubernetesAdditionalServiceNetworks(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	crd := OVNKubernetesConfig.DeepCopy()
+	config := &crd.Spec
+	fillDefaults(config, nil)
+
+	bootstrapResult := fakeBootstrapResult()
+	bootstrapResult.OVN = bootstrap.OVNBootstrapResult{
+		ControlPlaneReplicaCount: 3,
+		OVNKubernetesConfig: &bootstrap.OVNConfigBoostrapResult{
+			DpuHostModeLabel:     OVN_NODE_SELECTOR_DEFAULT_DPU_HOST,
+			DpuModeLabel:         OVN_NODE_SELECTOR_DEFAULT_DPU,
+			SmartNicModeLabel:    OVN_NODE_SELECTOR_DEFAULT_SMART_NIC,
+			MgmtPortResourceName: "",
+			HyperShiftConfig: &bootstrap.OVNHyperShiftBootstrapResult{
+				Enabled: false,
+			},
+		},
+	}
+	bootstrapResult.ServiceNetworkMigration.AdditionalServiceNetworks = []string{"172.31.0.0/16"}
+
+	objs, _, err := renderOVNKubernetes(config, bootstrapResult, manifestDirOvn, cnofake.NewFakeClient(), getDefaultFeatureGates())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	found := 0
+	for _, obj := range objs {
+		switch {
+		case obj.GetKind() == "ConfigMap" && obj.GetName() == "ovnkube-config":
+			conf, _, err := uns.NestedString(obj.Object, "data", "ovnkube.conf")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(strings.Contains(conf, `service-cidrs="172.30.0.0/16,172.31.0.0/16"`)).To(BeTrue(), conf)
+			found++
+		case obj.GetName() == "ovnkube-node" || obj.GetName() == "ovnkube-control-plane":
+			g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(names.ServiceNetworkCIDRsAnnotation, "172.30.0.0/16,172.31.0.0/16"))
+			found++
+		}
+	}
+	g.Expect(found).To(Equal(3))
+}
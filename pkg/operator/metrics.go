@@ -0,0 +1,23 @@
+package operator
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// servingCertDir is where the metrics-tls secret, which is signed by the
+// service CA, is mounted. If it is missing, the metrics server falls back to a
+// self-signed certificate.
+const servingCertDir = "/var/run/secrets/serving-cert"
+
+// metricsServerOptions returns the options for the controller-runtime metrics
+// server. The metrics are served over https, and scrapers are authenticated and
+// authorized against the apiserver. A bindAddress of "0" disables the server.
+func metricsServerOptions(bindAddress string) metricsserver.Options {
+	return metricsserver.Options{
+		BindAddress:    bindAddress,
+		SecureServing:  true,
+		CertDir:        servingCertDir,
+		FilterProvider: filters.WithAuthenticationAndAuthorization,
+	}
+}
@@ -22,7 +22,6 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
-	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
 	"github.com/openshift/cluster-network-operator/pkg/controller"
@@ -48,7 +47,7 @@ type Operator struct {
 
 var logger = klog.NewKlogr()
 
-func RunOperator(ctx context.Context, controllerConfig *controllercmd.ControllerContext, inClusterClientName string, extraClusters map[string]string) error {
+func RunOperator(ctx context.Context, controllerConfig *controllercmd.ControllerContext, inClusterClientName string, extraClusters map[string]string, metricsBindAddress string, webhookPort int) error {
 	o := &Operator{}
 
 	var err error
@@ -66,7 +65,7 @@ func RunOperator(ctx context.Context, controllerConfig *controllercmd.Controller
 		MapperProvider: func(cfg *rest.Config, httpClient *http.Client) (meta.RESTMapper, error) {
 			return o.client.Default().RESTMapper(), nil
 		},
-		Metrics: metricsserver.Options{BindAddress: "0"},
+		Metrics: metricsServerOptions(metricsBindAddress),
 		Logger:  klog.Background(),
 	})
 	if err != nil {
@@ -147,6 +146,13 @@ func RunOperator(ctx context.Context, controllerConfig *controllercmd.Controller
 		return fmt.Errorf("failed to add controllers to manager: %w", err)
 	}
 
+	// Serve the admission webhooks
+	if webhookPort != 0 {
+		if err := o.manager.Add(newWebhookServer(webhookPort, o.client)); err != nil {
+			return fmt.Errorf("failed to add the webhook server to manager: %w", err)
+		}
+	}
+
 	// Initialize individual (non-controller-runtime) controllers
 
 	// logLevelController reacts to changes in the operator spec loglevel
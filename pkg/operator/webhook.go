@@ -0,0 +1,117 @@
+package operator
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operv1 "github.com/openshift/api/operator/v1"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+	"github.com/openshift/cluster-network-operator/pkg/network"
+)
+
+// webhookCertDir is where the webhook-tls secret, which is signed by the
+// service CA, is mounted.
+const webhookCertDir = "/var/run/secrets/webhook-cert"
+
+// networkValidationPath is the path of the webhook that validates the operator
+// configuration. It must match the cluster-network-operator
+// ValidatingWebhookConfiguration.
+const networkValidationPath = "/validate-operator-network"
+
+// webhookServer serves the admission webhooks of the operator. The serving
+// certificate is signed by the service CA, which only runs once the network is
+// up, so the server doesn't start until the certificate exists. Until then, the
+// webhook is ignored by the apiserver.
+type webhookServer struct {
+	webhook.Server
+}
+
+// newWebhookServer returns a webhook server listening on port, that validates
+// the operator configuration.
+func newWebhookServer(port int, client cnoclient.Client) *webhookServer {
+	server := webhook.NewServer(webhook.Options{
+		Port:    port,
+		CertDir: webhookCertDir,
+	})
+	server.Register(networkValidationPath, &webhook.Admission{
+		Handler: &networkValidator{
+			client:  client,
+			decoder: admission.NewDecoder(client.Default().Scheme()),
+		},
+	})
+	return &webhookServer{Server: server}
+}
+
+// Start waits for the serving certificate and starts the server.
+func (s *webhookServer) Start(ctx context.Context) error {
+	certFile := filepath.Join(webhookCertDir, "tls.crt")
+	err := wait.PollUntilContextCancel(ctx, 10*time.Second, true, func(context.Context) (bool, error) {
+		if _, err := os.Stat(certFile); err != nil {
+			klog.V(2).Infof("Waiting for the webhook serving certificate %s: %v", certFile, err)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		// the context is done
+		return nil
+	}
+	klog.Infof("Starting the admission webhook server")
+	return s.Server.Start(ctx)
+}
+
+// networkValidator rejects operator configurations that the operconfig
+// controller would not apply because they are invalid.
+type networkValidator struct {
+	client  cnoclient.Client
+	decoder admission.Decoder
+}
+
+func (v *networkValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	// The operator ignores any other Network
+	if req.Name != names.OPERATOR_CONFIG {
+		return admission.Allowed("")
+	}
+	operConfig := &operv1.Network{}
+	if err := v.decoder.Decode(req, operConfig); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// Mirror what the operconfig controller does before validating: the
+	// networks are copied from the cluster configuration.
+	clusterConfig := &configv1.Network{}
+	err := v.client.Default().CRClient().Get(ctx, types.NamespacedName{Name: names.CLUSTER_CONFIG}, clusterConfig)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return admission.Allowed("").WithWarnings("could not validate the operator configuration: " + err.Error())
+	}
+	if err == nil {
+		if _, ok := clusterConfig.Annotations[names.NetworkTypeMigrationAnnotation]; !ok && network.ValidateClusterConfig(clusterConfig, v.client) == nil {
+			network.MergeClusterConfig(&operConfig.Spec, clusterConfig.Spec)
+		}
+	}
+	network.DeprecatedCanonicalize(&operConfig.Spec)
+
+	if errs := network.ValidateFields(&operConfig.Spec); len(errs) > 0 {
+		invalid := apierrors.NewInvalid(operv1.GroupVersion.WithKind("Network").GroupKind(), operConfig.Name, errs)
+		return admission.Response{
+			AdmissionResponse: admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result:  &invalid.ErrStatus,
+			},
+		}
+	}
+	return admission.Allowed("")
+}
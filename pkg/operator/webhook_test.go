@@ -0,0 +1,63 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openshift/cluster-network-operator/pkg/client/fake"
+	"github.com/openshift/cluster-network-operator/pkg/names"
+)
+
+func networkRequest(g *WithT, network *operv1.Network) admission.Request {
+	network.TypeMeta = metav1.TypeMeta{APIVersion: operv1.GroupVersion.String(), Kind: "Network"}
+	raw, err := json.Marshal(network)
+	g.Expect(err).NotTo(HaveOccurred())
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Name:      network.Name,
+		Operation: admissionv1.Update,
+		Object:    runtime.RawExtension{Raw: raw},
+	}}
+}
+
+func TestNetworkValidator(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(operv1.Install(scheme)).To(Succeed())
+	v := &networkValidator{client: fake.NewFakeClient(), decoder: admission.NewDecoder(scheme)}
+
+	network := &operv1.Network{
+		ObjectMeta: metav1.ObjectMeta{Name: names.OPERATOR_CONFIG},
+		Spec: operv1.NetworkSpec{
+			ServiceNetwork: []string{"172.30.0.0/16"},
+			ClusterNetwork: []operv1.ClusterNetworkEntry{{CIDR: "10.128.0.0/14", HostPrefix: 23}},
+			DefaultNetwork: operv1.DefaultNetworkDefinition{Type: operv1.NetworkTypeOVNKubernetes},
+		},
+	}
+	resp := v.Handle(context.TODO(), networkRequest(g, network))
+	g.Expect(resp.Allowed).To(BeTrue())
+
+	network.Spec.ClusterNetwork[0].HostPrefix = 12
+	resp = v.Handle(context.TODO(), networkRequest(g, network))
+	g.Expect(resp.Allowed).To(BeFalse())
+	g.Expect(resp.Result.Reason).To(Equal(metav1.StatusReasonInvalid))
+	g.Expect(resp.Result.Details.Causes).To(ContainElement(metav1.StatusCause{
+		Type:    metav1.CauseTypeFieldValueInvalid,
+		Message: "Invalid value: 12: is larger than its cidr 10.128.0.0/14",
+		Field:   "spec.clusterNetwork[0].hostPrefix",
+	}))
+
+	// Other Networks are ignored by the operator
+	network.Name = "other"
+	resp = v.Handle(context.TODO(), networkRequest(g, network))
+	g.Expect(resp.Allowed).To(BeTrue())
+}
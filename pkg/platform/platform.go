@@ -2,10 +2,12 @@ package platform
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 
+	"github.com/ghodss/yaml"
 	configv1 "github.com/openshift/api/config/v1"
 	mcfgv1 "github.com/openshift/api/machineconfiguration/v1"
 	"github.com/openshift/cluster-network-operator/pkg/bootstrap"
@@ -202,6 +204,75 @@ func InfraStatus(client cnoclient.Client) (*bootstrap.InfraStatus, error) {
 	return res, nil
 }
 
+// MachineNetworks returns the machine network CIDRs of the install-config
+// stored in the kube-system/cluster-config-v1 ConfigMap. Clusters that were
+// not installed with an install-config (e.g. HyperShift) have none, and a
+// nil clusterConfig.
+func MachineNetworks(clusterConfig *corev1.ConfigMap) ([]string, error) {
+	if clusterConfig == nil {
+		return nil, nil
+	}
+
+	ic := struct {
+		Networking struct {
+			MachineCIDR    string `json:"machineCIDR"`
+			MachineNetwork []struct {
+				CIDR string `json:"cidr"`
+			} `json:"machineNetwork,omitempty"`
+		} `json:"networking"`
+	}{}
+	if err := yaml.Unmarshal([]byte(clusterConfig.Data["install-config"]), &ic); err != nil {
+		return nil, fmt.Errorf("failed to decode the install-config: %w", err)
+	}
+
+	cidrs := []string{}
+	if ic.Networking.MachineCIDR != "" {
+		cidrs = append(cidrs, ic.Networking.MachineCIDR)
+	}
+	for _, mn := range ic.Networking.MachineNetwork {
+		cidrs = append(cidrs, mn.CIDR)
+	}
+	return cidrs, nil
+}
+
+// NodeSubnets returns the pod subnets that ovn-kubernetes allocated to each
+// node, from the names.OVNNodeSubnetsAnnotation annotation. Nodes without the
+// annotation are omitted.
+func NodeSubnets(nodes []corev1.Node) map[string][]string {
+	res := map[string][]string{}
+	for _, node := range nodes {
+		annotation, ok := node.Annotations[names.OVNNodeSubnetsAnnotation]
+		if !ok {
+			continue
+		}
+		subnets, err := parseNodeSubnets(annotation)
+		if err != nil {
+			klog.Warningf("Ignoring invalid %s annotation on node %s: %v", names.OVNNodeSubnetsAnnotation, node.Name, err)
+			continue
+		}
+		res[node.Name] = subnets
+	}
+	return res
+}
+
+// parseNodeSubnets parses the subnets of the default network from the value of
+// the names.OVNNodeSubnetsAnnotation annotation. Older ovn-kubernetes versions
+// store a single subnet rather than a list.
+func parseNodeSubnets(annotation string) ([]string, error) {
+	list := map[string][]string{}
+	if err := json.Unmarshal([]byte(annotation), &list); err == nil {
+		return list["default"], nil
+	}
+	single := map[string]string{}
+	if err := json.Unmarshal([]byte(annotation), &single); err != nil {
+		return nil, err
+	}
+	if subnet, ok := single["default"]; ok {
+		return []string{subnet}, nil
+	}
+	return nil, nil
+}
+
 func findIPsecMachineConfigsWithLabel(client cnoclient.Client, mcLabel labels.Set) ([]*mcfgv1.MachineConfig, error) {
 	machineConfigs := &mcfgv1.MachineConfigList{}
 	err := client.Default().CRClient().List(context.TODO(), machineConfigs, &crclient.ListOptions{LabelSelector: mcLabel.AsSelector()})
@@ -18,6 +18,7 @@ const OVN_INTERCONNECT_CONFIGMAP_NAME = "ovn-interconnect-configuration"
 const OVN_NAMESPACE = "openshift-ovn-kubernetes"
 const OVN_CONTROL_PLANE = "ovnkube-control-plane"
 const OVN_NODE = "ovnkube-node"
+const OVN_NODE_CANARY = "ovnkube-node-canary"
 const OVN_CONTROLLER = "ovnkube-controller"
 const SDN_NAMESPACE = "openshift-sdn"
 const MTU_CM_NAMESPACE = "openshift-network-operator"